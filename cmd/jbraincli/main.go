@@ -23,6 +23,8 @@ func main() {
 			commands.NewMemoryCommand(),
 			commands.NewRememberCommand(), // Direct remember command
 			commands.NewReportsCommand(),
+			commands.NewStandupCommand(),
+			commands.NewDigestCommand(),
 			commands.NewTaskMemoriesCommand(),
 			commands.NewMemoryTasksCommand(),
 			commands.NewLinkCommand(),
@@ -33,7 +35,15 @@ func main() {
 			commands.NewContextPackCommand(), // Context packs (bundles of contexts)
 			commands.NewSubtaskCommand(),
 			commands.NewOverviewCommand(),
+			commands.NewExportCommand(),
+			commands.NewImportCommand(),
+			commands.NewScanCommand(),
+			commands.NewGitCommand(),
+			commands.NewGraphCommand(),
+			commands.NewAttachCommand(),
+			commands.NewAttachmentsCommand(),
 			commands.NewMcpCommand(),
+			commands.NewAuditCommand(),
 			commands.NewConfigCommand(),
 			commands.NewGeminiKeyCommand(),
 		},
@@ -9,7 +9,9 @@ import (
 
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/terzigolu/josepshbrain-go/handlers"
+	"github.com/terzigolu/josepshbrain-go/internal/attach"
 	"github.com/terzigolu/josepshbrain-go/repository"
 
 	_ "github.com/lib/pq"
@@ -46,6 +48,60 @@ func main() {
 		}
 	})
 
+	// --- Attachment Endpoints ---
+	// Uploaded files are stored by content hash under ATTACHMENTS_DIR
+	attachmentDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentDir == "" {
+		attachmentDir = "attachments"
+	}
+	attachmentHandler := handlers.NewAttachmentHandler(repository.NewAttachmentRepository(db), attach.New(attachmentDir))
+
+	http.HandleFunc("/attachments", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			attachmentHandler.List(w, r)
+		case http.MethodPost:
+			attachmentHandler.Upload(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	// /attachments/{id} and /attachments/{id}/content
+	http.HandleFunc("/attachments/", func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/attachments/")
+		idStr, sub, _ := strings.Cut(rest, "/")
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+			return
+		}
+		switch {
+		case sub == "content" && r.Method == http.MethodGet:
+			attachmentHandler.Content(w, r, id)
+		case sub == "" && r.Method == http.MethodGet:
+			attachmentHandler.Get(w, r, id)
+		case sub == "" && r.Method == http.MethodDelete:
+			attachmentHandler.Delete(w, r, id)
+		case sub != "content" && sub != "":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// --- Calendar Feed ---
+	// Only served when CALENDAR_TOKEN is set; subscribe with /v1/calendar.ics?token=...
+	if token := os.Getenv("CALENDAR_TOKEN"); token != "" {
+		calendarHandler := handlers.NewCalendarHandler(repository.NewCalendarRepository(db), token)
+		http.HandleFunc("/v1/calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			calendarHandler.Feed(w, r)
+		})
+	}
+
 	// --- Task Notes Endpoints ---
 	http.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
@@ -0,0 +1,226 @@
+// attachment_handler.go
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/terzigolu/josepshbrain-go/internal/attach"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/repository"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentHandler serves /attachments. Responses use the same shapes as
+// the hosted API, so `ramorie attach` works against this server directly.
+type AttachmentHandler struct {
+	Repo  *repository.AttachmentRepository
+	Store *attach.Store
+
+	// blobs is read-locked by uploads and locked by whatever removes stored
+	// content, so content is never removed while an upload is reusing it
+	blobs sync.RWMutex
+}
+
+func NewAttachmentHandler(repo *repository.AttachmentRepository, store *attach.Store) *AttachmentHandler {
+	return &AttachmentHandler{Repo: repo, Store: store}
+}
+
+func writeAttachmentJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Upload handles POST /attachments (multipart: owner_type, owner_id, file)
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxAttachmentBytes+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, attach.ErrTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	ownerType := r.FormValue("owner_type")
+	ownerID, err := uuid.Parse(r.FormValue("owner_id"))
+	if err != nil {
+		http.Error(w, "Invalid owner ID", http.StatusBadRequest)
+		return
+	}
+	projectID, err := h.Repo.OwnerProject(r.Context(), ownerType, ownerID)
+	if errors.Is(err, repository.ErrUnknownOwner) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a, created, sum, err := h.put(r.Context(), file, &models.Attachment{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		ProjectID: projectID,
+		Name:      header.Filename,
+	})
+	if err != nil {
+		// Content written for this upload is not left behind without an attachment
+		if sum != "" {
+			h.removeUnused(r.Context(), sum)
+		}
+		if errors.Is(err, attach.ErrTooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeAttachmentJSON(w, status, a)
+}
+
+// put stores the content of an upload and creates its attachment. It returns
+// the SHA-256 once the content is stored, also when creating the attachment
+// fails. Deletes wait for it, so content it finds already stored is not
+// removed before the attachment referencing it exists.
+func (h *AttachmentHandler) put(ctx context.Context, file io.Reader, a *models.Attachment) (*models.Attachment, bool, string, error) {
+	h.blobs.RLock()
+	defer h.blobs.RUnlock()
+
+	sum, size, err := h.Store.Put(file)
+	if err != nil {
+		return nil, false, "", err
+	}
+	stored, err := h.Store.Open(sum)
+	if err != nil {
+		return nil, false, sum, err
+	}
+	content, err := io.ReadAll(stored)
+	stored.Close()
+	if err != nil {
+		return nil, false, sum, err
+	}
+
+	a.MimeType = attach.ContentType(a.Name, content[:min(len(content), 512)])
+	a.Size = size
+	a.SHA256 = sum
+	// Text attachments are indexed so recall can search them
+	text := ""
+	if attach.IsText(a.MimeType) {
+		text = strings.ToValidUTF8(string(content), "")
+	}
+	a, created, err := h.Repo.CreateAttachment(ctx, a, text)
+	return a, created, sum, err
+}
+
+// removeUnused removes stored content no attachment references
+func (h *AttachmentHandler) removeUnused(ctx context.Context, sum string) {
+	h.blobs.Lock()
+	defer h.blobs.Unlock()
+	if used, err := h.Repo.ContentInUse(ctx, sum); err == nil && !used {
+		h.Store.Remove(sum)
+	}
+}
+
+// List handles GET /attachments?owner_type=&owner_id=&project_id=&search=
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repository.AttachmentFilter{OwnerType: q.Get("owner_type"), Search: q.Get("search")}
+	for key, dst := range map[string]**uuid.UUID{"owner_id": &filter.OwnerID, "project_id": &filter.ProjectID} {
+		if q.Get(key) == "" {
+			continue
+		}
+		id, err := uuid.Parse(q.Get(key))
+		if err != nil {
+			http.Error(w, "Invalid "+key, http.StatusBadRequest)
+			return
+		}
+		*dst = &id
+	}
+	attachments, err := h.Repo.ListAttachments(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeAttachmentJSON(w, http.StatusOK, attachments)
+}
+
+// Get handles GET /attachments/{id}
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	a, err := h.Repo.GetAttachment(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeAttachmentJSON(w, http.StatusOK, a)
+}
+
+// Content handles GET /attachments/{id}/content
+func (h *AttachmentHandler) Content(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	a, err := h.Repo.GetAttachment(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	f, err := h.Store.Open(a.SHA256)
+	if err != nil {
+		http.Error(w, "attachment content is missing", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", a.MimeType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(a.Name, `"`, "")+`"`)
+	http.ServeContent(w, r, a.Name, a.CreatedAt, f)
+}
+
+// Delete handles DELETE /attachments/{id}; content no other attachment uses is removed
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	a, err := h.Repo.GetAttachment(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "attachment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Held until the content is removed, so an upload of the same content
+	// cannot reuse it in between
+	h.blobs.Lock()
+	defer h.blobs.Unlock()
+	shared, err := h.Repo.DeleteAttachment(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !shared {
+		h.Store.Remove(a.SHA256)
+	}
+	writeAttachmentJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
@@ -0,0 +1,61 @@
+// calendar_handler.go
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/ical"
+	"github.com/terzigolu/josepshbrain-go/repository"
+)
+
+// CalendarHandler serves the task calendar feed. Calendar apps cannot send
+// headers when subscribing, so the token may also be given as ?token=.
+type CalendarHandler struct {
+	Repo  *repository.CalendarRepository
+	Token string
+}
+
+func NewCalendarHandler(repo *repository.CalendarRepository, token string) *CalendarHandler {
+	return &CalendarHandler{Repo: repo, Token: token}
+}
+
+// Feed handles GET /v1/calendar.ics[?token=...&project_id=...&due_only=1]
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+		http.Error(w, "invalid or missing calendar token", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	var projectID *uuid.UUID
+	if q.Get("project_id") != "" {
+		id, err := uuid.Parse(q.Get("project_id"))
+		if err != nil {
+			http.Error(w, "Invalid project_id", http.StatusBadRequest)
+			return
+		}
+		projectID = &id
+	}
+	dueOnly := q.Get("due_only") == "1" || q.Get("due_only") == "true"
+
+	todos, err := h.Repo.ListTodos(r.Context(), projectID, dueOnly)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="ramorie.ics"`)
+	ical.Calendar{Name: "ramorie", Todos: todos}.Encode(w, time.Now())
+}
+
+func (h *CalendarHandler) authorized(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) == 1
+}
@@ -2,15 +2,18 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/terzigolu/josepshbrain-go/internal/config"
 	"github.com/terzigolu/josepshbrain-go/internal/models"
 )
@@ -23,6 +26,23 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	APIKey     string
+
+	// ctx cancels in-flight requests; see WithContext
+	ctx context.Context
+}
+
+// WithContext returns a shallow copy of the client whose requests are bound to ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 func (c *Client) Request(method, endpoint string, body interface{}) ([]byte, error) {
@@ -75,7 +95,7 @@ func (c *Client) makeAuthRequest(method, endpoint string, body interface{}) ([]b
 		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(c.context(), method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -102,24 +122,27 @@ func (c *Client) makeAuthRequest(method, endpoint string, body interface{}) ([]b
 
 // makeRequest makes an HTTP request and returns the response body
 func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	url := c.BaseURL + endpoint
-
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonBody)
+	if body == nil {
+		return c.send(method, endpoint, "", nil)
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
+	return c.send(method, endpoint, "application/json", bytes.NewBuffer(jsonBody))
+}
+
+// send makes a request with a raw body, e.g. a multipart upload
+func (c *Client) send(method, endpoint, contentType string, reqBody io.Reader) ([]byte, error) {
+	url := c.BaseURL + endpoint
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(c.context(), method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	// Add Authorization header if API key is available
@@ -362,9 +385,20 @@ func (c *Client) DeleteTask(id string) error {
 	return err
 }
 
-func (c *Client) StartTask(taskID string) error {
-	_, err := c.makeRequest("POST", "/tasks/"+taskID+"/start", nil)
-	return err
+// StartTask makes a task active and IN_PROGRESS and returns it, so callers
+// holding a short ID learn the full one
+func (c *Client) StartTask(taskID string) (*models.Task, error) {
+	respBody, err := c.makeRequest("POST", "/tasks/"+taskID+"/start", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var task models.Task
+	if err := json.Unmarshal(respBody, &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &task, nil
 }
 
 func (c *Client) CompleteTask(taskID string) error {
@@ -377,6 +411,14 @@ func (c *Client) StopTask(taskID string) error {
 	return err
 }
 
+// AddTaskDependency records that blockingTaskID must finish before taskID
+func (c *Client) AddTaskDependency(taskID, blockingTaskID string) error {
+	_, err := c.makeRequest("POST", "/tasks/"+taskID+"/dependencies", map[string]string{
+		"blocking_task_id": blockingTaskID,
+	})
+	return err
+}
+
 func (c *Client) GetActiveTask() (*models.Task, error) {
 	respBody, err := c.makeRequest("GET", "/tasks/active", nil)
 	if err != nil {
@@ -515,6 +557,54 @@ func (c *Client) CreateMemory(projectID, content string, tags ...string) (*model
 	return &memory, nil
 }
 
+// CreateTypedMemory creates a structured memory (see internal/memtype) with per-type fields
+func (c *Client) CreateTypedMemory(projectID, content, memoryType string, fields map[string]string, tags ...string) (*models.Memory, error) {
+	reqBody := map[string]interface{}{
+		"project_id": projectID,
+		"content":    content,
+		"type":       memoryType,
+		"fields":     fields,
+	}
+	if len(tags) > 0 {
+		reqBody["tags"] = tags
+	}
+
+	respBody, err := c.makeRequest("POST", "/memories", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var memory models.Memory
+	if err := json.Unmarshal(respBody, &memory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &memory, nil
+}
+
+// CreateMemoryWithOptions creates a memory with further request fields, e.g.
+// the type and fields of a typed memory or expires_at, review_after and evergreen
+func (c *Client) CreateMemoryWithOptions(projectID, content string, options map[string]interface{}) (*models.Memory, error) {
+	reqBody := map[string]interface{}{}
+	for key, value := range options {
+		reqBody[key] = value
+	}
+	reqBody["project_id"] = projectID
+	reqBody["content"] = content
+
+	respBody, err := c.makeRequest("POST", "/memories", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var memory models.Memory
+	if err := json.Unmarshal(respBody, &memory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &memory, nil
+}
+
 // MemoriesListResponse represents the paginated response from memories endpoint
 type MemoriesListResponse struct {
 	Memories []models.Memory `json:"memories"`
@@ -524,6 +614,11 @@ type MemoriesListResponse struct {
 }
 
 func (c *Client) ListMemories(projectID, search string) ([]models.Memory, error) {
+	return c.ListMemoriesQuery(projectID, search, "")
+}
+
+// ListMemoriesQuery lists memories, optionally only those of one memory type
+func (c *Client) ListMemoriesQuery(projectID, search, memoryType string) ([]models.Memory, error) {
 	endpoint := "/memories"
 	params := url.Values{}
 	if projectID != "" {
@@ -532,6 +627,9 @@ func (c *Client) ListMemories(projectID, search string) ([]models.Memory, error)
 	if search != "" {
 		params.Add("search", search)
 	}
+	if memoryType != "" {
+		params.Add("type", memoryType)
+	}
 	if encoded := params.Encode(); encoded != "" {
 		endpoint += "?" + encoded
 	}
@@ -581,6 +679,49 @@ func (c *Client) GetMemory(id string) (*models.Memory, error) {
 	return &memory, nil
 }
 
+// MemoryRevision is one stored version of a memory's content
+type MemoryRevision struct {
+	ID        string    `json:"id"`
+	MemoryID  string    `json:"memory_id"`
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemoryRevisionListResponse represents the response from listing memory revisions
+type MemoryRevisionListResponse struct {
+	Revisions []MemoryRevision `json:"revisions"`
+}
+
+// ListMemoryRevisions returns the version history of a memory, oldest first
+func (c *Client) ListMemoryRevisions(memoryID string) ([]MemoryRevision, error) {
+	respBody, err := c.makeRequest("GET", "/memories/"+memoryID+"/revisions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response MemoryRevisionListResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return response.Revisions, nil
+}
+
+// RestoreMemoryRevision makes an old version the memory's current content
+func (c *Client) RestoreMemoryRevision(memoryID string, version int) (*models.Memory, error) {
+	respBody, err := c.makeRequest("POST", fmt.Sprintf("/memories/%s/revisions/%d/restore", memoryID, version), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var memory models.Memory
+	if err := json.Unmarshal(respBody, &memory); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal memory: %w", err)
+	}
+	return &memory, nil
+}
+
 // Context API methods
 func (c *Client) CreateContext(name, description string) (*models.Context, error) {
 	reqBody := map[string]interface{}{
@@ -723,6 +864,175 @@ func (c *Client) CreateMemoryTaskLink(taskID, memoryID, relationType string) ([]
 	return c.makeRequest("POST", "/memory-task-links", req)
 }
 
+// CreateMemoryLink relates two memories, e.g. a note and a note it links to
+func (c *Client) CreateMemoryLink(memoryID, targetMemoryID, relationType string) ([]byte, error) {
+	req := map[string]interface{}{
+		"memory_id":        memoryID,
+		"target_memory_id": targetMemoryID,
+	}
+	if strings.TrimSpace(relationType) != "" {
+		req["relation_type"] = relationType
+	}
+	return c.makeRequest("POST", "/memory-links", req)
+}
+
+// ListMemoryLinks lists the relations from or to a memory, or between the
+// memories of a project. Both filters are optional.
+func (c *Client) ListMemoryLinks(memoryID, projectID string) ([]models.MemoryLink, error) {
+	endpoint := "/memory-links"
+	params := url.Values{}
+	if memoryID != "" {
+		params.Add("memory_id", memoryID)
+	}
+	if projectID != "" {
+		params.Add("project_id", projectID)
+	}
+	if encoded := params.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	var links []models.MemoryLink
+	if err := json.Unmarshal(respBody, &links); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal memory links: %w", err)
+	}
+	return links, nil
+}
+
+// DeleteMemoryLink removes a memory relation
+func (c *Client) DeleteMemoryLink(id string) error {
+	_, err := c.makeRequest("DELETE", "/memory-links/"+id, nil)
+	return err
+}
+
+// UploadAttachment attaches a file to a task or memory (ownerType "task" or "memory")
+func (c *Client) UploadAttachment(ownerType, ownerID, name string, content io.Reader) (*models.Attachment, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("owner_type", ownerType)
+	_ = form.WriteField("owner_id", ownerID)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.send("POST", "/attachments", form.FormDataContentType(), &body)
+	if err != nil {
+		return nil, err
+	}
+	var attachment models.Attachment
+	if err := json.Unmarshal(respBody, &attachment); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+// AttachmentQuery filters ListAttachments; all fields are optional
+type AttachmentQuery struct {
+	OwnerType string
+	OwnerID   string
+	ProjectID string
+	Search    string // matches the text of text attachments
+}
+
+// ListAttachments lists attachments, newest first
+func (c *Client) ListAttachments(q AttachmentQuery) ([]models.Attachment, error) {
+	endpoint := "/attachments"
+	params := url.Values{}
+	for key, value := range map[string]string{"owner_type": q.OwnerType, "owner_id": q.OwnerID, "project_id": q.ProjectID, "search": q.Search} {
+		if value != "" {
+			params.Add(key, value)
+		}
+	}
+	if encoded := params.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	var attachments []models.Attachment
+	if err := json.Unmarshal(respBody, &attachments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// SearchTerms splits a recall query the way recall matches it: terms
+// separated by commas must all match, otherwise any word may
+func SearchTerms(query string) (terms []string, all bool) {
+	all = strings.Contains(query, ",")
+	parts := strings.Fields(query)
+	if all {
+		parts = strings.Split(query, ",")
+	}
+	for _, t := range parts {
+		if t = strings.TrimSpace(strings.ToLower(t)); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms, all
+}
+
+// SearchAttachments finds text attachments matching any of the terms, or
+// all of them, in the order the backend first returned them
+func (c *Client) SearchAttachments(projectID string, terms []string, all bool) ([]models.Attachment, error) {
+	hits := map[uuid.UUID]int{}
+	var found []models.Attachment
+	for _, t := range terms {
+		attachments, err := c.ListAttachments(AttachmentQuery{ProjectID: projectID, Search: t})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			if hits[a.ID] == 0 {
+				found = append(found, a)
+			}
+			hits[a.ID]++
+		}
+	}
+
+	var matched []models.Attachment
+	for _, a := range found {
+		if !all || hits[a.ID] == len(terms) {
+			matched = append(matched, a)
+		}
+	}
+	return matched, nil
+}
+
+// GetAttachment returns an attachment's metadata by ID or short ID
+func (c *Client) GetAttachment(id string) (*models.Attachment, error) {
+	respBody, err := c.makeRequest("GET", "/attachments/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	var attachment models.Attachment
+	if err := json.Unmarshal(respBody, &attachment); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+// DownloadAttachment returns an attachment's content
+func (c *Client) DownloadAttachment(id string) ([]byte, error) {
+	return c.makeRequest("GET", "/attachments/"+id+"/content", nil)
+}
+
+// DeleteAttachment removes an attachment
+func (c *Client) DeleteAttachment(id string) error {
+	_, err := c.makeRequest("DELETE", "/attachments/"+id, nil)
+	return err
+}
+
 func (c *Client) ListTaskMemories(taskID string) ([]models.Memory, error) {
 	endpoint := fmt.Sprintf("/tasks/%s/memories", taskID)
 	respBody, err := c.makeRequest("GET", endpoint, nil)
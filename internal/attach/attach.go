@@ -0,0 +1,181 @@
+// Package attach stores attachment content by its SHA-256, so the same file
+// attached twice is kept once. The CLI keeps a local copy of everything it
+// uploads or downloads; the self-hosted server keeps the uploaded files.
+package attach
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+)
+
+// ErrTooLarge is returned for content over constants.MaxAttachmentBytes
+var ErrTooLarge = fmt.Errorf("attachment is larger than %d MB", constants.MaxAttachmentBytes/1_000_000)
+
+// Store is a directory of files named by the SHA-256 of their content
+type Store struct {
+	Dir string
+}
+
+// New returns a store rooted at dir
+func New(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Default returns the local store in the ramorie data directory (~/.ramorie/attachments)
+func Default() (*Store, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, "attachments")), nil
+}
+
+// Put copies r into the store and returns its SHA-256 and size. Content over
+// the attachment limit is rejected with ErrTooLarge.
+func (s *Store) Put(r io.Reader) (string, int64, error) {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return "", 0, err
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, constants.MaxAttachmentBytes+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if !constants.IsWithinAttachmentLimit(size) {
+		return "", 0, ErrTooLarge
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	path := s.Path(sum)
+	if _, err := os.Stat(path); err == nil {
+		return sum, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+	return sum, size, nil
+}
+
+// Path is where content with the given SHA-256 is stored, e.g. <dir>/ab/abcdef…
+func (s *Store) Path(sum string) string {
+	return filepath.Join(s.Dir, sum[:2], sum)
+}
+
+// Has reports whether the store holds the content
+func (s *Store) Has(sum string) bool {
+	if !ValidSum(sum) {
+		return false
+	}
+	_, err := os.Stat(s.Path(sum))
+	return err == nil
+}
+
+// Open opens stored content for reading
+func (s *Store) Open(sum string) (*os.File, error) {
+	if !ValidSum(sum) {
+		return nil, fmt.Errorf("invalid content hash %q", sum)
+	}
+	return os.Open(s.Path(sum))
+}
+
+// Remove deletes stored content; removing missing content is not an error
+func (s *Store) Remove(sum string) error {
+	if !ValidSum(sum) {
+		return fmt.Errorf("invalid content hash %q", sum)
+	}
+	if err := os.Remove(s.Path(sum)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ValidSum reports whether sum is a hex SHA-256, which keeps stored paths inside the store
+func ValidSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil
+}
+
+// textExtensions are searchable even when their content sniffs as binary
+var textExtensions = map[string]bool{
+	".txt": true, ".log": true, ".md": true, ".json": true, ".yaml": true, ".yml": true,
+	".csv": true, ".xml": true, ".trace": true, ".out": true, ".err": true,
+}
+
+// ContentType guesses the MIME type from the file name and the first bytes of content
+func ContentType(name string, head []byte) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); t != "" {
+		return t
+	}
+	if textExtensions[strings.ToLower(filepath.Ext(name))] && utf8.Valid(head) {
+		return "text/plain; charset=utf-8"
+	}
+	return http.DetectContentType(head)
+}
+
+// IsText reports whether content of the given MIME type is indexed for search
+func IsText(mimeType string) bool {
+	t, _, _ := mime.ParseMediaType(mimeType)
+	return strings.HasPrefix(t, "text/") ||
+		t == "application/json" || t == "application/xml" || t == "application/yaml" ||
+		strings.HasSuffix(t, "+json") || strings.HasSuffix(t, "+xml")
+}
+
+// Snippet returns about width bytes of text around the first case-insensitive
+// match of query, on one line
+func Snippet(text, query string, width int) string {
+	i := strings.Index(strings.ToLower(text), strings.ToLower(strings.TrimSpace(query)))
+	if i < 0 {
+		return ""
+	}
+	// Lowercasing can change byte lengths outside ASCII
+	i = min(i, len(text))
+	start := max(0, i-width/3)
+	end := min(len(text), start+width)
+	// Start at a word, not in the middle of one
+	if start > 0 {
+		if sp := strings.IndexAny(text[start:i], " \t\n"); sp >= 0 {
+			start += sp + 1
+		}
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -0,0 +1,84 @@
+package attach
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+)
+
+func TestStore(t *testing.T) {
+	s := New(t.TempDir())
+
+	sum, size, err := s.Put(strings.NewReader("panic: nil map\n"))
+	if err != nil || size != 15 || !ValidSum(sum) {
+		t.Fatalf("Put = %q, %d, %v", sum, size, err)
+	}
+	again, _, err := s.Put(strings.NewReader("panic: nil map\n"))
+	if err != nil || again != sum {
+		t.Errorf("second Put = %q, %v; want the same hash", again, err)
+	}
+	f, err := s.Open(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "panic: nil map\n" {
+		t.Errorf("Open read %q", data)
+	}
+
+	if err := s.Remove(sum); err != nil || s.Has(sum) {
+		t.Errorf("Remove: %v, still stored: %v", err, s.Has(sum))
+	}
+	if _, err := s.Open("../../etc/passwd"); err == nil {
+		t.Error("Open accepted a path")
+	}
+
+	big := bytes.NewReader(make([]byte, constants.MaxAttachmentBytes+1))
+	if _, _, err := s.Put(big); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Put over the limit: %v", err)
+	}
+	entries, _ := os.ReadDir(s.Dir)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".upload-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		text bool
+	}{
+		{"trace.log", "goroutine 1 [running]:", true},
+		{"notes.md", "# Notes", true},
+		{"config.json", "{}", true},
+		{"screenshot.png", "\x89PNG\r\n\x1a\n", false},
+		{"dump", "plain words", true},
+		{"core", "\x00\x01\x02\x03", false},
+	}
+	for _, tt := range tests {
+		mimeType := ContentType(tt.name, []byte(tt.head))
+		if IsText(mimeType) != tt.text {
+			t.Errorf("%s: %s, text = %v; want %v", tt.name, mimeType, !tt.text, tt.text)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("x ", 50) + "NullPointerException at Foo.bar\n" + strings.Repeat("y ", 50)
+	got := Snippet(text, "nullpointer", 40)
+	if !strings.Contains(got, "NullPointerException at") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Snippet = %q", got)
+	}
+	if Snippet(text, "missing", 40) != "" {
+		t.Error("Snippet matched a missing term")
+	}
+}
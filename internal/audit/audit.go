@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+)
+
+const (
+	// currentFileName is the active audit log; rotated files get a timestamp suffix
+	currentFileName = "audit.jsonl"
+
+	// DefaultMaxBytes is the size at which the active log is rotated (10 MB)
+	DefaultMaxBytes = 10 * 1024 * 1024
+
+	// DefaultMaxFiles is how many rotated logs are kept besides the active one
+	DefaultMaxFiles = 10
+)
+
+// Client identifies the MCP client that issued a tool call
+type Client struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Record is a single audit log entry for one MCP tool call
+type Record struct {
+	Time      time.Time       `json:"time"`
+	SessionID string          `json:"session_id,omitempty"`
+	Client    Client          `json:"client"`
+	Tool      string          `json:"tool"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Outcome   string          `json:"outcome"` // ok, error
+	Error     string          `json:"error,omitempty"`
+	LatencyMS int64           `json:"latency_ms"`
+	EntityIDs []string        `json:"entity_ids,omitempty"`
+}
+
+// Logger appends audit records to JSONL files, rotating them by size
+type Logger struct {
+	Dir      string
+	MaxBytes int64
+	MaxFiles int
+
+	mu sync.Mutex
+}
+
+// DefaultDir returns the audit log directory (~/.ramorie/audit)
+func DefaultDir() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit"), nil
+}
+
+// NewLogger creates a logger writing to dir with default rotation settings
+func NewLogger(dir string) *Logger {
+	return &Logger{
+		Dir:      dir,
+		MaxBytes: DefaultMaxBytes,
+		MaxFiles: DefaultMaxFiles,
+	}
+}
+
+// Append writes a record to the active log, rotating it first if it is full
+func (l *Logger) Append(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return err
+	}
+
+	path := filepath.Join(l.Dir, currentFileName)
+	if info, err := os.Stat(path); err == nil && l.MaxBytes > 0 && info.Size()+int64(len(line)) > l.MaxBytes {
+		if err := l.rotate(path); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(line)
+	return err
+}
+
+// rotate renames the active log with a timestamp and prunes old rotated logs
+func (l *Logger) rotate(path string) error {
+	// Names sort by time; a rotation within the same millisecond takes the next one
+	var rotated string
+	for t := time.Now().UTC(); ; t = t.Add(time.Millisecond) {
+		rotated = filepath.Join(l.Dir, fmt.Sprintf("audit-%s.jsonl", t.Format("20060102T150405.000")))
+		if _, err := os.Stat(rotated); os.IsNotExist(err) {
+			break
+		}
+	}
+	if err := os.Rename(path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	files, err := rotatedFiles(l.Dir)
+	if err != nil {
+		return err
+	}
+	if l.MaxFiles > 0 && len(files) > l.MaxFiles {
+		for _, f := range files[:len(files)-l.MaxFiles] {
+			_ = os.Remove(f)
+		}
+	}
+	return nil
+}
+
+// rotatedFiles returns rotated log paths, oldest first
+func rotatedFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Filter selects records when reading the audit log
+type Filter struct {
+	Tool    string
+	Since   time.Time
+	Outcome string
+	Query   string
+}
+
+func (f Filter) matches(rec Record) bool {
+	if f.Tool != "" && !strings.EqualFold(rec.Tool, f.Tool) {
+		return false
+	}
+	if !f.Since.IsZero() && rec.Time.Before(f.Since) {
+		return false
+	}
+	if f.Outcome != "" && !strings.EqualFold(rec.Outcome, f.Outcome) {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		haystack := strings.ToLower(string(rec.Input) + " " + rec.Error + " " + strings.Join(rec.EntityIDs, " "))
+		if !strings.Contains(haystack, q) {
+			return false
+		}
+	}
+	return true
+}
+
+// Read returns all records in dir matching the filter, oldest first
+func Read(dir string, filter Filter) ([]Record, error) {
+	files, err := rotatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, filepath.Join(dir, currentFileName))
+
+	var records []Record
+	for _, path := range files {
+		recs, err := readFile(path, filter)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	return records, nil
+}
+
+func readFile(path string, filter Filter) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// Skip partially written or corrupted lines
+			continue
+		}
+		if filter.matches(rec) {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
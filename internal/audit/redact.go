@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+)
+
+// maxValueChars is the longest string value kept verbatim in an audit record
+const maxValueChars = 200
+
+// sensitiveKeys are input fields whose values are never written to the audit log
+var sensitiveKeys = []string{"password", "token", "secret", "api_key", "apikey", "authorization"}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Redact masks sensitive fields and truncates long strings in a JSON tool
+// input. Every string value is also scanned, and whatever the scanner finds
+// is replaced with [REDACTED:<rule>] whatever its storage policy; a nil
+// scanner uses the default rules.
+func Redact(raw json.RawMessage, scanner *secrets.Scanner) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	if scanner == nil {
+		scanner = secrets.New()
+	}
+	b, err := json.Marshal(redactValue(scanner, "", v))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// RedactText replaces what the scanner finds in text, e.g. an error message
+func RedactText(text string, scanner *secrets.Scanner) string {
+	if scanner == nil {
+		scanner = secrets.New()
+	}
+	return secrets.Redact(text, scanner.Scan(text))
+}
+
+func redactValue(scanner *secrets.Scanner, key string, v interface{}) interface{} {
+	if isSensitiveKey(key) {
+		return "[REDACTED]"
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			out[k] = redactValue(scanner, k, val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = redactValue(scanner, key, val)
+		}
+		return out
+	case string:
+		// Redact before truncating, so a secret cut in half is still found
+		t = secrets.Redact(t, scanner.Scan(t))
+		if r := []rune(t); len(r) > maxValueChars {
+			return fmt.Sprintf("%s… (%d chars)", string(r[:maxValueChars]), len(r))
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityIDs collects the UUIDs of entities referenced by a tool's input and output.
+// Only "id" and "*_id"/"*Id" fields are considered, down to the items of top-level lists.
+func EntityIDs(values ...interface{}) []string {
+	seen := map[string]bool{}
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		var generic interface{}
+		switch t := v.(type) {
+		case json.RawMessage:
+			if err := json.Unmarshal(t, &generic); err != nil {
+				continue
+			}
+		default:
+			b, err := json.Marshal(t)
+			if err != nil {
+				continue
+			}
+			if err := json.Unmarshal(b, &generic); err != nil {
+				continue
+			}
+		}
+		collectIDs(generic, 0, seen)
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func collectIDs(v interface{}, depth int, seen map[string]bool) {
+	if depth > 2 {
+		return
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if s, ok := val.(string); ok && isIDKey(k) && uuidPattern.MatchString(s) {
+				seen[strings.ToLower(s)] = true
+				continue
+			}
+			collectIDs(val, depth+1, seen)
+		}
+	case []interface{}:
+		for _, val := range t {
+			collectIDs(val, depth+1, seen)
+		}
+	}
+}
+
+func isIDKey(k string) bool {
+	return k == "id" || strings.HasSuffix(k, "_id") || strings.HasSuffix(k, "Id") || strings.HasSuffix(k, "ID")
+}
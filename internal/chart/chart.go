@@ -0,0 +1,302 @@
+// Package chart renders small text charts for terminal reports: line
+// charts, scatter plots, sparklines, calendar heatmaps and horizontal bars. Everything
+// returns plain strings so callers decide where the output goes.
+package chart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Series is one line of a line chart
+type Series struct {
+	Name   string
+	Values []float64
+	Mark   rune
+}
+
+// Line draws series on a grid about width columns wide and height rows tall,
+// with a y axis on the left and the first and last labels under the x axis.
+// Later series are drawn over earlier ones. A legend follows the axis.
+func Line(series []Series, labels []string, width, height int) []string {
+	n, top := 0, 0.0
+	for _, s := range series {
+		n = max(n, len(s.Values))
+		for _, v := range s.Values {
+			top = math.Max(top, v)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	top = math.Max(math.Ceil(top), 1)
+	height = max(height, 3)
+
+	labelW := len(strconv.FormatFloat(top, 'f', 0, 64))
+	plotW := max(width-labelW-2, 2)
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", plotW))
+	}
+	col := func(i int) int {
+		if n == 1 {
+			return 0
+		}
+		return i * (plotW - 1) / (n - 1)
+	}
+	row := func(v float64) int {
+		r := height - 1 - int(math.Round(v/top*float64(height-1)))
+		return min(max(r, 0), height-1)
+	}
+	for _, s := range series {
+		for i, v := range s.Values {
+			grid[row(v)][col(i)] = s.Mark
+			if i+1 >= len(s.Values) {
+				continue
+			}
+			// Interpolate between points so the line reads as a line
+			x0, x1 := col(i), col(i+1)
+			for x := x0 + 1; x < x1; x++ {
+				f := float64(x-x0) / float64(x1-x0)
+				grid[row(v+(s.Values[i+1]-v)*f)][x] = s.Mark
+			}
+		}
+	}
+
+	lines := frame(grid, top, labels)
+	var legend []string
+	for _, s := range series {
+		legend = append(legend, string(s.Mark)+" "+s.Name)
+	}
+	lines = append(lines, strings.Repeat(" ", labelW+2)+strings.Join(legend, "   "))
+	return lines
+}
+
+// frame puts a y axis from 0 to top left of the grid, an x axis under it,
+// and the first and last labels under the axis
+func frame(grid [][]rune, top float64, labels []string) []string {
+	height := len(grid)
+	plotW := len(grid[0])
+	topLabel := strconv.FormatFloat(top, 'f', 0, 64)
+	labelW := len(topLabel)
+
+	lines := make([]string, 0, height+2)
+	for i, r := range grid {
+		label := ""
+		switch i {
+		case 0:
+			label = topLabel
+		case height - 1:
+			label = "0"
+		case (height - 1) / 2:
+			label = strconv.FormatFloat(top/2, 'f', 0, 64)
+		}
+		lines = append(lines, strings.TrimRight(pad(label, labelW, true)+" ┤"+string(r), " "))
+	}
+	lines = append(lines, strings.Repeat(" ", labelW)+" └"+strings.Repeat("─", plotW))
+
+	if len(labels) > 0 {
+		first, last := labels[0], labels[len(labels)-1]
+		axis := strings.Repeat(" ", labelW+2) + first
+		if len(labels) > 1 {
+			gap := labelW + 2 + plotW - utf8.RuneCountInString(axis) - utf8.RuneCountInString(last)
+			axis += strings.Repeat(" ", max(gap, 1)) + last
+		}
+		lines = append(lines, axis)
+	}
+	return lines
+}
+
+// Point is one point of a scatter plot
+type Point struct {
+	X, Y float64
+}
+
+// Rule is a labelled horizontal line across a scatter plot, such as a percentile
+type Rule struct {
+	Y    float64
+	Name string
+}
+
+// Scatter plots points on a grid about width columns wide and height rows
+// tall, spanning the x range of the points and 0 to the largest y. A cell
+// with one point shows •, with more ●. Rules are drawn behind the points.
+func Scatter(points []Point, rules []Rule, labels []string, width, height int) []string {
+	if len(points) == 0 {
+		return nil
+	}
+	minX, maxX, top := points[0].X, points[0].X, 0.0
+	for _, p := range points {
+		minX, maxX, top = math.Min(minX, p.X), math.Max(maxX, p.X), math.Max(top, p.Y)
+	}
+	for _, r := range rules {
+		top = math.Max(top, r.Y)
+	}
+	top = math.Max(math.Ceil(top), 1)
+	height = max(height, 3)
+	labelW := len(strconv.FormatFloat(top, 'f', 0, 64))
+	plotW := max(width-labelW-2, 2)
+
+	row := func(y float64) int {
+		r := height - 1 - int(math.Round(y/top*float64(height-1)))
+		return min(max(r, 0), height-1)
+	}
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", plotW))
+	}
+	for _, r := range rules {
+		line := grid[row(r.Y)]
+		for x := range line {
+			line[x] = '┈'
+		}
+	}
+	counts := map[[2]int]int{}
+	for _, p := range points {
+		x := 0
+		if maxX > minX {
+			x = int(math.Round((p.X - minX) / (maxX - minX) * float64(plotW-1)))
+		}
+		cell := [2]int{row(p.Y), x}
+		counts[cell]++
+		grid[cell[0]][cell[1]] = '•'
+		if counts[cell] > 1 {
+			grid[cell[0]][cell[1]] = '●'
+		}
+	}
+	// Name each rule at the rightmost spot that hides no points
+	for _, r := range rules {
+		line := grid[row(r.Y)]
+		name := []rune(" " + r.Name)
+		for at := plotW - len(name); at >= 0; at-- {
+			if !strings.ContainsFunc(string(line[at:at+len(name)]), func(c rune) bool { return c != '┈' }) {
+				copy(line[at:], name)
+				break
+			}
+		}
+	}
+	return frame(grid, top, labels)
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one block per value, scaled to the largest value
+func Sparkline(values []float64) string {
+	top := 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if top > 0 && v > 0 {
+			i = int(math.Round(v / top * float64(len(sparks)-1)))
+		}
+		b.WriteRune(sparks[min(max(i, 0), len(sparks)-1)])
+	}
+	return b.String()
+}
+
+var eighths = []rune(" ▏▎▍▌▋▊▉")
+
+// Bar draws a horizontal bar of up to width cells for value out of total,
+// using eighth blocks for the remainder
+func Bar(value, total float64, width int) string {
+	if total <= 0 || value <= 0 || width <= 0 {
+		return ""
+	}
+	cells := math.Min(value/total, 1) * float64(width)
+	full := int(cells)
+	bar := strings.Repeat("█", full)
+	if rem := int((cells - float64(full)) * 8); rem > 0 && full < width {
+		bar += string(eighths[rem])
+	}
+	return bar
+}
+
+var shades = []rune("·░▒▓█")
+
+// Heatmap draws a calendar of daily counts keyed by "2006-01-02", one row per
+// weekday (Monday first) and one column per week. Weeks that do not fit in
+// width are dropped from the start. Days outside from..to are left blank.
+func Heatmap(counts map[string]int, from, to time.Time, width int) []string {
+	from = day(from)
+	to = day(to)
+	if to.Before(from) {
+		return nil
+	}
+	// Columns start on the Monday on or before from
+	start := from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	weeks := int(to.Sub(start).Hours()/24)/7 + 1
+	const labelW = 4
+	if fit := max((width-labelW)/2, 1); weeks > fit {
+		start = start.AddDate(0, 0, 7*(weeks-fit))
+		weeks = fit
+	}
+
+	top := 0
+	for _, c := range counts {
+		top = max(top, c)
+	}
+	shade := func(c int) rune {
+		if c <= 0 || top == 0 {
+			return shades[0]
+		}
+		return shades[1+min((c-1)*4/top, 3)]
+	}
+
+	// Month names above the week their first day falls in
+	header := []rune(strings.Repeat(" ", labelW+weeks*2))
+	for w := 0; w < weeks; w++ {
+		monday := start.AddDate(0, 0, 7*w)
+		sunday := monday.AddDate(0, 0, 6)
+		if w == 0 || monday.Month() != sunday.Month() || monday.Day() == 1 {
+			name := []rune(sunday.Format("Jan"))
+			if w == 0 {
+				name = []rune(monday.Format("Jan"))
+			}
+			at := labelW + w*2
+			if at+len(name) <= len(header) && (at == labelW || header[at-1] == ' ') {
+				copy(header[at:], name)
+			}
+		}
+	}
+	lines := []string{strings.TrimRight(string(header), " ")}
+
+	for d := 0; d < 7; d++ {
+		var b strings.Builder
+		b.WriteString(pad(start.AddDate(0, 0, d).Format("Mon"), labelW, false))
+		for w := 0; w < weeks; w++ {
+			date := start.AddDate(0, 0, 7*w+d)
+			if date.Before(from) || date.After(to) {
+				b.WriteString("  ")
+				continue
+			}
+			b.WriteRune(shade(counts[date.Format("2006-01-02")]))
+			b.WriteByte(' ')
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	lines = append(lines, strings.Repeat(" ", labelW)+"less "+strings.Join(strings.Split(string(shades), ""), " ")+" more")
+	return lines
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func pad(s string, width int, right bool) string {
+	gap := width - utf8.RuneCountInString(s)
+	if gap <= 0 {
+		return s
+	}
+	if right {
+		return strings.Repeat(" ", gap) + s
+	}
+	return s + strings.Repeat(" ", gap)
+}
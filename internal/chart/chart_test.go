@@ -0,0 +1,109 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 1, 2, 4, 8}); got != "▁▂▃▅█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]float64{0, 0}); got != "▁▁" {
+		t.Errorf("Sparkline of zeros = %q", got)
+	}
+}
+
+func TestBar(t *testing.T) {
+	cases := []struct {
+		value, total float64
+		width        int
+		want         string
+	}{
+		{5, 10, 10, "█████"},
+		{1, 20, 10, "▌"},
+		{20, 10, 4, "████"},
+		{0, 10, 10, ""},
+	}
+	for _, c := range cases {
+		if got := Bar(c.value, c.total, c.width); got != c.want {
+			t.Errorf("Bar(%v, %v, %d) = %q, want %q", c.value, c.total, c.width, got, c.want)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	lines := Line([]Series{
+		{Name: "ideal", Values: []float64{10, 5, 0}, Mark: '·'},
+		{Name: "actual", Values: []float64{10, 8, 6}, Mark: '●'},
+	}, []string{"03-01", "03-03"}, 40, 6)
+
+	// 6 rows, axis, labels, legend
+	if len(lines) != 9 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	for _, l := range lines {
+		if n := utf8.RuneCountInString(l); n > 40 {
+			t.Errorf("line is %d runes wide: %q", n, l)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "10 ┤●") {
+		t.Errorf("top row = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[5], " 0 ┤") || !strings.HasSuffix(lines[5], "·") {
+		t.Errorf("bottom row = %q", lines[5])
+	}
+	if !strings.HasSuffix(lines[7], "03-03") || utf8.RuneCountInString(lines[7]) != 40 {
+		t.Errorf("labels = %q", lines[7])
+	}
+	if Line(nil, nil, 40, 6) != nil {
+		t.Error("empty chart should render nothing")
+	}
+}
+
+func TestScatter(t *testing.T) {
+	lines := Scatter([]Point{{0, 1}, {10, 9}, {10, 9}, {5, 0}}, []Rule{{Y: 5, Name: "p85"}}, []string{"a", "b"}, 20, 5)
+	want := []string{
+		"9 ┤                ●",
+		"  ┤",
+		"4 ┤┈┈┈┈┈┈┈┈┈┈┈┈┈ p85",
+		"  ┤",
+		"0 ┤•       •",
+		"  └─────────────────",
+		"   a               b",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if Scatter(nil, nil, nil, 20, 5) != nil {
+		t.Error("empty plot should render nothing")
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC) // Monday
+	to := from.AddDate(0, 0, 13)
+	lines := Heatmap(map[string]int{"2025-03-03": 4, "2025-03-11": 1}, from, to, 80)
+
+	// Header, 7 weekdays, legend
+	if len(lines) != 9 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if lines[0] != "    Mar" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if lines[1] != "Mon █ ·" {
+		t.Errorf("Monday = %q", lines[1])
+	}
+	if lines[2] != "Tue · ░" {
+		t.Errorf("Tuesday = %q", lines[2])
+	}
+
+	// Narrow terminals keep the most recent weeks
+	narrow := Heatmap(map[string]int{"2025-03-11": 1}, from, to, 6)
+	if narrow[2] != "Tue ░" {
+		t.Errorf("narrow Tuesday = %q", narrow[2])
+	}
+}
@@ -21,7 +21,10 @@ func NewAnnotateCmd() *cli.Command {
 				return fmt.Errorf("task ID and content are required")
 			}
 			taskID := c.Args().Get(0)
-			content := c.Args().Get(1)
+			content, err := guardContent("annotation", c.Args().Get(1))
+			if err != nil {
+				return err
+			}
 
 			client := api.NewClient()
 			annotation, err := client.CreateAnnotation(taskID, content)
@@ -0,0 +1,408 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/attach"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// ownerFlags pick whether an ID names a task or a memory when it could be either
+func ownerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{Name: "task", Usage: "The ID is a task"},
+		&cli.BoolFlag{Name: "memory", Usage: "The ID is a memory"},
+	}
+}
+
+// attachOwner is the task or memory an attachment belongs to
+type attachOwner struct {
+	kind  string // task or memory
+	id    string
+	label string
+}
+
+// resolveAttachOwner finds the task or memory with the given ID. Without
+// --task or --memory both are tried, and an ID matching both is rejected.
+func resolveAttachOwner(client *api.Client, c *cli.Context, ident string) (attachOwner, error) {
+	var owners []attachOwner
+	if !c.Bool("memory") {
+		if t, err := client.GetTask(ident); err == nil {
+			owners = append(owners, attachOwner{"task", t.ID.String(), truncateString(t.Title, 50)})
+		}
+	}
+	if !c.Bool("task") {
+		if m, err := client.GetMemory(ident); err == nil {
+			owners = append(owners, attachOwner{"memory", m.ID.String(), truncateString(models.MemoryTitle(m.Content), 50)})
+		}
+	}
+	switch len(owners) {
+	case 0:
+		return attachOwner{}, fmt.Errorf("no task or memory '%s' found", ident)
+	case 1:
+		return owners[0], nil
+	}
+	return attachOwner{}, fmt.Errorf("'%s' matches both a task and a memory; use --task or --memory", ident)
+}
+
+// resolveAttachment returns an attachment by full or short ID
+func resolveAttachment(client *api.Client, ident string) (*models.Attachment, error) {
+	if len(ident) == 36 {
+		return client.GetAttachment(ident)
+	}
+	attachments, err := client.ListAttachments(api.AttachmentQuery{})
+	if err != nil {
+		return nil, err
+	}
+	var found *models.Attachment
+	for i, a := range attachments {
+		if strings.HasPrefix(a.ID.String(), strings.ToLower(ident)) {
+			if found != nil {
+				return nil, fmt.Errorf("attachment ID '%s' is ambiguous", ident)
+			}
+			found = &attachments[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("attachment '%s' not found", ident)
+	}
+	return found, nil
+}
+
+// NewAttachCommand attaches files to a task or memory.
+func NewAttachCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "attach",
+		Usage:     "Attach files (logs, stack traces, screenshots) to a task or memory",
+		ArgsUsage: "[task-or-memory-id] [file...]",
+		Description: fmt.Sprintf("Files are kept in ~/.ramorie/attachments by content hash and uploaded. Text files\n"+
+			"are scanned for secrets like memories are, and become searchable by recall.\n"+
+			"Files over %d MB are refused.", constants.MaxAttachmentBytes/1_000_000),
+		Flags: append(ownerFlags(), &cli.StringFlag{
+			Name:  "name",
+			Usage: "Name to store a single file under (default: the file name)",
+		}),
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 2 {
+				return fmt.Errorf("a task or memory ID and at least one file are required")
+			}
+			files := c.Args().Slice()[1:]
+			if c.String("name") != "" && len(files) > 1 {
+				return fmt.Errorf("--name only works with a single file")
+			}
+
+			client := api.NewClient()
+			owner, err := resolveAttachOwner(client, c, c.Args().First())
+			if err != nil {
+				return err
+			}
+			store, err := attach.Default()
+			if err != nil {
+				return err
+			}
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+
+			for _, path := range files {
+				name := c.String("name")
+				if name == "" {
+					name = filepath.Base(path)
+				}
+				a, err := attachFile(client, store, scanner, owner, path, name)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				fmt.Printf("📎 Attached %s (%s) to %s %s (%s) as %s\n", a.Name, formatBytes(a.Size), owner.kind, owner.id[:8], owner.label, a.ID.String()[:8])
+			}
+			return nil
+		},
+	}
+}
+
+// attachFile copies a file into the local store, checks text content for
+// secrets and uploads it
+func attachFile(client *api.Client, store *attach.Store, scanner *secrets.Scanner, owner attachOwner, path, name string) (*models.Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("is a directory")
+	}
+	if !constants.IsWithinAttachmentLimit(info.Size()) {
+		return nil, fmt.Errorf("%s (%s)", attach.ErrTooLarge, formatBytes(info.Size()))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if attach.IsText(attach.ContentType(name, data[:min(len(data), 512)])) {
+		text, err := guardLabelled(scanner, "attachment", string(data))
+		if err != nil {
+			return nil, err
+		}
+		data = []byte(text)
+	}
+
+	sum, _, err := store.Put(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	f, err := store.Open(sum)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a, err := client.UploadAttachment(owner.kind, owner.id, name, f)
+	if err != nil {
+		return nil, errors.New(apierrors.ParseAPIError(err))
+	}
+	return a, nil
+}
+
+// NewAttachmentsCommand lists, downloads and removes attachments.
+func NewAttachmentsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "attachments",
+		Usage: "List, download and remove attachments",
+		Subcommands: []*cli.Command{
+			attachmentsListCmd(),
+			attachmentsGetCmd(),
+			attachmentsRmCmd(),
+		},
+	}
+}
+
+func attachmentsListCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "list",
+		Aliases:   []string{"ls"},
+		Usage:     "List the attachments of a task or memory, or of a project",
+		ArgsUsage: "[task-or-memory-id]",
+		Flags: append(ownerFlags(),
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project name or ID (default: the active project, or all projects)",
+			},
+			&cli.BoolFlag{Name: "json", Usage: "Print the attachments as JSON"},
+		),
+		Action: func(c *cli.Context) error {
+			client := api.NewClient()
+			var q api.AttachmentQuery
+			if c.NArg() > 0 {
+				owner, err := resolveAttachOwner(client, c, c.Args().First())
+				if err != nil {
+					return err
+				}
+				q.OwnerType, q.OwnerID = owner.kind, owner.id
+			} else if arg := c.String("project"); arg != "" {
+				id, err := resolveProjectArg(client, arg)
+				if err != nil {
+					return err
+				}
+				q.ProjectID = id
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				q.ProjectID = cfg.ActiveProjectID
+			}
+
+			attachments, err := client.ListAttachments(q)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if c.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(attachments)
+			}
+			if len(attachments) == 0 {
+				fmt.Println("No attachments found.")
+				return nil
+			}
+			printAttachments(attachments)
+			return nil
+		},
+	}
+}
+
+// printAttachments shows attachments as a table, with the matching text for search results
+func printAttachments(attachments []models.Attachment) {
+	search := false
+	for _, a := range attachments {
+		search = search || a.Snippet != ""
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if search {
+		fmt.Fprintln(w, "ID\tNAME\tSIZE\tON\tMATCH")
+		fmt.Fprintln(w, "--\t----\t----\t--\t-----")
+	} else {
+		fmt.Fprintln(w, "ID\tNAME\tSIZE\tTYPE\tON\tADDED")
+		fmt.Fprintln(w, "--\t----\t----\t----\t--\t-----")
+	}
+	for _, a := range attachments {
+		owner := a.OwnerType + " " + a.OwnerID.String()[:8]
+		if search {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.ID.String()[:8], truncateString(a.Name, 32), formatBytes(a.Size), owner, truncateString(a.Snippet, 60))
+			continue
+		}
+		mimeType, _, _ := strings.Cut(a.MimeType, ";")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.ID.String()[:8], truncateString(a.Name, 32), formatBytes(a.Size),
+			mimeType, owner, a.CreatedAt.Local().Format("2006-01-02"))
+	}
+	w.Flush()
+}
+
+func attachmentsGetCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Usage:     "Save an attachment to a file (or stdout with -o -)",
+		ArgsUsage: "[attachment-id]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Where to save the file (default: its name in the current directory)",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Overwrite an existing file",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("attachment ID is required")
+			}
+			client := api.NewClient()
+			a, err := resolveAttachment(client, c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			data, err := attachmentContent(client, a)
+			if err != nil {
+				return err
+			}
+
+			out := c.String("output")
+			if out == "-" {
+				_, err := os.Stdout.Write(data)
+				return err
+			}
+			if out == "" {
+				out = filepath.Base(a.Name)
+			}
+			if _, err := os.Stat(out); err == nil && !c.Bool("force") {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", out)
+			}
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("💾 Saved %s (%s)\n", out, formatBytes(int64(len(data))))
+			return nil
+		},
+	}
+}
+
+// attachmentContent reads an attachment from the local store, downloading
+// and caching it when it is not there
+func attachmentContent(client *api.Client, a *models.Attachment) ([]byte, error) {
+	store, err := attach.Default()
+	if err != nil {
+		return nil, err
+	}
+	if store.Has(a.SHA256) {
+		f, err := store.Open(a.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	data, err := client.DownloadAttachment(a.ID.String())
+	if err != nil {
+		return nil, errors.New(apierrors.ParseAPIError(err))
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != a.SHA256 {
+		return nil, fmt.Errorf("downloaded content of %s does not match its hash", a.Name)
+	}
+	if _, _, err := store.Put(bytes.NewReader(data)); err != nil {
+		// The local copy is only a cache
+		fmt.Fprintf(os.Stderr, "warning: could not cache %s: %v\n", a.Name, err)
+	}
+	return data, nil
+}
+
+func attachmentsRmCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "rm",
+		Usage:     "Remove an attachment",
+		ArgsUsage: "[attachment-id]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("attachment ID is required")
+			}
+			client := api.NewClient()
+			a, err := resolveAttachment(client, c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if err := client.DeleteAttachment(a.ID.String()); err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			// Drop the local copy unless another attachment still uses it
+			if others, err := client.ListAttachments(api.AttachmentQuery{}); err == nil && !attachmentContentUsed(others, a.SHA256) {
+				if store, err := attach.Default(); err == nil {
+					_ = store.Remove(a.SHA256)
+				}
+			}
+			fmt.Printf("🗑️  Removed %s from %s %s\n", a.Name, a.OwnerType, a.OwnerID.String()[:8])
+			return nil
+		},
+	}
+}
+
+func attachmentContentUsed(attachments []models.Attachment, sum string) bool {
+	for _, a := range attachments {
+		if a.SHA256 == sum {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBytes shows a size as B, KB or MB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1f MB", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1f KB", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d B", n)
+}
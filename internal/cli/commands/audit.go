@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terzigolu/josepshbrain-go/internal/audit"
+	"github.com/urfave/cli/v2"
+)
+
+// NewAuditCommand creates the 'audit' command for inspecting the MCP tool call log.
+func NewAuditCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "audit",
+		Usage: "Inspect the audit log of MCP tool calls",
+		Subcommands: []*cli.Command{
+			auditTailCmd(),
+			auditSearchCmd(),
+		},
+	}
+}
+
+// auditTailCmd shows the most recent audit records.
+func auditTailCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "tail",
+		Usage: "Show the most recent tool calls",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "Number of records to show", Value: 20},
+			&cli.BoolFlag{Name: "json", Usage: "Print raw JSONL records"},
+		},
+		Action: func(c *cli.Context) error {
+			return printAuditRecords(audit.Filter{}, c.Int("limit"), c.Bool("json"))
+		},
+	}
+}
+
+// auditSearchCmd filters audit records by tool, time, outcome or free text.
+func auditSearchCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search tool calls by tool, time or content",
+		ArgsUsage: "[query]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tool", Usage: "Only show calls to this tool (e.g. create_task)"},
+			&cli.StringFlag{Name: "since", Aliases: []string{"s"}, Usage: "Only show calls since: yesterday, 24h, 7d, 2006-01-02"},
+			&cli.StringFlag{Name: "outcome", Usage: "Filter by outcome (ok, error)"},
+			&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "Limit number of results (0 = all)", Value: 0},
+			&cli.BoolFlag{Name: "json", Usage: "Print raw JSONL records"},
+		},
+		Action: func(c *cli.Context) error {
+			filter := audit.Filter{
+				Tool:    c.String("tool"),
+				Outcome: c.String("outcome"),
+				Query:   strings.Join(c.Args().Slice(), " "),
+			}
+			if s := c.String("since"); s != "" {
+				since, err := parseSince(s)
+				if err != nil {
+					return err
+				}
+				filter.Since = since
+			}
+			return printAuditRecords(filter, c.Int("limit"), c.Bool("json"))
+		},
+	}
+}
+
+func printAuditRecords(filter audit.Filter, limit int, asJSON bool) error {
+	dir, err := audit.DefaultDir()
+	if err != nil {
+		return err
+	}
+	records, err := audit.Read(dir, filter)
+	if err != nil {
+		return fmt.Errorf("could not read audit log: %w", err)
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No audit records found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tCLIENT\tTOOL\tOUTCOME\tLATENCY\tENTITIES")
+	fmt.Fprintln(w, "----\t------\t----\t-------\t-------\t--------")
+	for _, r := range records {
+		client := r.Client.Name
+		if client == "" {
+			client = "-"
+		}
+		outcome := "✅"
+		if r.Outcome != "ok" {
+			outcome = "❌ " + truncateString(r.Error, 30)
+		}
+		entities := "-"
+		if len(r.EntityIDs) > 0 {
+			short := make([]string, 0, len(r.EntityIDs))
+			for _, id := range r.EntityIDs {
+				short = append(short, id[:8])
+			}
+			entities = truncateString(strings.Join(short, ","), 35)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%dms\t%s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"),
+			client,
+			r.Tool,
+			outcome,
+			r.LatencyMS,
+			entities)
+	}
+	w.Flush()
+	return nil
+}
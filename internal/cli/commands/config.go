@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
 	"github.com/urfave/cli/v2"
 )
 
@@ -15,6 +16,7 @@ func NewConfigCommand() *cli.Command {
 		Subcommands: []*cli.Command{
 			configShowCmd(),
 			configSetApiKeyCmd(),
+			configSetScanPolicyCmd(),
 		},
 	}
 }
@@ -42,6 +44,10 @@ func configShowCmd() *cli.Command {
 			} else {
 				fmt.Println("Active Project: Not set")
 			}
+
+			secretPolicy, _ := secrets.ParsePolicy(cliCfg.ScanPolicy, secrets.DefaultSecretPolicy)
+			piiPolicy, _ := secrets.ParsePolicy(cliCfg.PIIPolicy, secrets.DefaultPIIPolicy)
+			fmt.Printf("Scan Policy:    secrets=%s, pii=%s\n", secretPolicy, piiPolicy)
 			fmt.Println("-----------------------")
 
 			return nil
@@ -76,3 +82,45 @@ func configSetApiKeyCmd() *cli.Command {
 		},
 	}
 }
+
+// configSetScanPolicyCmd sets what happens when a secret or PII is found in stored content.
+func configSetScanPolicyCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "set-scan-policy",
+		Usage:     "Set the policy for secrets (or PII with --pii) found in memories and notes: block, redact or warn",
+		ArgsUsage: "[block|redact|warn]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "pii",
+				Usage: "Set the policy for personal data (emails) instead of secrets",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("policy is required (block, redact or warn)")
+			}
+			policy, err := secrets.ParsePolicy(c.Args().First(), "")
+			if err != nil || policy == "" {
+				return fmt.Errorf("invalid scan policy '%s' (use block, redact or warn)", c.Args().First())
+			}
+
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				cfg = &config.Config{}
+			}
+			kind := "secrets"
+			if c.Bool("pii") {
+				cfg.PIIPolicy = string(policy)
+				kind = "PII"
+			} else {
+				cfg.ScanPolicy = string(policy)
+			}
+			if err := config.SaveConfig(cfg); err != nil {
+				return fmt.Errorf("could not save config: %w", err)
+			}
+
+			fmt.Printf("✅ Scan policy for %s set to '%s'.\n", kind, policy)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// NewExportCommand creates the 'export' command group.
+func NewExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export tasks, memories and decisions to other formats",
+		Subcommands: []*cli.Command{
+			exportVaultCmd(),
+			exportHTMLCmd(),
+			exportTaskwarriorCmd(),
+			exportICalCmd(),
+		},
+	}
+}
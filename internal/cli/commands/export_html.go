@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/site"
+	"github.com/urfave/cli/v2"
+)
+
+// exportHTMLCmd writes a project as a static HTML site.
+func exportHTMLCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "html",
+		Usage:     "Export a project as a static HTML site (dashboard, tasks, memories, ADR log)",
+		ArgsUsage: "<project> <outdir>",
+		Description: "Writes index.html with stats and a burndown chart, a page per task with notes and\n" +
+			"subtasks, a memory index with search, and the decision log. The site needs no server:\n" +
+			"open index.html from disk or publish the directory on a file share.",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "days", Aliases: []string{"d"}, Usage: "Days covered by the burndown chart", Value: 30},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of parallel API requests", Value: 4},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 2 {
+				return fmt.Errorf("usage: ramorie export html <project> <outdir>")
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			client := api.NewClient()
+			projectID, err := resolveProjectArg(client, c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			project, err := client.GetProject(projectID)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			s := &site.Site{Project: *project, BurndownDays: c.Int("days")}
+			tasks, err := client.ListTasks(projectID, "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if s.Memories, err = client.ListMemories(projectID, ""); err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			var mu sync.Mutex
+			var firstErr error
+			fail := func(err error) {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+			s.Tasks = make([]site.Task, len(tasks))
+			forEachLimited(concurrency, len(tasks), func(i int) {
+				t := site.Task{Task: tasks[i]}
+				id := tasks[i].ID.String()
+				var err error
+				if t.Annotations, err = client.ListAnnotations(id); err != nil {
+					fail(err)
+					return
+				}
+				if t.Subtasks, err = client.ListSubtasks(id); err != nil {
+					fail(err)
+					return
+				}
+				if t.Memories, err = client.ListTaskMemories(id); err != nil {
+					fail(err)
+					return
+				}
+				s.Tasks[i] = t
+			})
+			if firstErr != nil {
+				fmt.Println(apierrors.ParseAPIError(firstErr))
+				return firstErr
+			}
+
+			decisions, err := client.ListDecisions("", "", 0)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			for _, d := range decisions {
+				// Decisions without a project belong in every project's log
+				if d.ProjectID == nil || *d.ProjectID == projectID {
+					s.Decisions = append(s.Decisions, d)
+				}
+			}
+
+			dir := c.Args().Get(1)
+			n, err := site.Write(dir, s)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("📤 Exported %s to %s (%d files, %d tasks, %d memories, %d decisions)\n",
+				project.Name, dir, n, len(s.Tasks), len(s.Memories), len(s.Decisions))
+			fmt.Printf("   Open %s\n", filepath.Join(dir, "index.html"))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/ical"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/worklog"
+	"github.com/urfave/cli/v2"
+)
+
+// exportICalCmd writes tasks and work sessions as an iCalendar file.
+func exportICalCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "ical",
+		Aliases:   []string{"ics"},
+		Usage:     "Export tasks (VTODO) and work sessions (VEVENT) as an iCalendar file",
+		ArgsUsage: "[file.ics]",
+		Description: "Writes to stdout unless a file is given. Tasks become to-dos with their due date,\n" +
+			"priority and status. Work sessions are the stretches between `ramorie task start` and\n" +
+			"`task stop`/`task done` run on this machine; tasks started through the MCP tools\n" +
+			"(start_task/stop_task) are not recorded. For a feed calendar apps can subscribe\n" +
+			"to, see /v1/calendar.ics on the self-hosted server.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Only this project (name or ID); default all projects"},
+			&cli.BoolFlag{Name: "due-only", Usage: "Only tasks with a due date"},
+			&cli.BoolFlag{Name: "no-sessions", Usage: "Leave out work sessions"},
+			&cli.StringFlag{Name: "since", Usage: "Only work sessions since (7d, 2006-01-02, ...)"},
+		},
+		Action: func(c *cli.Context) error {
+			var since time.Time
+			if c.String("since") != "" {
+				var err error
+				if since, err = parseSince(c.String("since")); err != nil {
+					return err
+				}
+			}
+			client := api.NewClient()
+			projectID := c.String("project")
+			var err error
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			}
+			projects, err := client.ListProjects()
+			if err != nil {
+				return fmt.Errorf("could not fetch projects: %w", err)
+			}
+			names := map[string]string{}
+			name := "ramorie"
+			for _, p := range projects {
+				names[p.ID.String()] = p.Name
+				if p.ID.String() == projectID {
+					name = "ramorie · " + p.Name
+				}
+			}
+			// All tasks, so work sessions recorded with short IDs can be resolved
+			tasks, err := client.ListTasks("", "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			cal := ical.Calendar{Name: name}
+			for _, t := range tasks {
+				if projectID != "" && t.ProjectID.String() != projectID || c.Bool("due-only") && t.DueDate == nil {
+					continue
+				}
+				cal.Todos = append(cal.Todos, taskTodo(t, names[t.ProjectID.String()]))
+			}
+
+			skipped := 0
+			if !c.Bool("no-sessions") {
+				path, err := worklog.DefaultPath()
+				if err != nil {
+					return err
+				}
+				events, err := worklog.Read(path)
+				if err != nil {
+					return err
+				}
+				for _, s := range worklog.Sessions(events, time.Now()) {
+					if s.Open || s.Start.Before(since) {
+						continue
+					}
+					t := findTaskByPrefix(tasks, s.TaskID)
+					if t == nil {
+						// Deleted since, or started against another account
+						skipped++
+						continue
+					}
+					if projectID != "" && t.ProjectID.String() != projectID {
+						continue
+					}
+					project := names[t.ProjectID.String()]
+					ev := ical.Event{
+						UID:         fmt.Sprintf("%s-%d@ramorie", t.ID, s.Start.Unix()),
+						Summary:     t.Title,
+						Description: fmt.Sprintf("Worked on task %s for %s", t.ID.String()[:8], sessionDuration(s.Duration())),
+						Start:       s.Start,
+						End:         s.End,
+					}
+					if project != "" {
+						ev.Categories = []string{project}
+					}
+					cal.Events = append(cal.Events, ev)
+				}
+			}
+
+			var b bytes.Buffer
+			if err := cal.Encode(&b, time.Now()); err != nil {
+				return err
+			}
+			file := c.Args().First()
+			if file == "" || file == "-" {
+				_, err = os.Stdout.Write(b.Bytes())
+				return err
+			}
+			if err := os.WriteFile(file, b.Bytes(), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("📅 Exported %d tasks and %d work sessions to %s\n", len(cal.Todos), len(cal.Events), file)
+			if skipped > 0 {
+				fmt.Printf("⚠️  %d work sessions belong to tasks that no longer exist\n", skipped)
+			}
+			return nil
+		},
+	}
+}
+
+// taskTodo maps a task onto a VTODO, filed under its project and tags
+func taskTodo(t models.Task, project string) ical.Todo {
+	todo := ical.Todo{
+		UID:         t.ID.String() + "@ramorie",
+		Summary:     t.Title,
+		Description: strings.TrimSpace(t.Description),
+		Status:      t.Status,
+		Priority:    t.Priority,
+		Created:     t.CreatedAt,
+		Modified:    t.UpdatedAt,
+		Due:         t.DueDate,
+		Completed:   t.CompletedAt,
+	}
+	if project != "" {
+		todo.Categories = append(todo.Categories, project)
+	}
+	todo.Categories = append(todo.Categories, getTagsAsStrings(t.Tags)...)
+	return todo
+}
+
+// sessionDuration formats a work session length as 1h 20m
+func sessionDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh %dm", h, m)
+}
+
+// findTaskByPrefix resolves a work log task ID, which older logs may hold as
+// typed; a prefix shared by several tasks resolves to none of them
+func findTaskByPrefix(tasks []models.Task, id string) *models.Task {
+	id = strings.ToLower(id)
+	if id == "" {
+		return nil
+	}
+	var found *models.Task
+	for i := range tasks {
+		if !strings.HasPrefix(tasks[i].ID.String(), id) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = &tasks[i]
+	}
+	return found
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/fakeapi"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/worklog"
+	"github.com/urfave/cli/v2"
+)
+
+func TestTaskStartRecordsFullID(t *testing.T) {
+	project := uuid.MustParse("0e000000-0000-4000-8000-000000000001")
+	tasks := []models.Task{
+		{ID: uuid.MustParse("aaaaaaa1-0000-4000-8000-000000000001"), ProjectID: project, Title: "Configure ingress", Status: "TODO"},
+		{ID: uuid.MustParse("aaaaaaa2-0000-4000-8000-000000000002"), ProjectID: project, Title: "Rotate keys", Status: "TODO"},
+	}
+	backend := fakeapi.NewServer(&fakeapi.Store{
+		Projects: []models.Project{{ID: project, Name: "orion"}},
+		Tasks:    append([]models.Task{}, tasks...),
+	})
+	t.Cleanup(backend.Close)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("API_BASE_URL", backend.URL+"/v1")
+
+	if err := (&cli.App{Commands: []*cli.Command{taskStartCmd()}}).Run([]string{"ramorie", "start", "aaaaaaa2"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := worklog.DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := worklog.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].TaskID != tasks[1].ID.String() {
+		t.Fatalf("work log = %+v, want a start for %s", events, tasks[1].ID)
+	}
+
+	if got := findTaskByPrefix(tasks, "aaaaaaa"); got != nil {
+		t.Errorf("a prefix shared by two tasks resolved to %s", got.ID)
+	}
+	if got := findTaskByPrefix(tasks, "AAAAAAA1"); got == nil || got.ID != tasks[0].ID {
+		t.Errorf("findTaskByPrefix(AAAAAAA1) = %v", got)
+	}
+}
@@ -0,0 +1,488 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// vaultFrontMatter is the header of every exported note. Type and ID mark
+// the notes the exporter owns, so --prune never touches hand-written notes.
+type vaultFrontMatter struct {
+	ID        string   `yaml:"id"`
+	Type      string   `yaml:"type"`
+	Project   string   `yaml:"project,omitempty"`
+	ADR       string   `yaml:"adr,omitempty"`
+	Status    string   `yaml:"status,omitempty"`
+	Priority  string   `yaml:"priority,omitempty"`
+	Area      string   `yaml:"area,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	CreatedAt string   `yaml:"created_at,omitempty"`
+	UpdatedAt string   `yaml:"updated_at,omitempty"`
+}
+
+// vaultExport collects rendered notes by their path relative to the vault root
+type vaultExport struct {
+	files map[string]string
+}
+
+// exportVaultCmd writes memories, tasks and decisions as an Obsidian-compatible vault.
+func exportVaultCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "vault",
+		Usage:     "Export memories, tasks and decisions as a markdown (Obsidian) vault",
+		ArgsUsage: "<dir>",
+		Description: "Writes <Project>/Memories, <Project>/Tasks and Decisions notes with YAML front matter.\n" +
+			"Memory-task links become [[wikilinks]]. Re-running only rewrites notes that changed;\n" +
+			"--prune also removes notes of deleted or renamed items.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Only export this project (name or ID). Defaults to all projects.",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of parallel API requests",
+				Value: 4,
+			},
+			&cli.BoolFlag{
+				Name:  "prune",
+				Usage: "Remove previously exported notes that no longer exist",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("output directory is required")
+			}
+			dir := c.Args().First()
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+
+			client := api.NewClient()
+
+			var projects []models.Project
+			if arg := c.String("project"); arg != "" {
+				id, err := resolveProjectArg(client, arg)
+				if err != nil {
+					return err
+				}
+				project, err := client.GetProject(id)
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				projects = []models.Project{*project}
+			} else {
+				var err error
+				if projects, err = client.ListProjects(); err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+			}
+
+			export := &vaultExport{files: map[string]string{}}
+			for _, p := range projects {
+				if err := export.addProject(client, p, concurrency); err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+			}
+
+			decisions, err := client.ListDecisions("", "", 0)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			names := map[string]string{}
+			for _, p := range projects {
+				names[p.ID.String()] = p.Name
+			}
+			for _, d := range decisions {
+				if c.String("project") != "" && d.ProjectID != nil && *d.ProjectID != projects[0].ID.String() {
+					continue
+				}
+				project := ""
+				if d.ProjectID != nil {
+					project = names[*d.ProjectID]
+				}
+				export.addDecision(d, project)
+			}
+
+			written, unchanged, err := export.write(dir)
+			if err != nil {
+				return err
+			}
+			removed := 0
+			if c.Bool("prune") {
+				// A single-project export must not prune the other projects' notes:
+				// Decisions is shared, so only this project's decisions go there
+				roots := map[string]func(vaultFrontMatter) bool{dir: nil}
+				if c.String("project") != "" {
+					name := projects[0].Name
+					roots = map[string]func(vaultFrontMatter) bool{
+						filepath.Join(dir, noteFileName(name)): nil,
+						filepath.Join(dir, "Decisions"):        func(fm vaultFrontMatter) bool { return fm.Project == name },
+					}
+				}
+				for root, owned := range roots {
+					n, err := export.prune(dir, root, owned)
+					if err != nil {
+						return err
+					}
+					removed += n
+				}
+			}
+
+			fmt.Printf("📤 Exported %d notes to %s (%d written, %d unchanged", len(export.files), dir, written, unchanged)
+			if c.Bool("prune") {
+				fmt.Printf(", %d removed", removed)
+			}
+			fmt.Println(")")
+			return nil
+		},
+	}
+}
+
+// addProject renders a project's index, tasks and memories
+func (e *vaultExport) addProject(client *api.Client, p models.Project, concurrency int) error {
+	tasks, err := client.ListTasks(p.ID.String(), "")
+	if err != nil {
+		return err
+	}
+	memories, err := client.ListMemories(p.ID.String(), "")
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+
+	annotations := make([][]models.Annotation, len(tasks))
+	subtasks := make([][]models.Subtask, len(tasks))
+	taskMemories := make([][]models.Memory, len(tasks))
+	forEachLimited(concurrency, len(tasks), func(i int) {
+		var err error
+		id := tasks[i].ID.String()
+		if annotations[i], err = client.ListAnnotations(id); err != nil {
+			fail(err)
+			return
+		}
+		if subtasks[i], err = client.ListSubtasks(id); err != nil {
+			fail(err)
+			return
+		}
+		if taskMemories[i], err = client.ListTaskMemories(id); err != nil {
+			fail(err)
+		}
+	})
+
+	memoryTasks := make([][]models.Task, len(memories))
+	forEachLimited(concurrency, len(memories), func(i int) {
+		var err error
+		if memoryTasks[i], err = client.ListMemoryTasks(memories[i].ID.String()); err != nil {
+			fail(err)
+		}
+	})
+	if firstErr != nil {
+		return firstErr
+	}
+
+	folder := noteFileName(p.Name)
+	for i, t := range tasks {
+		e.files[filepath.Join(folder, "Tasks", taskNoteName(t)+".md")] = renderTaskNote(p, t, annotations[i], subtasks[i], taskMemories[i])
+	}
+	for i, m := range memories {
+		e.files[filepath.Join(folder, "Memories", memoryNoteName(m)+".md")] = renderMemoryNote(p, m, memoryTasks[i])
+	}
+	e.files[filepath.Join(folder, folder+".md")] = renderProjectNote(p, tasks, memories)
+	return nil
+}
+
+func (e *vaultExport) addDecision(d api.Decision, project string) {
+	e.files[filepath.Join("Decisions", decisionNoteName(d)+".md")] = renderDecisionNote(d, project)
+}
+
+// write stores every note whose content differs from what is on disk
+func (e *vaultExport) write(dir string) (written, unchanged int, err error) {
+	for rel, content := range e.files {
+		path := filepath.Join(dir, rel)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, []byte(content)) {
+			unchanged++
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, unchanged, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, unchanged, err
+		}
+		written++
+	}
+	return written, unchanged, nil
+}
+
+// prune removes exported notes (identified by their front matter) under root
+// that were not generated this run. With owned set, only the notes it accepts
+// are removed.
+func (e *vaultExport) prune(dir, root string, owned func(vaultFrontMatter) bool) (int, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, nil
+	}
+	removed := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := e.files[rel]; ok {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		header, _, err := splitFrontMatter(string(data))
+		if err != nil || header == "" {
+			return nil
+		}
+		var fm vaultFrontMatter
+		if yaml.Unmarshal([]byte(header), &fm) != nil || fm.ID == "" || owned != nil && !owned(fm) {
+			return nil
+		}
+		switch fm.Type {
+		case "memory", "task", "decision", "project":
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+func renderMemoryNote(p models.Project, m models.Memory, tasks []models.Task) string {
+	var sb strings.Builder
+	writeVaultFrontMatter(&sb, vaultFrontMatter{
+		ID:        m.ID.String(),
+		Type:      "memory",
+		Project:   p.Name,
+		Tags:      getTagsAsStrings(m.Tags),
+		CreatedAt: vaultTime(m.CreatedAt),
+		UpdatedAt: vaultTime(m.UpdatedAt),
+	})
+	sb.WriteString(strings.TrimSpace(m.Content) + "\n")
+
+	if len(tasks) > 0 {
+		sb.WriteString("\n## Tasks\n\n")
+		for _, t := range tasks {
+			sb.WriteString("- [[" + taskNoteName(t) + "]]\n")
+		}
+	}
+	return sb.String()
+}
+
+func renderTaskNote(p models.Project, t models.Task, annotations []models.Annotation, subtasks []models.Subtask, memories []models.Memory) string {
+	var sb strings.Builder
+	writeVaultFrontMatter(&sb, vaultFrontMatter{
+		ID:        t.ID.String(),
+		Type:      "task",
+		Project:   p.Name,
+		Status:    t.Status,
+		Priority:  t.Priority,
+		Tags:      getTagsAsStrings(t.Tags),
+		CreatedAt: vaultTime(t.CreatedAt),
+		UpdatedAt: vaultTime(t.UpdatedAt),
+	})
+	sb.WriteString("# " + strings.TrimSpace(t.Title) + "\n")
+	if desc := strings.TrimSpace(t.Description); desc != "" && desc != strings.TrimSpace(t.Title) {
+		sb.WriteString("\n" + desc + "\n")
+	}
+
+	if len(subtasks) > 0 {
+		sb.WriteString("\n## Subtasks\n\n")
+		for _, st := range subtasks {
+			check := " "
+			if st.Completed != 0 {
+				check = "x"
+			}
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", check, strings.TrimSpace(st.Description)))
+		}
+	}
+
+	if len(annotations) > 0 {
+		sb.WriteString("\n## Annotations\n")
+		for _, a := range annotations {
+			sb.WriteString("\n### " + a.CreatedAt.UTC().Format("2006-01-02 15:04") + " UTC\n\n")
+			sb.WriteString(strings.TrimSpace(a.Content) + "\n")
+		}
+	}
+
+	if len(memories) > 0 {
+		sb.WriteString("\n## Memories\n\n")
+		for _, m := range memories {
+			sb.WriteString("- [[" + memoryNoteName(m) + "]]\n")
+		}
+	}
+	return sb.String()
+}
+
+func renderProjectNote(p models.Project, tasks []models.Task, memories []models.Memory) string {
+	var sb strings.Builder
+	writeVaultFrontMatter(&sb, vaultFrontMatter{
+		ID:        p.ID.String(),
+		Type:      "project",
+		CreatedAt: vaultTime(p.CreatedAt),
+	})
+	sb.WriteString("# " + p.Name + "\n")
+	if desc := strings.TrimSpace(p.Description); desc != "" {
+		sb.WriteString("\n" + desc + "\n")
+	}
+
+	for _, group := range []struct{ title, status string }{
+		{"In progress", "IN_PROGRESS"},
+		{"To do", "TODO"},
+		{"Completed", "COMPLETED"},
+	} {
+		var lines []string
+		for _, t := range tasks {
+			if t.Status == group.status {
+				lines = append(lines, "- [["+taskNoteName(t)+"]]")
+			}
+		}
+		if len(lines) > 0 {
+			sb.WriteString(fmt.Sprintf("\n## %s (%d)\n\n%s\n", group.title, len(lines), strings.Join(lines, "\n")))
+		}
+	}
+
+	if len(memories) > 0 {
+		sb.WriteString(fmt.Sprintf("\n## Memories (%d)\n\n", len(memories)))
+		for _, m := range memories {
+			sb.WriteString("- [[" + memoryNoteName(m) + "]]\n")
+		}
+	}
+	return sb.String()
+}
+
+func renderDecisionNote(d api.Decision, project string) string {
+	var sb strings.Builder
+	writeVaultFrontMatter(&sb, vaultFrontMatter{
+		ID:        d.ID,
+		Type:      "decision",
+		Project:   project,
+		ADR:       d.ADRNumber,
+		Status:    d.Status,
+		Area:      d.Area,
+		CreatedAt: vaultTime(d.CreatedAt),
+		UpdatedAt: vaultTime(d.UpdatedAt),
+	})
+	title := d.Title
+	if d.ADRNumber != "" {
+		title = d.ADRNumber + ": " + title
+	}
+	sb.WriteString("# " + title + "\n")
+	if desc := strings.TrimSpace(d.Description); desc != "" {
+		sb.WriteString("\n" + desc + "\n")
+	}
+	for _, section := range []struct {
+		title string
+		text  *string
+	}{
+		{"Context", d.Context},
+		{"Decision", d.Content},
+		{"Consequences", d.Consequences},
+	} {
+		if section.text != nil && strings.TrimSpace(*section.text) != "" {
+			sb.WriteString("\n## " + section.title + "\n\n" + strings.TrimSpace(*section.text) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeVaultFrontMatter(sb *strings.Builder, fm vaultFrontMatter) {
+	sb.WriteString("---\n")
+	enc := yaml.NewEncoder(sb)
+	enc.SetIndent(2)
+	_ = enc.Encode(fm)
+	_ = enc.Close()
+	sb.WriteString("---\n\n")
+}
+
+// vaultTime formats timestamps in UTC so re-exports on other machines are identical
+func vaultTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Note names carry the short ID so titles can repeat without clashing
+
+func taskNoteName(t models.Task) string {
+	return noteFileName(t.Title) + " (" + t.ID.String()[:8] + ")"
+}
+
+func memoryNoteName(m models.Memory) string {
+	return noteFileName(models.MemoryTitle(m.Content)) + " (" + m.ID.String()[:8] + ")"
+}
+
+func decisionNoteName(d api.Decision) string {
+	if d.ADRNumber != "" {
+		return d.ADRNumber + " " + noteFileName(d.Title)
+	}
+	return noteFileName(d.Title) + " (" + d.ID[:8] + ")"
+}
+
+// noteFileName makes a title safe as a file name and wikilink target
+func noteFileName(title string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '#', '^', '[', ']', '\n', '\r', '\t':
+			return ' '
+		}
+		return r
+	}, title)
+	clean = strings.Join(strings.Fields(clean), " ")
+	if runes := []rune(clean); len(runes) > 60 {
+		clean = strings.TrimSpace(string(runes[:60]))
+	}
+	clean = strings.Trim(clean, ".")
+	if clean == "" {
+		return "Untitled"
+	}
+	return clean
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/fakeapi"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+)
+
+func TestNoteFileName(t *testing.T) {
+	tests := map[string]string{
+		"Fix: login/logout [urgent]": "Fix login logout urgent",
+		"  ## ":                      "Untitled",
+		"v1.2.":                      "v1.2",
+		strings.Repeat("a", 70):      strings.Repeat("a", 60),
+	}
+	for in, want := range tests {
+		if got := noteFileName(in); got != want {
+			t.Errorf("noteFileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderTaskNote(t *testing.T) {
+	created := time.Date(2025, 1, 2, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	project := models.Project{Name: "orion"}
+	task := models.Task{
+		ID:        uuid.MustParse("aaaaaaa1-0000-4000-8000-000000000001"),
+		Title:     "Configure ingress",
+		Status:    "TODO",
+		Priority:  "H",
+		Tags:      []interface{}{"infra"},
+		CreatedAt: created,
+	}
+	memory := models.Memory{ID: uuid.MustParse("cccccccc-0000-4000-8000-000000000001"), Content: "## Traefik\nneeds labels"}
+
+	got := renderTaskNote(project, task,
+		[]models.Annotation{{Content: "started", CreatedAt: created}},
+		[]models.Subtask{{Description: "write config", Completed: 1}},
+		[]models.Memory{memory})
+
+	for _, want := range []string{
+		"---\nid: aaaaaaa1-0000-4000-8000-000000000001\ntype: task\nproject: orion\n",
+		"tags:\n  - infra\ncreated_at: \"2025-01-02T09:00:00Z\"\n---\n\n# Configure ingress\n",
+		"- [x] write config\n",
+		"### 2025-01-02 09:00 UTC\n\nstarted\n",
+		"## Memories\n\n- [[Traefik (cccccccc)]]\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("task note missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestVaultPruneSingleProject(t *testing.T) {
+	orion := models.Project{ID: uuid.New(), Name: "orion"}
+	vega := models.Project{ID: uuid.New(), Name: "vega"}
+	decision := func(id, title string, p models.Project) api.Decision {
+		projectID := p.ID.String()
+		return api.Decision{ID: id, Title: title, Status: "ACCEPTED", ProjectID: &projectID}
+	}
+	store := &fakeapi.Store{
+		Projects: []models.Project{orion, vega},
+		Decisions: []api.Decision{
+			decision("d0000001-orion", "Use Postgres", orion),
+			decision("d0000002-orion", "Drop MySQL", orion),
+			decision("d0000003-vega", "Use Redis", vega),
+		},
+	}
+	backend := fakeapi.NewServer(store)
+	t.Cleanup(backend.Close)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("API_BASE_URL", backend.URL+"/v1")
+
+	dir := t.TempDir()
+	export := func(args ...string) {
+		t.Helper()
+		app := &cli.App{Commands: []*cli.Command{exportVaultCmd()}}
+		if err := app.Run(append([]string{"ramorie", "vault"}, append(args, dir)...)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, "Decisions", name))
+		return err == nil
+	}
+
+	export()
+	for _, name := range []string{"Use Postgres (d0000001).md", "Drop MySQL (d0000002).md", "Use Redis (d0000003).md"} {
+		if !exists(name) {
+			t.Fatalf("%s was not exported", name)
+		}
+	}
+
+	// Dropping an orion decision prunes only that note, not vega's
+	store.Decisions = append(store.Decisions[:1:1], store.Decisions[2])
+	export("--prune", "--project", "orion")
+	if !exists("Use Postgres (d0000001).md") || exists("Drop MySQL (d0000002).md") {
+		t.Error("the deleted orion decision should be pruned and the other kept")
+	}
+	if !exists("Use Redis (d0000003).md") {
+		t.Error("a single-project export pruned another project's decision")
+	}
+}
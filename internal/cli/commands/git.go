@@ -0,0 +1,336 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/gitlink"
+	"github.com/terzigolu/josepshbrain-go/internal/worklog"
+	"github.com/urfave/cli/v2"
+)
+
+// hookTimeout bounds API calls made from git hooks, so an unreachable
+// server does not hold up a commit
+const hookTimeout = 5 * time.Second
+
+// NewGitCommand creates the 'git' command group.
+func NewGitCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "git",
+		Usage: "Link git commits and branches to tasks",
+		Subcommands: []*cli.Command{
+			gitInstallHooksCmd(),
+			gitSyncCmd(),
+			gitHookCmd(),
+		},
+	}
+}
+
+// gitInstallHooksCmd installs the commit hooks into the current repository.
+func gitInstallHooksCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "install-hooks",
+		Usage: "Install git hooks that link commits to the active task",
+		Description: "prepare-commit-msg adds a '" + gitlink.TrailerKey + ": <short-id>' trailer for the active task\n" +
+			"(see 'ramorie task start'), and post-commit records the commit hash and subject as an\n" +
+			"annotation on that task. Hooks never block a commit when the API is unreachable; run\n" +
+			"'ramorie git sync' later to backfill annotations.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "force", Usage: "Replace existing hooks (they are kept as <hook>.local and still run)"},
+		},
+		Action: func(c *cli.Context) error {
+			dir, err := gitlink.HooksDir(".")
+			if err != nil {
+				return err
+			}
+			// Prefer ramorie from PATH so the hooks survive upgrades
+			ramorie := "ramorie"
+			if _, err := exec.LookPath(ramorie); err != nil {
+				if ramorie, err = os.Executable(); err != nil {
+					return err
+				}
+			}
+			for _, hook := range gitlink.Hooks {
+				unchanged, err := gitlink.InstallHook(dir, hook, ramorie, c.Bool("force"))
+				if err != nil {
+					return err
+				}
+				if unchanged {
+					fmt.Printf("= %s is up to date\n", hook)
+				} else {
+					fmt.Printf("✅ Installed %s\n", hook)
+				}
+			}
+			fmt.Printf("🔗 Commits will be linked to the active task (hooks in %s).\n", dir)
+			return nil
+		},
+	}
+}
+
+// gitHookCmd is what the installed hooks run.
+func gitHookCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "hook",
+		Usage:     "Run a ramorie git hook (called by the installed hooks)",
+		ArgsUsage: "<hook> [hook args...]",
+		Hidden:    true,
+		Action: func(c *cli.Context) error {
+			client := api.NewClient()
+			client.HTTPClient.Timeout = hookTimeout
+			var err error
+			switch c.Args().First() {
+			case "prepare-commit-msg":
+				err = prepareCommitMsg(client, c.Args().Get(1), c.Args().Get(2))
+			case "post-commit":
+				err = postCommit(client)
+			default:
+				return fmt.Errorf("unknown hook %q", c.Args().First())
+			}
+			if err != nil {
+				// Warn only: the commit goes ahead either way
+				fmt.Fprintf(os.Stderr, "⚠️  ramorie: %v\n", err)
+			}
+			return nil
+		},
+	}
+}
+
+// prepareCommitMsg adds the trailer for the active task to the message file
+func prepareCommitMsg(client *api.Client, file, source string) error {
+	if file == "" || source == "merge" {
+		return nil
+	}
+	task, err := client.GetActiveTask()
+	if err != nil {
+		return fmt.Errorf("could not get the active task: %w", err)
+	}
+	if task == nil {
+		return nil
+	}
+	return gitlink.AddTrailer(".", file, task.ID.String()[:8])
+}
+
+// postCommit annotates the tasks named by the trailers of the new commit
+func postCommit(client *api.Client) error {
+	commits, err := gitlink.Log(".", "-1", "HEAD")
+	if err != nil {
+		return err
+	}
+	for _, commit := range commits {
+		text, err := guardContent("commit "+commit.Short(), commit.Annotation())
+		if err != nil {
+			return err
+		}
+		for _, taskID := range commit.TaskIDs {
+			if _, err := client.CreateAnnotation(taskID, text); err != nil {
+				return fmt.Errorf("could not annotate task %s: %w", taskID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// gitSyncCmd backfills commit annotations from the git history.
+func gitSyncCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "sync",
+		Usage:     "Annotate tasks with the commits that name them in a " + gitlink.TrailerKey + " trailer",
+		ArgsUsage: "[revision-range]",
+		Description: "Reads 'git log' of the current branch (or the given range) and adds the commit\n" +
+			"annotations that are missing, e.g. for commits made while offline or before the hooks\n" +
+			"were installed. Commits already recorded on a task are skipped.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "since", Usage: "Only commits since (7d, 2006-01-02, ...)"},
+			&cli.BoolFlag{Name: "all", Usage: "Read all branches instead of the current one"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of tasks synced in parallel", Value: 4},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Show the annotations that would be added"},
+		},
+		Action: func(c *cli.Context) error {
+			var args []string
+			if c.String("since") != "" {
+				since, err := parseSince(c.String("since"))
+				if err != nil {
+					return err
+				}
+				args = append(args, "--since="+since.Format(time.RFC3339))
+			}
+			if c.Bool("all") {
+				args = append(args, "--all")
+			}
+			args = append(args, c.Args().Slice()...)
+			commits, err := gitlink.Log(".", args...)
+			if err != nil {
+				return err
+			}
+
+			// Oldest first, so annotations are added in commit order
+			var taskIDs []string
+			byTask := map[string][]gitlink.Commit{}
+			for i := len(commits) - 1; i >= 0; i-- {
+				for _, id := range commits[i].TaskIDs {
+					if _, ok := byTask[id]; !ok {
+						taskIDs = append(taskIDs, id)
+					}
+					byTask[id] = append(byTask[id], commits[i])
+				}
+			}
+			if len(taskIDs) == 0 {
+				fmt.Println("No commits with a " + gitlink.TrailerKey + " trailer found.")
+				return nil
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+
+			// Subjects are stored as annotations, so they pass the secret scan first
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			texts := map[string]string{}
+			blocked := map[string]error{}
+			for _, commit := range commits {
+				if texts[commit.Hash], err = guardLabelled(scanner, "commit "+commit.Short(), commit.Annotation()); err != nil {
+					blocked[commit.Hash] = err
+				}
+			}
+
+			client := api.NewClient()
+			missing := make([][]gitlink.Commit, len(taskIDs))
+			added := make([]int, len(taskIDs))
+			errs := make([]error, len(taskIDs))
+			forEachLimited(concurrency, len(taskIDs), func(i int) {
+				notes, err := client.ListAnnotations(taskIDs[i])
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				contents := make([]string, len(notes))
+				for j, n := range notes {
+					contents[j] = n.Content
+				}
+				for _, commit := range byTask[taskIDs[i]] {
+					if commit.Annotated(contents) {
+						continue
+					}
+					if err := blocked[commit.Hash]; err != nil {
+						errs[i] = err
+						continue
+					}
+					missing[i] = append(missing[i], commit)
+				}
+				if c.Bool("dry-run") {
+					return
+				}
+				for _, commit := range missing[i] {
+					if _, err := client.CreateAnnotation(taskIDs[i], texts[commit.Hash]); err != nil {
+						errs[i] = err
+						return
+					}
+					added[i]++
+				}
+			})
+
+			total, recorded, tasks, failed := 0, 0, 0, 0
+			for i, id := range taskIDs {
+				recorded += len(byTask[id]) - len(missing[i])
+				if errs[i] != nil {
+					failed++
+					if c.Bool("dry-run") {
+						fmt.Printf("  ! %s: %s\n", id, apierrors.ParseAPIError(errs[i]))
+					} else {
+						fmt.Printf("❌ %s: %s\n", id, apierrors.ParseAPIError(errs[i]))
+					}
+				}
+				if c.Bool("dry-run") {
+					for _, commit := range missing[i] {
+						fmt.Printf("  + %s %s\n", id, truncateString(texts[commit.Hash], 70))
+					}
+					added[i] = len(missing[i])
+				}
+				if added[i] > 0 {
+					total += added[i]
+					tasks++
+				}
+			}
+			if c.Bool("dry-run") {
+				fmt.Printf("\nDry run: %d annotations would be added to %d tasks, %d commits already recorded.\n", total, tasks, recorded)
+			} else {
+				fmt.Printf("🔗 Added %d commit annotations to %d tasks, %d commits already recorded.\n", total, tasks, recorded)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d tasks failed to sync", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// taskBranchCmd creates a git branch named after a task.
+func taskBranchCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "branch",
+		Usage:     "Create and switch to a git branch named after a task",
+		ArgsUsage: "[task-id]",
+		Description: "The branch is named <prefix><short-id>-<title>, e.g. 'feat/aaaaaaa1-configure-traefik-ingress'.\n" +
+			"An existing branch of that name is switched to instead.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "prefix", Usage: "Branch name prefix, e.g. feat/"},
+			&cli.BoolFlag{Name: "no-checkout", Usage: "Only create the branch"},
+			&cli.BoolFlag{Name: "start", Usage: "Also start the task, so commits get its trailer"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("task ID is required")
+			}
+			client := api.NewClient()
+			task, err := client.GetTask(c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			name := gitlink.BranchName(c.String("prefix"), task.ID.String(), task.Title)
+			if _, err := gitlink.Git(".", "check-ref-format", "--branch", name); err != nil {
+				return fmt.Errorf("invalid branch name %q: %w", name, err)
+			}
+
+			_, err = gitlink.Git(".", "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+			exists := err == nil
+			switch {
+			case exists && c.Bool("no-checkout"):
+				fmt.Printf("= Branch %s already exists\n", name)
+			case exists:
+				if _, err := gitlink.Git(".", "checkout", name); err != nil {
+					return err
+				}
+				fmt.Printf("🌿 Switched to existing branch %s\n", name)
+			case c.Bool("no-checkout"):
+				if _, err := gitlink.Git(".", "branch", name); err != nil {
+					return err
+				}
+				fmt.Printf("🌿 Created branch %s\n", name)
+			default:
+				if _, err := gitlink.Git(".", "checkout", "-b", name); err != nil {
+					return err
+				}
+				fmt.Printf("🌿 Switched to new branch %s\n", name)
+			}
+
+			if c.Bool("start") {
+				taskID := task.ID.String()
+				if _, err := client.StartTask(taskID); err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				recordWork(taskID, worklog.Start)
+				fmt.Printf("🚀 Task %s is now ACTIVE and IN_PROGRESS.\n", taskID[:8])
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// NewImportCommand creates the 'import' command group.
+func NewImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Import tasks from other tools and issue trackers",
+		Subcommands: []*cli.Command{
+			importTaskwarriorCmd(),
+			importIssuesCmd(),
+		},
+	}
+}
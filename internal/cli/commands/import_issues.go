@@ -0,0 +1,304 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/issues"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// issueImport is a tracker issue and what the import does with it
+type issueImport struct {
+	issues.Issue
+	Existing *models.Task // task imported from this issue earlier
+	Changes  []string     // fields of Existing that differ from the issue
+	Comments []string     // annotations still to add
+	Err      error
+
+	// The title, description and comment annotations as stored, after the secret scan
+	StoredTitle       string
+	StoredDescription string
+	StoredComments    []string
+}
+
+// importIssuesCmd imports issue tracker exports as tasks.
+func importIssuesCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "issues",
+		Usage:     "Import GitHub, GitLab or Jira issue exports as tasks",
+		ArgsUsage: "<file|->",
+		Description: "Reads exported files, no tracker access needed:\n" +
+			"  github  gh issue list --state all --json number,title,body,labels,state,url,comments,createdAt\n" +
+			"  gitlab  the issues CSV export (Issues > Export as CSV)\n" +
+			"  jira    the CSV export of a filter (all fields)\n" +
+			"Labels become tags, the body the description and comments annotations. The issue\n" +
+			"URL (or Jira key) is kept on a Source: line of the description, so importing a newer\n" +
+			"export updates the tasks and adds new comments instead of creating duplicates.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "source", Aliases: []string{"s"}, Usage: "Export format: " + strings.Join(issues.Sources, ", "), Required: true},
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project for new tasks (name or ID). Defaults to the active project."},
+			&cli.StringSliceFlag{Name: "tags", Aliases: []string{"t"}, Usage: "Extra tags added to every new task"},
+			&cli.BoolFlag{Name: "no-comments", Usage: "Do not import comments"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of issues imported in parallel", Value: 4},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Show what would be created or updated without changing anything"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("export file is required (use - for stdin)")
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+
+			var r io.Reader = os.Stdin
+			if file := c.Args().First(); file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			parsed, err := issues.Parse(c.String("source"), r)
+			if err != nil {
+				return fmt.Errorf("could not read %s export: %w", c.String("source"), err)
+			}
+
+			var items []*issueImport
+			seen := map[string]bool{}
+			invalid := 0
+			for i, issue := range parsed {
+				if issue.Ref == "" || issue.Title == "" {
+					fmt.Printf("⚠️  Skipping issue %d: no URL/key or title\n", i+1)
+					invalid++
+					continue
+				}
+				if seen[issue.Ref] {
+					continue
+				}
+				seen[issue.Ref] = true
+				if c.Bool("no-comments") {
+					issue.Comments = nil
+				}
+				items = append(items, &issueImport{Issue: issue})
+			}
+			if len(items) == 0 {
+				fmt.Println("No issues to import.")
+				return nil
+			}
+
+			client := api.NewClient()
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				projectID = cfg.ActiveProjectID
+			}
+			if projectID == "" {
+				return fmt.Errorf("no active project set. Use 'ramorie project use <id>' or specify --project")
+			}
+
+			// Tasks imported earlier, wherever they were moved since
+			tasks, err := client.ListTasks("", "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			for _, it := range items {
+				it.Err = guardIssue(scanner, it)
+			}
+
+			bySource := map[string]models.Task{}
+			for _, t := range tasks {
+				if ref := issues.SourceRef(t.Description); ref != "" {
+					bySource[ref] = t
+				}
+			}
+			forEachLimited(concurrency, len(items), func(i int) {
+				it := items[i]
+				if it.Err != nil {
+					return
+				}
+				t, ok := bySource[it.Ref]
+				if !ok {
+					it.Comments = it.StoredComments
+					return
+				}
+				it.Existing = &t
+				if t.Title != it.StoredTitle {
+					it.Changes = append(it.Changes, "title")
+				}
+				if strings.TrimSpace(t.Description) != strings.TrimSpace(it.StoredDescription) {
+					it.Changes = append(it.Changes, "description")
+				}
+				// An open issue says nothing about work started here; only a
+				// closed/reopened issue or a specific tracker status moves the task
+				if t.Status != it.Status && (it.Status != "TODO" || t.Status == "COMPLETED") {
+					it.Changes = append(it.Changes, "status")
+				}
+				if len(it.StoredComments) == 0 {
+					return
+				}
+				notes, err := client.ListAnnotations(t.ID.String())
+				if err != nil {
+					it.Err = err
+					return
+				}
+				have := map[string]bool{}
+				for _, n := range notes {
+					have[strings.TrimSpace(n.Content)] = true
+				}
+				for _, text := range it.StoredComments {
+					if !have[strings.TrimSpace(text)] {
+						it.Comments = append(it.Comments, text)
+					}
+				}
+			})
+
+			if c.Bool("dry-run") {
+				printIssuePlan(items)
+				return nil
+			}
+
+			forEachLimited(concurrency, len(items), func(i int) {
+				it := items[i]
+				if it.Err != nil {
+					return
+				}
+				var taskID string
+				if it.Existing == nil {
+					tags := append([]string{}, it.Labels...)
+					for _, tag := range c.StringSlice("tags") {
+						if !containsFold(tags, tag) {
+							tags = append(tags, tag)
+						}
+					}
+					task, err := client.CreateTask(projectID, it.StoredTitle, it.StoredDescription, it.Priority, tags...)
+					if err != nil {
+						it.Err = err
+						return
+					}
+					taskID = task.ID.String()
+					if it.Status != "TODO" {
+						it.Changes = []string{"status"}
+					}
+				} else {
+					taskID = it.Existing.ID.String()
+				}
+				if len(it.Changes) > 0 {
+					update := map[string]interface{}{}
+					for _, field := range it.Changes {
+						switch field {
+						case "title":
+							update["title"] = it.StoredTitle
+						case "description":
+							update["description"] = it.StoredDescription
+						case "status":
+							update["status"] = it.Status
+						}
+					}
+					if _, err := client.UpdateTask(taskID, update); err != nil {
+						it.Err = err
+						return
+					}
+				}
+				for _, text := range it.Comments {
+					if _, err := client.CreateAnnotation(taskID, text); err != nil {
+						it.Err = err
+						return
+					}
+				}
+			})
+
+			created, updated, unchanged, comments, failed := 0, 0, 0, 0, 0
+			for _, it := range items {
+				switch {
+				case it.Err != nil:
+					failed++
+					fmt.Printf("❌ %s: %s\n", it.Ref, apierrors.ParseAPIError(it.Err))
+				case it.Existing == nil:
+					created++
+					comments += len(it.Comments)
+				case len(it.Changes) > 0 || len(it.Comments) > 0:
+					updated++
+					comments += len(it.Comments)
+				default:
+					unchanged++
+				}
+			}
+			fmt.Printf("📥 Issues: %d created, %d updated, %d unchanged, %d comments added.\n", created, updated, unchanged, comments)
+			if invalid > 0 {
+				fmt.Printf("⚠️  %d issues skipped without a URL/key or title\n", invalid)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d issues failed to import", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// guardIssue applies the secret scan policy to everything stored from an issue
+func guardIssue(scanner *secrets.Scanner, it *issueImport) error {
+	var err error
+	label := "issue " + it.Ref
+	if it.StoredTitle, err = guardLabelled(scanner, label+" title", it.Title); err != nil {
+		return err
+	}
+	if it.StoredDescription, err = guardLabelled(scanner, label, it.Description()); err != nil {
+		return err
+	}
+	it.StoredComments = make([]string, len(it.Issue.Comments))
+	for i, comment := range it.Issue.Comments {
+		if it.StoredComments[i], err = guardLabelled(scanner, label+" comment", comment.Annotation()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printIssuePlan(items []*issueImport) {
+	create, update := 0, 0
+	for _, it := range items {
+		title := truncateString(it.StoredTitle, 60)
+		switch {
+		case it.Err != nil:
+			fmt.Printf("  ! %s: %s\n", it.Ref, apierrors.ParseAPIError(it.Err))
+		case it.Existing == nil:
+			create++
+			line := fmt.Sprintf("  + %s [%s, %s] %s", title, it.Priority, it.Status, it.Ref)
+			if len(it.Labels) > 0 {
+				line += " #" + strings.Join(it.Labels, " #")
+			}
+			if len(it.Comments) > 0 {
+				line += fmt.Sprintf(" (%d comments)", len(it.Comments))
+			}
+			fmt.Println(line)
+		case len(it.Changes) > 0 || len(it.Comments) > 0:
+			update++
+			changes := append([]string{}, it.Changes...)
+			if len(it.Comments) > 0 {
+				changes = append(changes, fmt.Sprintf("%d new comments", len(it.Comments)))
+			}
+			fmt.Printf("  ~ %s %s (%s: %s)\n", it.Existing.ID.String()[:8], title, it.Ref, strings.Join(changes, ", "))
+		default:
+			fmt.Printf("  = %s %s (unchanged)\n", it.Existing.ID.String()[:8], title)
+		}
+	}
+	fmt.Printf("\nDry run: %d tasks would be created, %d updated, %d unchanged.\n", create, update, len(items)-create-update)
+}
@@ -15,6 +15,7 @@ func NewLinkCommand() *cli.Command {
 		Usage: "Link memories and tasks",
 		Subcommands: []*cli.Command{
 			linkCreateCmd(),
+			linkMemoryCmd(),
 		},
 	}
 }
@@ -50,6 +50,25 @@ func NewMcpCommand() *cli.Command {
 					return nil
 				},
 			},
+			{
+				Name:  "docs",
+				Usage: "Generate markdown reference docs for MCP tools",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Write to file instead of stdout"},
+				},
+				Action: func(c *cli.Context) error {
+					docs := mcp.ToolsMarkdown()
+					if out := c.String("output"); out != "" {
+						if err := os.WriteFile(out, []byte(docs), 0644); err != nil {
+							return fmt.Errorf("could not write docs: %w", err)
+						}
+						fmt.Printf("📄 MCP tool docs written to %s\n", out)
+						return nil
+					}
+					fmt.Print(docs)
+					return nil
+				},
+			},
 		},
 	}
 }
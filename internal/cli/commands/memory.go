@@ -3,13 +3,17 @@ package commands
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/terzigolu/josepshbrain-go/internal/api"
 	"github.com/terzigolu/josepshbrain-go/internal/config"
 	"github.com/terzigolu/josepshbrain-go/internal/constants"
 	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/freshness"
+	"github.com/terzigolu/josepshbrain-go/internal/memtype"
 	"github.com/terzigolu/josepshbrain-go/internal/models"
 	"github.com/urfave/cli/v2"
 )
@@ -25,6 +29,14 @@ func NewMemoryCommand() *cli.Command {
 			memoriesCmd(),
 			getCmd(),
 			recallCmd(),
+			memoryEditCmd(),
+			memoryHistoryCmd(),
+			memoryDiffCmd(),
+			memoryRestoreCmd(),
+			memoryImportCmd(),
+			memoryDedupeCmd(),
+			memoryScheduleCmd(),
+			memoryReviewCmd(),
 			forgetCmd(),
 		},
 	}
@@ -40,45 +52,95 @@ func rememberCmd() *cli.Command {
 	return &cli.Command{
 		Name:                   "remember",
 		Usage:                  "Create a new memory",
-		ArgsUsage:              "[content]",
+		ArgsUsage:              "[content | -]",
+		Description:            "Content comes from the arguments, stdin (-), --file or --edit.\nA leading YAML front matter block may set title, tags, project and task.",
 		UseShortOptionHandling: true,
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "project",
 				Aliases: []string{"p"},
-				Usage:   "Project ID. Defaults to the active project.",
+				Usage:   "Project name or ID. Defaults to the active project.",
 			},
 			&cli.StringSliceFlag{
 				Name:    "tags",
 				Aliases: []string{"t"},
 				Usage:   "Tags for the memory (can be used multiple times or comma-separated)",
 			},
-		},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Read the memory from a markdown file",
+			},
+			&cli.BoolFlag{
+				Name:    "edit",
+				Aliases: []string{"e"},
+				Usage:   "Write the memory in $EDITOR",
+			},
+			&cli.BoolFlag{
+				Name:  "chunk",
+				Usage: "Split content over the size limit into several memories without asking",
+			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Memory type: " + strings.Join(memtype.Names(), ", "),
+			},
+		}, append(lifecycleFlags(), memoryFieldFlags()...)...),
 		Action: func(c *cli.Context) error {
-			if c.NArg() == 0 {
-				return fmt.Errorf("memory content is required")
+			raw, err := readMemoryInput(c)
+			if err != nil {
+				return err
 			}
-			content := c.Args().First()
-			projectID := c.String("project")
-			tags := c.StringSlice("tags")
+			fm, content, err := parseFrontMatter(raw)
+			if err != nil {
+				return err
+			}
+			content = strings.TrimSpace(content)
 
-			// Check content length limit before sending
-			if !constants.IsWithinMemoryLimit(content) {
-				chars, tokens, usage := constants.GetContentStats(content)
-				fmt.Printf("❌ Content exceeds maximum limit!\n")
-				fmt.Printf("   Your content: %d chars (~%d tokens)\n", chars, tokens)
-				fmt.Printf("   Maximum: %d chars (~%d tokens)\n", constants.MaxMemoryChars, constants.MaxMemoryChars/constants.CharsPerToken)
-				fmt.Printf("   Usage: %.1f%%\n", usage)
-				return fmt.Errorf("content too large")
+			memoryType, fields, content, err := memoryTypeFields(c, fm, content)
+			if err != nil {
+				return err
+			}
+			lifecycle, err := lifecycleUpdates(c, fm)
+			if err != nil {
+				return err
+			}
+			if content == "" {
+				return fmt.Errorf("memory content is required")
+			}
+			if fm.Title != "" && memoryType == "" {
+				content = "# " + fm.Title + "\n\n" + content
+			}
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			if content, err = guardLabelled(scanner, "memory", content); err != nil {
+				return err
+			}
+			for name, value := range fields {
+				if fields[name], err = guardLabelled(scanner, "memory "+name, value); err != nil {
+					return err
+				}
 			}
 
-			// Show warning if approaching limit (80%+)
-			chars, tokens, usage := constants.GetContentStats(content)
-			if usage >= constants.WarningThresholdPercent {
-				fmt.Printf("⚠️  Warning: Content is %.1f%% of maximum limit (%d chars)\n", usage, chars)
+			tags := c.StringSlice("tags")
+			for _, tag := range fm.Tags {
+				if !containsFold(tags, tag) {
+					tags = append(tags, tag)
+				}
 			}
 
+			client := api.NewClient()
+
+			projectID := c.String("project")
 			if projectID == "" {
+				projectID = fm.Project
+			}
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			} else {
 				cfg, err := config.LoadConfig()
 				if err != nil || cfg.ActiveProjectID == "" {
 					return fmt.Errorf("no active project set. Use 'ramorie project use <id>' or specify --project")
@@ -86,27 +148,174 @@ func rememberCmd() *cli.Command {
 				projectID = cfg.ActiveProjectID
 			}
 
-			client := api.NewClient()
-			memory, err := client.CreateMemory(projectID, content, tags...)
-			if err != nil {
-				fmt.Println(apierrors.ParseAPIError(err))
-				return err
+			linkedTaskID := ""
+			if id := fm.taskID(); id != "" {
+				task, err := client.GetTask(id)
+				if err != nil {
+					return fmt.Errorf("linked task '%s' not found: %w", id, err)
+				}
+				linkedTaskID = task.ID.String()
+			}
+
+			parts := []string{content}
+
+			// Check content length limit before sending
+			if !constants.IsWithinMemoryLimit(content) && memoryType != "" {
+				chars, _, _ := constants.GetContentStats(content)
+				return fmt.Errorf("content too large: %d chars (maximum %d); typed memories cannot be split", chars, constants.MaxMemoryChars)
+			} else if !constants.IsWithinMemoryLimit(content) {
+				chars, tokens, usage := constants.GetContentStats(content)
+				fmt.Printf("❌ Content exceeds maximum limit!\n")
+				fmt.Printf("   Your content: %d chars (~%d tokens)\n", chars, tokens)
+				fmt.Printf("   Maximum: %d chars (~%d tokens)\n", constants.MaxMemoryChars, constants.MaxMemoryChars/constants.CharsPerToken)
+				fmt.Printf("   Usage: %.1f%%\n", usage)
+
+				// Leave room for the "(part i/n)" header
+				parts = chunkContent(content, constants.MaxMemoryChars-64)
+				if !c.Bool("chunk") && !confirm(fmt.Sprintf("Split it into %d memories?", len(parts))) {
+					return fmt.Errorf("content too large (use --chunk to split it into several memories)")
+				}
+				for i := range parts {
+					parts[i] = fmt.Sprintf("(part %d/%d)\n\n%s", i+1, len(parts), parts[i])
+				}
+			} else {
+				// Show warning if approaching limit (80%+)
+				chars, _, usage := constants.GetContentStats(content)
+				if usage >= constants.WarningThresholdPercent {
+					fmt.Printf("⚠️  Warning: Content is %.1f%% of maximum limit (%d chars)\n", usage, chars)
+				}
+			}
+
+			options := map[string]interface{}{}
+			for key, value := range lifecycle {
+				options[key] = value
 			}
-			fmt.Printf("🧠 Memory stored successfully! (ID: %s)\n", memory.ID.String()[:8])
-			fmt.Printf("   Size: %d chars (~%d tokens)\n", chars, tokens)
 			if len(tags) > 0 {
-				fmt.Printf("   Tags: %s\n", strings.Join(tags, ", "))
+				options["tags"] = tags
+			}
+			if memoryType != "" {
+				options["type"] = memoryType
+				options["fields"] = fields
 			}
+			for _, part := range parts {
+				memory, err := client.CreateMemoryWithOptions(projectID, part, options)
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				// A backend that ignores the lifecycle fields on create gets them in
+				// an update; the memory is stored either way, so a failure only warns
+				if missing := freshness.Unapplied(*memory, lifecycle); len(missing) > 0 {
+					if updated, err := client.UpdateMemory(memory.ID.String(), missing); err != nil {
+						fmt.Printf("⚠️  Memory %s was stored, but its expiry and review dates could not be set: %s\n", memory.ID.String()[:8], apierrors.ParseAPIError(err))
+					} else {
+						memory = updated
+					}
+				}
+				chars, tokens, _ := constants.GetContentStats(part)
+				fmt.Printf("🧠 Memory stored successfully! (ID: %s)\n", memory.ID.String()[:8])
+				if memoryType != "" {
+					fmt.Printf("   Type: %s\n", memoryType)
+				}
+				fmt.Printf("   Size: %d chars (~%d tokens)\n", chars, tokens)
+				if len(tags) > 0 {
+					fmt.Printf("   Tags: %s\n", strings.Join(tags, ", "))
+				}
+				printLifecycle(*memory)
 
-			// Show if memory was auto-linked to active task
-			if memory.LinkedTaskID != nil {
-				fmt.Printf("🔗 Auto-linked to active task: %s\n", memory.LinkedTaskID.String()[:8])
+				if linkedTaskID != "" {
+					if _, err := client.CreateMemoryTaskLink(linkedTaskID, memory.ID.String(), "related"); err != nil {
+						fmt.Println(apierrors.ParseAPIError(err))
+						return err
+					}
+					fmt.Printf("🔗 Linked to task: %s\n", linkedTaskID[:8])
+				} else if memory.LinkedTaskID != nil {
+					// Show if memory was auto-linked to active task
+					fmt.Printf("🔗 Auto-linked to active task: %s\n", memory.LinkedTaskID.String()[:8])
+				}
 			}
 			return nil
 		},
 	}
 }
 
+// memoryFieldFlags returns a flag for every structured memory field, e.g. --symptom
+func memoryFieldFlags() []cli.Flag {
+	var flags []cli.Flag
+	for _, name := range memtype.FieldNames() {
+		var types []string
+		for _, t := range memtype.Types {
+			for _, f := range t.Fields {
+				if f.Name == name {
+					types = append(types, t.Name)
+				}
+			}
+		}
+		flags = append(flags, &cli.StringFlag{
+			Name:     name,
+			Usage:    "Field of " + strings.Join(types, "/") + " memories",
+			Category: "Memory type fields",
+		})
+	}
+	return flags
+}
+
+// memoryTypeFields combines --type and field flags with the front matter
+// and validates them. A snippet without --code takes the body as its code;
+// a typed memory without body gets its fields as content.
+func memoryTypeFields(c *cli.Context, fm memoryFrontMatter, content string) (string, map[string]string, string, error) {
+	name := c.String("type")
+	if name == "" {
+		name = fm.Type
+	}
+	fields := map[string]string{}
+	for k, v := range fm.Fields {
+		fields[k] = v
+	}
+	for _, field := range memtype.FieldNames() {
+		if c.IsSet(field) {
+			fields[field] = c.String(field)
+		}
+	}
+	if name == "" {
+		if len(fields) > 0 {
+			return "", nil, "", memtype.Validate("", fields)
+		}
+		return "", nil, content, nil
+	}
+
+	t, ok := memtype.Lookup(name)
+	if !ok {
+		return "", nil, "", memtype.Validate(name, fields)
+	}
+	switch t.Name {
+	case "snippet":
+		if fields["code"] == "" && content != "" {
+			fields["code"], content = content, ""
+		}
+	case "reference":
+		if fields["title"] == "" && fm.Title != "" {
+			fields["title"] = fm.Title
+		}
+	}
+	if err := memtype.Validate(t.Name, fields); err != nil {
+		return "", nil, "", err
+	}
+	if content == "" {
+		content = memtype.Content(t.Name, fields)
+	}
+	return t.Name, fields, content, nil
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
 // memoriesCmd lists all memory items.
 func memoriesCmd() *cli.Command {
 	return &cli.Command{
@@ -139,6 +348,10 @@ func memoriesCmd() *cli.Command {
 				Aliases: []string{"t"},
 				Usage:   "Filter by tag",
 			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Filter by memory type (" + strings.Join(memtype.Names(), ", ") + ")",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			projectID := c.String("project")
@@ -160,7 +373,7 @@ func memoriesCmd() *cli.Command {
 			}
 
 			client := api.NewClient()
-			memories, err := client.ListMemories(projectID, "") // No search query
+			memories, err := client.ListMemoriesQuery(projectID, "", c.String("type")) // No search query
 			if err != nil {
 				fmt.Println(apierrors.ParseAPIError(err))
 				return err
@@ -214,7 +427,7 @@ func memoriesCmd() *cli.Command {
 						tagsStr = tagsStr[:12] + "..."
 					}
 				}
-				fmt.Fprintf(w, "%s\t%s\t%s\n", m.ID.String()[:8], tagsStr, truncateString(m.Content, 55))
+				fmt.Fprintf(w, "%s\t%s\t%s\n", m.ID.String()[:8], tagsStr, truncateString(memoryPreview(m), 55))
 			}
 			w.Flush()
 			return nil
@@ -241,6 +454,18 @@ func recallCmd() *cli.Command {
 				Usage:   "Limit number of results",
 				Value:   20,
 			},
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Filter by memory type (" + strings.Join(memtype.Names(), ", ") + ")",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Include expired and archived memories",
+			},
+			&cli.BoolFlag{
+				Name:  "expand",
+				Usage: "Also show memories one relation away from the results (see 'link memory')",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if c.NArg() == 0 {
@@ -259,30 +484,58 @@ func recallCmd() *cli.Command {
 			}
 
 			client := api.NewClient()
-			memories, err := client.ListMemories(projectID, query)
+			memories, err := client.ListMemoriesQuery(projectID, query, c.String("type"))
 			if err != nil {
 				fmt.Println(apierrors.ParseAPIError(err))
 				return err
 			}
 
+			memories = rankByFreshness(memories, time.Now(), c.Bool("all"))
+
 			// Apply limit
 			if limit > 0 && len(memories) > limit {
 				memories = memories[:limit]
 			}
 
-			if len(memories) == 0 {
+			// Text attachments (logs, traces) are searched too, by term as MCP recall does
+			terms, all := api.SearchTerms(query)
+			attachments, _ := client.SearchAttachments(projectID, terms, all)
+
+			if len(memories) == 0 && len(attachments) == 0 {
 				fmt.Printf("No memories found matching '%s'.\n", query)
 				return nil
 			}
+			if len(memories) == 0 {
+				fmt.Printf("No memories match '%s', but %d attachment(s) do:\n", query, len(attachments))
+				printAttachments(attachments)
+				return nil
+			}
 
 			fmt.Printf("Found %d memories matching your query:\n", len(memories))
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 			fmt.Fprintln(w, "ID\tCONTENT")
 			fmt.Fprintln(w, "--\t-------")
 			for _, m := range memories {
-				fmt.Fprintf(w, "%s\t%s\n", m.ID.String()[:8], truncateString(m.Content, 70))
+				fmt.Fprintf(w, "%s\t%s\n", m.ID.String()[:8], truncateString(memoryPreview(m), 70))
 			}
 			w.Flush()
+
+			if c.Bool("expand") {
+				related := expandRelated(client, memories, time.Now(), c.Bool("all"))
+				if len(related) > 0 {
+					fmt.Printf("\nRelated (%d):\n", len(related))
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					for _, r := range related {
+						// Reads as "<result> superseded by <related>"
+						fmt.Fprintf(w, "%s %s %s\t%s\n", r.via.String()[:8], r.relation, r.memory.ID.String()[:8], truncateString(memoryPreview(r.memory), 60))
+					}
+					w.Flush()
+				}
+			}
+			if len(attachments) > 0 {
+				fmt.Printf("\nAttachments (%d):\n", len(attachments))
+				printAttachments(attachments)
+			}
 			return nil
 		},
 	}
@@ -307,7 +560,13 @@ func getCmd() *cli.Command {
 				return err
 			}
 
-			fmt.Printf("Memory %s:\n%s\n", memory.ID.String()[:8], memory.Content)
+			if memory.Type != "" {
+				fmt.Printf("Memory %s (%s):\n%s\n", memory.ID.String()[:8], memory.Type, memtype.Markdown(memory.Type, memory.Fields, memory.Content))
+			} else {
+				fmt.Printf("Memory %s:\n%s\n", memory.ID.String()[:8], memory.Content)
+			}
+			printLifecycle(*memory)
+			printNeighbours(client, *memory)
 			return nil
 		},
 	}
@@ -338,6 +597,40 @@ func forgetCmd() *cli.Command {
 	}
 }
 
+// memoryPreview is the one-line content shown in memory tables, prefixed with the type
+func memoryPreview(m models.Memory) string {
+	content := strings.Join(strings.Fields(m.Content), " ")
+	if m.Type != "" {
+		return "[" + m.Type + "] " + content
+	}
+	return content
+}
+
+// rankByFreshness drops expired and archived memories (unless all is set)
+// and reorders the backend's relevance ranking so that older memories sink,
+// weighting each position by freshness.Weight
+func rankByFreshness(memories []models.Memory, now time.Time, all bool) []models.Memory {
+	type ranked struct {
+		memory models.Memory
+		score  float64
+	}
+	var list []ranked
+	for i, m := range memories {
+		if !all && freshness.Hidden(m, now) {
+			continue
+		}
+		relevance := 1 - float64(i)/float64(len(memories))
+		list = append(list, ranked{m, relevance * freshness.Weight(m, now)})
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].score > list[j].score })
+
+	out := make([]models.Memory, len(list))
+	for i, r := range list {
+		out[i] = r.memory
+	}
+	return out
+}
+
 // getTagsAsStrings converts interface{} tags to []string
 func getTagsAsStrings(tags interface{}) []string {
 	if tags == nil {
@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/dedupe"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// memoryDedupeCmd finds near-duplicate memories and merges them.
+func memoryDedupeCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "dedupe",
+		Usage: "Find duplicate and near-duplicate memories and merge them",
+		Description: "Memories are compared locally. For each cluster you pick the memory to keep;\n" +
+			"it gets the tags and task links of the others, which are then deleted.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project name or ID. Defaults to the active project.",
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "Minimum similarity (0.05-1) for two memories to count as duplicates",
+				Value: 0.5,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only list the duplicate clusters",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Merge every cluster into its suggested memory without asking",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			threshold := c.Float64("threshold")
+			if threshold < dedupe.MinThreshold || threshold > 1 {
+				return fmt.Errorf("--threshold must be between %g and 1", dedupe.MinThreshold)
+			}
+
+			client := api.NewClient()
+
+			projectID := c.String("project")
+			if projectID != "" {
+				var err error
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			} else {
+				cfg, err := config.LoadConfig()
+				if err != nil || cfg.ActiveProjectID == "" {
+					return fmt.Errorf("no active project set. Use 'ramorie project use <id>' or specify --project")
+				}
+				projectID = cfg.ActiveProjectID
+			}
+
+			memories, err := client.ListMemories(projectID, "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			texts := make([]string, len(memories))
+			for i, m := range memories {
+				texts[i] = m.Content
+			}
+			clusters := dedupe.Clusters(texts, threshold)
+			if len(clusters) == 0 {
+				fmt.Println("✨ No duplicate memories found.")
+				return nil
+			}
+
+			interactive := !c.Bool("dry-run") && !c.Bool("yes") && term.IsTerminal(int(os.Stdin.Fd()))
+			listOnly := c.Bool("dry-run") || (!c.Bool("yes") && !interactive)
+			reader := bufio.NewReader(os.Stdin)
+
+			merged, deleted := 0, 0
+			for k, cluster := range clusters {
+				group := make([]models.Memory, len(cluster.Members))
+				for i, idx := range cluster.Members {
+					group[i] = memories[idx]
+				}
+				sortMergeCandidates(group)
+
+				fmt.Printf("\n🔁 Cluster %d/%d — %d memories, similarity ≥ %.0f%%\n", k+1, len(clusters), len(group), cluster.Similarity*100)
+				for i, m := range group {
+					line := fmt.Sprintf("  [%d] %s  %s  %s", i+1, m.ID.String()[:8], m.CreatedAt.Local().Format("2006-01-02"), truncateString(strings.Join(strings.Fields(m.Content), " "), 70))
+					if tags := getTagsAsStrings(m.Tags); len(tags) > 0 {
+						line += "  [" + strings.Join(tags, ", ") + "]"
+					}
+					fmt.Println(line)
+				}
+				if listOnly {
+					continue
+				}
+
+				keep := 0
+				if interactive {
+					fmt.Printf("Keep which? [1-%d], s=skip, q=quit (default 1): ", len(group))
+					answer, _ := reader.ReadString('\n')
+					answer = strings.TrimSpace(strings.ToLower(answer))
+					switch {
+					case answer == "q":
+						fmt.Printf("\n🧹 Merged %d clusters, deleted %d memories.\n", merged, deleted)
+						return nil
+					case answer == "s":
+						continue
+					case answer != "":
+						n, err := strconv.Atoi(answer)
+						if err != nil || n < 1 || n > len(group) {
+							fmt.Println("Invalid choice, skipping cluster.")
+							continue
+						}
+						keep = n - 1
+					}
+				}
+
+				keeper := group[keep]
+				losers := append(append([]models.Memory{}, group[:keep]...), group[keep+1:]...)
+				if err := mergeMemories(client, keeper, losers); err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				merged++
+				deleted += len(losers)
+				fmt.Printf("✅ Kept %s, merged %d duplicates into it\n", keeper.ID.String()[:8], len(losers))
+			}
+
+			if listOnly {
+				fmt.Printf("\nFound %d clusters. Run in a terminal to merge them interactively, or pass --yes.\n", len(clusters))
+				return nil
+			}
+			fmt.Printf("\n🧹 Merged %d clusters, deleted %d memories.\n", merged, deleted)
+			return nil
+		},
+	}
+}
+
+// sortMergeCandidates puts the suggested memory to keep first: the most
+// detailed one, and the oldest among equally long ones.
+func sortMergeCandidates(group []models.Memory) {
+	sort.SliceStable(group, func(i, j int) bool {
+		li, lj := len(strings.TrimSpace(group[i].Content)), len(strings.TrimSpace(group[j].Content))
+		if li != lj {
+			return li > lj
+		}
+		return group[i].CreatedAt.Before(group[j].CreatedAt)
+	})
+}
+
+// mergeMemories gives keeper the tags and task links of losers, then deletes them
+func mergeMemories(client *api.Client, keeper models.Memory, losers []models.Memory) error {
+	tags := getTagsAsStrings(keeper.Tags)
+	added := false
+	for _, m := range losers {
+		for _, tag := range getTagsAsStrings(m.Tags) {
+			if !containsFold(tags, tag) {
+				tags = append(tags, tag)
+				added = true
+			}
+		}
+	}
+	if added {
+		if _, err := client.UpdateMemory(keeper.ID.String(), map[string]interface{}{"tags": tags}); err != nil {
+			return err
+		}
+	}
+
+	keeperTasks, err := client.ListMemoryTasks(keeper.ID.String())
+	if err != nil {
+		return err
+	}
+	linked := map[string]bool{}
+	for _, t := range keeperTasks {
+		linked[t.ID.String()] = true
+	}
+
+	for _, m := range losers {
+		tasks, err := client.ListMemoryTasks(m.ID.String())
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if linked[t.ID.String()] {
+				continue
+			}
+			if _, err := client.CreateMemoryTaskLink(t.ID.String(), keeper.ID.String(), "related"); err != nil {
+				return err
+			}
+			linked[t.ID.String()] = true
+		}
+		if err := client.DeleteMemory(m.ID.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/freshness"
+	"github.com/terzigolu/josepshbrain-go/internal/graph"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+)
+
+// linkMemoryCmd relates two memories with a typed relation.
+func linkMemoryCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "memory",
+		Usage:     "Relate two memories, e.g. a newer note that supersedes an older one",
+		ArgsUsage: "[memory-id] [target-memory-id]",
+		Description: "Reads as \"<memory> <type> <target>\": 'ramorie link memory --type supersedes new old'.\n" +
+			"Types: " + strings.Join(graph.Relations, ", ") + ".",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "type",
+				Aliases: []string{"t"},
+				Usage:   "Relation type (" + strings.Join(graph.Relations, ", ") + ")",
+				Value:   graph.SeeAlso,
+			},
+			&cli.BoolFlag{
+				Name:  "remove",
+				Usage: "Remove the relation instead of creating it",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 2 {
+				return fmt.Errorf("two memory IDs are required")
+			}
+			relation, err := graph.ParseRelation(c.String("type"))
+			if err != nil {
+				return err
+			}
+
+			client := api.NewClient()
+			from, err := client.GetMemory(c.Args().Get(0))
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			to, err := client.GetMemory(c.Args().Get(1))
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if from.ID == to.ID {
+				return fmt.Errorf("a memory cannot be linked to itself")
+			}
+
+			if c.Bool("remove") {
+				links, err := client.ListMemoryLinks(from.ID.String(), "")
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				removed := 0
+				for _, l := range links {
+					if l.MemoryID == from.ID && l.TargetMemoryID == to.ID && (!c.IsSet("type") || l.RelationType == relation) {
+						if err := client.DeleteMemoryLink(l.ID.String()); err != nil {
+							fmt.Println(apierrors.ParseAPIError(err))
+							return err
+						}
+						removed++
+					}
+				}
+				if removed == 0 {
+					return fmt.Errorf("memory %s has no such relation to %s", from.ID.String()[:8], to.ID.String()[:8])
+				}
+				fmt.Printf("✂️  Removed %d relation(s) from %s to %s.\n", removed, from.ID.String()[:8], to.ID.String()[:8])
+				return nil
+			}
+
+			if _, err := client.CreateMemoryLink(from.ID.String(), to.ID.String(), relation); err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			fmt.Printf("🔗 %s %s %s\n", from.ID.String()[:8], relation, to.ID.String()[:8])
+			return nil
+		},
+	}
+}
+
+// printNeighbours lists the memories related to m and the tasks linked to it
+func printNeighbours(client *api.Client, m models.Memory) {
+	links, err := client.ListMemoryLinks(m.ID.String(), "")
+	if err == nil {
+		neighbours := graph.Neighbours(links, m.ID)
+		if len(neighbours) > 0 {
+			fmt.Println("\nRelated memories:")
+		}
+		for _, n := range neighbours {
+			preview := ""
+			if other, err := client.GetMemory(n.ID.String()); err == nil {
+				preview = truncateString(memoryPreview(*other), 60)
+			}
+			arrow := "→"
+			if !n.Outgoing {
+				arrow = "←"
+			}
+			fmt.Printf("  %s %s %s  %s\n", arrow, n.Relation, n.ID.String()[:8], preview)
+		}
+	}
+
+	if tasks, err := client.ListMemoryTasks(m.ID.String()); err == nil && len(tasks) > 0 {
+		fmt.Println("\nLinked tasks:")
+		for _, t := range tasks {
+			fmt.Printf("  %s  %s [%s]\n", t.ID.String()[:8], truncateString(t.Title, 60), t.Status)
+		}
+	}
+}
+
+// relatedMemory is a recall result's neighbour that did not match the query itself
+type relatedMemory struct {
+	memory   models.Memory
+	relation string
+	via      uuid.UUID
+}
+
+// expandRelated follows the relations of the recalled memories one hop and
+// returns the neighbours that are not results already, skipping hidden ones
+// unless all is set
+func expandRelated(client *api.Client, memories []models.Memory, now time.Time, all bool) []relatedMemory {
+	linksOf := make([][]models.MemoryLink, len(memories))
+	forEachLimited(8, len(memories), func(i int) {
+		linksOf[i], _ = client.ListMemoryLinks(memories[i].ID.String(), "")
+	})
+
+	seen := map[uuid.UUID]bool{}
+	for _, m := range memories {
+		seen[m.ID] = true
+	}
+	var related []relatedMemory
+	for i, m := range memories {
+		for _, n := range graph.Neighbours(linksOf[i], m.ID) {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			related = append(related, relatedMemory{memory: models.Memory{ID: n.ID}, relation: n.Relation, via: m.ID})
+		}
+	}
+
+	forEachLimited(8, len(related), func(i int) {
+		if m, err := client.GetMemory(related[i].memory.ID.String()); err == nil {
+			related[i].memory = *m
+		}
+	})
+	out := related[:0]
+	for _, r := range related {
+		if r.memory.Content == "" || (!all && freshness.Hidden(r.memory, now)) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// NewGraphCommand exports memories, tasks and decisions with their relations
+func NewGraphCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "graph",
+		Usage: "Export the memory, task and decision graph as DOT, Mermaid or GraphML",
+		Description: "Nodes are memories, tasks and decisions (ADRs), grouped by project. Edges are memory\n" +
+			"relations ('ramorie link memory'), memory-task links, and mentions of an ADR number\n" +
+			"in memory or task text. Render DOT with e.g. 'ramorie graph | dot -Tsvg > graph.svg'.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project name or ID (default: all projects)",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format (" + strings.Join(graph.Formats, ", ") + ")",
+				Value:   "dot",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write to a file instead of stdout",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Include expired and archived memories",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			client := api.NewClient()
+
+			var projects []models.Project
+			if arg := c.String("project"); arg != "" {
+				id, err := resolveProjectArg(client, arg)
+				if err != nil {
+					return err
+				}
+				project, err := client.GetProject(id)
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				projects = []models.Project{*project}
+			} else {
+				var err error
+				if projects, err = client.ListProjects(); err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+			}
+
+			g := &graph.Graph{}
+			// mentions holds node text that may cite an ADR number
+			mentions := map[string]string{}
+			var links []models.MemoryLink
+			var taskLinks []graph.Edge
+			now := time.Now()
+
+			for _, p := range projects {
+				memories, err := client.ListMemories(p.ID.String(), "")
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				for _, m := range memories {
+					if !c.Bool("all") && freshness.Hidden(m, now) {
+						continue
+					}
+					g.AddNode(graph.Node{ID: m.ID.String(), Kind: graph.KindMemory, Label: truncateString(models.MemoryTitle(memoryPreview(m)), 48), Group: p.Name})
+					mentions[m.ID.String()] = m.Content
+				}
+
+				tasks, err := client.ListTasks(p.ID.String(), "")
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				for _, t := range tasks {
+					g.AddNode(graph.Node{ID: t.ID.String(), Kind: graph.KindTask, Label: truncateString(t.Title, 48), Group: p.Name})
+					mentions[t.ID.String()] = t.Title + "\n" + t.Description
+				}
+
+				var mu sync.Mutex
+				forEachLimited(8, len(tasks), func(i int) {
+					linked, err := client.ListTaskMemories(tasks[i].ID.String())
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					for _, m := range linked {
+						taskLinks = append(taskLinks, graph.Edge{From: tasks[i].ID.String(), To: m.ID.String(), Label: "linked"})
+					}
+				})
+
+				projectLinks, err := client.ListMemoryLinks("", p.ID.String())
+				if err != nil {
+					fmt.Println(apierrors.ParseAPIError(err))
+					return err
+				}
+				links = append(links, projectLinks...)
+			}
+
+			decisions, err := client.ListDecisions("", "", 0)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			for _, d := range decisions {
+				if c.String("project") != "" && d.ProjectID != nil && *d.ProjectID != projects[0].ID.String() {
+					continue
+				}
+				g.AddNode(graph.Node{ID: d.ID, Kind: graph.KindDecision, Label: strings.TrimSpace(d.ADRNumber + " " + truncateString(d.Title, 48))})
+			}
+
+			for _, l := range links {
+				g.AddEdge(graph.Edge{From: l.MemoryID.String(), To: l.TargetMemoryID.String(), Label: l.RelationType})
+			}
+			// Task links arrive concurrently; keep the output in node order
+			for _, n := range g.Nodes {
+				for _, e := range taskLinks {
+					if e.From == n.ID {
+						g.AddEdge(e)
+					}
+				}
+			}
+			for _, n := range g.Nodes {
+				text := mentions[n.ID]
+				if text == "" {
+					continue
+				}
+				for _, d := range decisions {
+					if d.ADRNumber != "" && strings.Contains(text, d.ADRNumber) {
+						g.AddEdge(graph.Edge{From: n.ID, To: d.ID, Label: "mentions"})
+					}
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := g.Write(&buf, c.String("format")); err != nil {
+				return err
+			}
+			path := c.String("output")
+			if path == "" {
+				_, err := os.Stdout.Write(buf.Bytes())
+				return err
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("🕸️  Wrote %d nodes and %d edges to %s\n", len(g.Nodes), len(g.Edges), path)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,589 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// vaultNote is one markdown file of a notes vault prepared for import
+type vaultNote struct {
+	Path   string // slash-separated, relative to the vault root
+	Name   string // file name without .md, the target of [[wikilinks]]
+	Folder string // top-level folder, "" for notes in the vault root
+
+	Project string // project from front matter, overrides the folder
+	Tags    []string
+	Links   []string // raw [[wikilink]] targets
+	Parts   []string // memory contents, more than one when over the size limit
+	Hashes  []string // content hash of each part
+
+	ProjectID string
+	MemoryIDs []string // per part, set once imported (or found from an earlier import)
+	Stored    int      // parts found from an earlier import
+	Existing  bool
+	Err       error
+}
+
+var (
+	hashtagPattern  = regexp.MustCompile(`(?:^|[\s(,])#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+	wikilinkPattern = regexp.MustCompile(`\[\[([^\[\]|#]+)(?:#[^\[\]|]*)?(?:\|[^\[\]]*)?\]\]`)
+	inlineCode      = regexp.MustCompile("`[^`\n]*`")
+)
+
+// memoryImportCmd imports a directory of markdown notes as memories.
+func memoryImportCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import a markdown notes directory (e.g. an Obsidian vault) as memories",
+		ArgsUsage: "<dir>",
+		Description: "Every .md file becomes a memory. Front matter tags and #hashtags become tags,\n" +
+			"top-level folders become projects (or contexts) and [[wikilinks]] between notes\n" +
+			"become memory links. Notes whose content is already stored are skipped, so the\n" +
+			"import can be re-run after the vault changes.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project for notes in the vault root (and all notes unless --folders=project). Defaults to the active project.",
+			},
+			&cli.StringFlag{
+				Name:  "folders",
+				Usage: "Map top-level folders to: project, context (tagged and created as contexts) or none",
+				Value: "project",
+			},
+			&cli.StringSliceFlag{
+				Name:    "tags",
+				Aliases: []string{"t"},
+				Usage:   "Extra tags added to every imported memory",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of notes imported in parallel",
+				Value: 4,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be imported without changing anything",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("vault directory is required")
+			}
+			folders := c.String("folders")
+			if folders != "project" && folders != "context" && folders != "none" {
+				return fmt.Errorf("invalid --folders value '%s' (use project, context or none)", folders)
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			dryRun := c.Bool("dry-run")
+
+			notes, err := readVault(c.Args().First())
+			if err != nil {
+				return err
+			}
+			if len(notes) == 0 {
+				fmt.Println("No markdown notes found.")
+				return nil
+			}
+			for _, n := range notes {
+				for _, tag := range c.StringSlice("tags") {
+					if !containsFold(n.Tags, tag) {
+						n.Tags = append(n.Tags, tag)
+					}
+				}
+				if folders == "context" && n.Folder != "" && !containsFold(n.Tags, n.Folder) {
+					n.Tags = append(n.Tags, n.Folder)
+				}
+			}
+
+			client := api.NewClient()
+
+			defaultProject := c.String("project")
+			if defaultProject != "" {
+				if defaultProject, err = resolveProjectArg(client, defaultProject); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				defaultProject = cfg.ActiveProjectID
+			}
+
+			if err := assignProjects(client, notes, folders == "project", defaultProject, dryRun); err != nil {
+				return err
+			}
+			if folders == "context" {
+				if err := ensureContexts(client, notes, dryRun); err != nil {
+					return err
+				}
+			}
+			if err := scanNotes(notes); err != nil {
+				return err
+			}
+			if err := markImported(client, notes); err != nil {
+				return err
+			}
+
+			byName := indexNotes(notes)
+
+			if dryRun {
+				printImportPlan(notes, byName)
+				return nil
+			}
+
+			// Create the memories
+			var pending []*vaultNote
+			for _, n := range notes {
+				if !n.Existing {
+					pending = append(pending, n)
+				}
+			}
+			forEachLimited(concurrency, len(pending), func(i int) {
+				n := pending[i]
+				if n.Err != nil {
+					return
+				}
+				if n.MemoryIDs == nil {
+					n.MemoryIDs = make([]string, len(n.Parts))
+				}
+				for i, part := range n.Parts {
+					if n.MemoryIDs[i] != "" {
+						continue
+					}
+					memory, err := client.CreateMemory(n.ProjectID, part, n.Tags...)
+					if err != nil {
+						n.Err = err
+						return
+					}
+					n.MemoryIDs[i] = memory.ID.String()
+				}
+			})
+
+			// Link new notes to the notes they reference
+			type link struct{ from, to string }
+			var links []link
+			for _, n := range pending {
+				if n.Err != nil {
+					continue
+				}
+				for _, target := range resolveLinks(n, byName) {
+					if len(target.MemoryIDs) > 0 && target.MemoryIDs[0] != "" {
+						links = append(links, link{n.MemoryIDs[0], target.MemoryIDs[0]})
+					}
+				}
+			}
+			var mu sync.Mutex
+			linkErrors := 0
+			forEachLimited(concurrency, len(links), func(i int) {
+				if _, err := client.CreateMemoryLink(links[i].from, links[i].to, "see-also"); err != nil {
+					mu.Lock()
+					linkErrors++
+					mu.Unlock()
+				}
+			})
+
+			imported, memories, failed := 0, 0, 0
+			for _, n := range pending {
+				if n.Err != nil {
+					failed++
+					fmt.Printf("❌ %s: %s\n", n.Path, apierrors.ParseAPIError(n.Err))
+					continue
+				}
+				imported++
+				memories += len(n.MemoryIDs) - n.Stored
+			}
+
+			fmt.Printf("📥 Imported %d notes as %d memories, skipped %d unchanged, created %d links.\n",
+				imported, memories, len(notes)-len(pending), len(links)-linkErrors)
+			if linkErrors > 0 {
+				fmt.Printf("⚠️  %d links could not be created\n", linkErrors)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d notes failed to import", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// readVault parses every markdown file under root, skipping hidden folders such as .obsidian
+func readVault(root string) ([]*vaultNote, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	var notes []*vaultNote
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".md") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		note, err := parseVaultNote(filepath.ToSlash(rel), string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if note != nil {
+			notes = append(notes, note)
+		}
+		return nil
+	})
+	return notes, err
+}
+
+// parseVaultNote turns a note into memory content; it returns nil for empty notes
+func parseVaultNote(rel, text string) (*vaultNote, error) {
+	fm, body, err := parseFrontMatter(text)
+	if err != nil {
+		return nil, err
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, nil
+	}
+
+	name := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	n := &vaultNote{Path: rel, Name: name, Project: fm.Project}
+	if i := strings.Index(rel, "/"); i >= 0 {
+		n.Folder = rel[:i]
+	}
+
+	for _, tag := range append([]string(fm.Tags), hashtags(body)...) {
+		if !containsFold(n.Tags, tag) {
+			n.Tags = append(n.Tags, tag)
+		}
+	}
+	n.Links = wikilinks(body)
+
+	// The file name is the note title in a vault
+	title := fm.Title
+	if title == "" {
+		title = name
+	}
+	content := body
+	if !strings.HasPrefix(body, "# ") {
+		content = "# " + title + "\n\n" + body
+	}
+
+	n.Parts = []string{content}
+	if !constants.IsWithinMemoryLimit(content) {
+		n.Parts = chunkContent(content, constants.MaxMemoryChars-64)
+		for i := range n.Parts {
+			n.Parts[i] = fmt.Sprintf("(part %d/%d)\n\n%s", i+1, len(n.Parts), n.Parts[i])
+		}
+	}
+	n.Hashes = hashParts(n.Parts)
+	return n, nil
+}
+
+// hashtags returns the #tags of a note body, ignoring code and headings
+func hashtags(body string) []string {
+	var tags []string
+	for _, line := range proseLines(body) {
+		for _, m := range hashtagPattern.FindAllStringSubmatch(line, -1) {
+			tag := strings.TrimRight(m[1], "/-")
+			// A tag needs at least one non-digit, so "#1" stays an issue reference
+			if strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+				continue
+			}
+			if !containsFold(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// wikilinks returns the targets of the [[wikilinks]] in a note body
+func wikilinks(body string) []string {
+	var links []string
+	for _, line := range proseLines(body) {
+		for _, m := range wikilinkPattern.FindAllStringSubmatch(line, -1) {
+			target := strings.TrimSpace(m[1])
+			if target != "" && !containsFold(links, target) {
+				links = append(links, target)
+			}
+		}
+	}
+	return links
+}
+
+// proseLines returns the lines outside fenced code blocks with inline code removed
+func proseLines(body string) []string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			lines = append(lines, inlineCode.ReplaceAllString(line, ""))
+		}
+	}
+	return lines
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashParts(parts []string) []string {
+	hashes := make([]string, len(parts))
+	for i, part := range parts {
+		hashes[i] = contentHash(part)
+	}
+	return hashes
+}
+
+// indexNotes maps lower-cased note names and paths (without .md) to notes
+func indexNotes(notes []*vaultNote) map[string]*vaultNote {
+	byName := map[string]*vaultNote{}
+	for _, n := range notes {
+		byName[strings.ToLower(strings.TrimSuffix(n.Path, path.Ext(n.Path)))] = n
+	}
+	// Bare names resolve to the first note with that name, as in Obsidian
+	for _, n := range notes {
+		if _, ok := byName[strings.ToLower(n.Name)]; !ok {
+			byName[strings.ToLower(n.Name)] = n
+		}
+	}
+	return byName
+}
+
+// resolveLinks returns the notes a note links to, ignoring links to missing notes and itself
+func resolveLinks(n *vaultNote, byName map[string]*vaultNote) []*vaultNote {
+	var targets []*vaultNote
+	for _, link := range n.Links {
+		key := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(link, "/"), ".md"))
+		target, ok := byName[key]
+		if !ok || target == n {
+			continue
+		}
+		dup := false
+		for _, t := range targets {
+			dup = dup || t == target
+		}
+		if !dup {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// assignProjects sets each note's project from its front matter, its top-level
+// folder (when foldersAsProjects) or the default, creating missing folder projects.
+func assignProjects(client *api.Client, notes []*vaultNote, foldersAsProjects bool, defaultProject string, dryRun bool) error {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return fmt.Errorf("could not fetch projects: %w", err)
+	}
+	ids := map[string]string{}
+	for _, p := range projects {
+		ids[strings.ToLower(p.Name)] = p.ID.String()
+		ids[strings.ToLower(p.ID.String())] = p.ID.String()
+	}
+
+	for _, n := range notes {
+		name := n.Project
+		if name == "" && foldersAsProjects {
+			name = n.Folder
+		}
+		if name == "" {
+			if defaultProject == "" {
+				return fmt.Errorf("no active project set for %s. Use 'ramorie project use <id>' or specify --project", n.Path)
+			}
+			n.ProjectID = defaultProject
+			continue
+		}
+
+		id, ok := ids[strings.ToLower(name)]
+		if !ok {
+			if dryRun {
+				fmt.Printf("📁 Would create project '%s'\n", name)
+				id = "new:" + name
+			} else {
+				project, err := client.CreateProject(name, "Imported from notes vault")
+				if err != nil {
+					return fmt.Errorf("could not create project '%s': %w", name, err)
+				}
+				fmt.Printf("📁 Created project '%s'\n", name)
+				id = project.ID.String()
+			}
+			ids[strings.ToLower(name)] = id
+		}
+		n.ProjectID = id
+	}
+	return nil
+}
+
+// ensureContexts creates a context for each top-level folder that has none
+func ensureContexts(client *api.Client, notes []*vaultNote, dryRun bool) error {
+	contexts, err := client.ListContexts()
+	if err != nil {
+		return fmt.Errorf("could not fetch contexts: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, ctx := range contexts {
+		seen[strings.ToLower(ctx.Name)] = true
+	}
+	for _, n := range notes {
+		if n.Folder == "" || seen[strings.ToLower(n.Folder)] {
+			continue
+		}
+		seen[strings.ToLower(n.Folder)] = true
+		if dryRun {
+			fmt.Printf("🗂️  Would create context '%s'\n", n.Folder)
+			continue
+		}
+		if _, err := client.CreateContext(n.Folder, "Imported from notes vault"); err != nil {
+			return fmt.Errorf("could not create context '%s': %w", n.Folder, err)
+		}
+		fmt.Printf("🗂️  Created context '%s'\n", n.Folder)
+	}
+	return nil
+}
+
+// scanNotes applies the secret scan policy to the notes. Blocked notes get
+// an error; redacted ones are rehashed so re-imports still recognise them.
+func scanNotes(notes []*vaultNote) error {
+	scanner, err := loadScanner()
+	if err != nil {
+		return err
+	}
+	for _, n := range notes {
+		for i, part := range n.Parts {
+			if n.Parts[i], n.Err = guardWith(scanner, n.Path, part); n.Err != nil {
+				break
+			}
+		}
+		if n.Err == nil {
+			n.Hashes = hashParts(n.Parts)
+		}
+	}
+	return nil
+}
+
+// markImported flags notes whose content is already stored in their project.
+// A note split into parts counts as imported only when every part is stored;
+// the parts that are already there are kept, so only the rest get created.
+func markImported(client *api.Client, notes []*vaultNote) error {
+	hashes := map[string]map[string]string{}
+	for _, n := range notes {
+		if strings.HasPrefix(n.ProjectID, "new:") {
+			continue
+		}
+		known, ok := hashes[n.ProjectID]
+		if !ok {
+			memories, err := client.ListMemories(n.ProjectID, "")
+			if err != nil {
+				return fmt.Errorf("could not fetch memories: %w", err)
+			}
+			known = map[string]string{}
+			for _, m := range memories {
+				known[contentHash(m.Content)] = m.ID.String()
+			}
+			hashes[n.ProjectID] = known
+		}
+		ids := make([]string, len(n.Hashes))
+		stored := 0
+		for i, hash := range n.Hashes {
+			if id, ok := known[hash]; ok {
+				ids[i] = id
+				stored++
+			}
+		}
+		if stored > 0 {
+			n.MemoryIDs, n.Stored = ids, stored
+			n.Existing = stored == len(n.Parts)
+		}
+	}
+	return nil
+}
+
+func printImportPlan(notes []*vaultNote, byName map[string]*vaultNote) {
+	sorted := append([]*vaultNote{}, notes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	create, links := 0, 0
+	for _, n := range sorted {
+		if n.Existing {
+			fmt.Printf("  = %s (unchanged)\n", n.Path)
+			continue
+		}
+		if n.Err != nil {
+			fmt.Printf("  ! %s: %v\n", n.Path, n.Err)
+			continue
+		}
+		create++
+		line := "  + " + n.Path
+		if n.Stored > 0 {
+			line += fmt.Sprintf(" (%d of %d parts missing)", len(n.Parts)-n.Stored, len(n.Parts))
+		} else if len(n.Parts) > 1 {
+			line += fmt.Sprintf(" (%d parts)", len(n.Parts))
+		}
+		if len(n.Tags) > 0 {
+			line += " [" + strings.Join(n.Tags, ", ") + "]"
+		}
+		if targets := resolveLinks(n, byName); len(targets) > 0 {
+			links += len(targets)
+			line += fmt.Sprintf(" → %d links", len(targets))
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("\nDry run: %d notes would be imported, %d skipped as unchanged or blocked, %d links created.\n", create, len(notes)-create, links)
+}
+
+// forEachLimited calls fn for 0..n-1 with at most limit calls running at once
+func forEachLimited(limit, n int, fn func(i int)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
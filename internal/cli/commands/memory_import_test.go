@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/fakeapi"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestParseVaultNote(t *testing.T) {
+	text := "---\ntags: [k8s]\naliases: [ingress]\n---\n" +
+		"Traefik routes via #networking and #k8s, see [[Docker Setup|docker]] and [[infra/DNS#records]].\n" +
+		"Issue #12 is unrelated.\n" +
+		"```\n#not-a-tag [[Not A Link]]\n```\n" +
+		"Inline `#code` is ignored.\n"
+
+	n, err := parseVaultNote("infra/Traefik.md", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Name != "Traefik" || n.Folder != "infra" {
+		t.Errorf("name/folder = %q/%q", n.Name, n.Folder)
+	}
+	if want := []string{"k8s", "networking"}; !reflect.DeepEqual(n.Tags, want) {
+		t.Errorf("tags = %v, want %v", n.Tags, want)
+	}
+	if want := []string{"Docker Setup", "infra/DNS"}; !reflect.DeepEqual(n.Links, want) {
+		t.Errorf("links = %v, want %v", n.Links, want)
+	}
+	if len(n.Parts) != 1 || n.Parts[0][:11] != "# Traefik\n\n" {
+		t.Errorf("content should start with the file name as title: %q", n.Parts)
+	}
+
+	empty, err := parseVaultNote("Empty.md", "---\ntags: [x]\n---\n\n")
+	if err != nil || empty != nil {
+		t.Errorf("empty note = %v, %v; want nil", empty, err)
+	}
+}
+
+func TestResolveLinks(t *testing.T) {
+	traefik := &vaultNote{Path: "infra/Traefik.md", Name: "Traefik", Links: []string{"docker setup", "infra/DNS", "Missing", "Traefik"}}
+	docker := &vaultNote{Path: "Docker Setup.md", Name: "Docker Setup"}
+	dns := &vaultNote{Path: "infra/DNS.md", Name: "DNS"}
+	byName := indexNotes([]*vaultNote{traefik, docker, dns})
+
+	got := resolveLinks(traefik, byName)
+	if want := []*vaultNote{docker, dns}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveLinks = %v, want %v", got, want)
+	}
+}
+
+func TestMarkImportedPartialNote(t *testing.T) {
+	project := uuid.MustParse("0e000000-0000-4000-8000-000000000001")
+	stored := uuid.MustParse("cccccccc-0000-4000-8000-000000000001")
+	parts := []string{"(part 1/2)\n\n# Big\n\nfirst", "(part 2/2)\n\nsecond"}
+
+	backend := fakeapi.NewServer(&fakeapi.Store{
+		Projects: []models.Project{{ID: project, Name: "orion"}},
+		Memories: []models.Memory{{ID: stored, ProjectID: project, Content: parts[0]}},
+	})
+	t.Cleanup(backend.Close)
+
+	n := &vaultNote{Path: "Big.md", Parts: parts, Hashes: hashParts(parts), ProjectID: project.String()}
+	if err := markImported(backend.APIClient(), []*vaultNote{n}); err != nil {
+		t.Fatal(err)
+	}
+	if n.Existing {
+		t.Error("a note with a missing part was marked as imported")
+	}
+	if want := []string{stored.String(), ""}; !reflect.DeepEqual(n.MemoryIDs, want) || n.Stored != 1 {
+		t.Errorf("memory IDs = %v (%d stored), want %v", n.MemoryIDs, n.Stored, want)
+	}
+}
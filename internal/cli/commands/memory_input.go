@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// memoryFrontMatter is the optional YAML header of a memory note:
+//
+//	---
+//	title: Traefik gotchas
+//	tags: [traefik, docker]
+//	project: orion
+//	task: 3f2a9c1e
+//	---
+type memoryFrontMatter struct {
+	Title   string  `yaml:"title"`
+	Tags    tagList `yaml:"tags"`
+	Project string  `yaml:"project"`
+	Task    string  `yaml:"task"`
+	Linked  string  `yaml:"linked_task"`
+
+	// Structured memories, e.g. type: gotcha with fields symptom/cause/fix
+	Type   string            `yaml:"type"`
+	Fields map[string]string `yaml:"fields"`
+
+	// Lifecycle, e.g. expires: 2025-06-30 or review_after: 90d
+	Expires     string `yaml:"expires"`
+	ReviewAfter string `yaml:"review_after"`
+	Evergreen   bool   `yaml:"evergreen"`
+}
+
+// tagList accepts tags as a YAML list or a comma-separated string
+type tagList []string
+
+func (t *tagList) UnmarshalYAML(value *yaml.Node) error {
+	var list []string
+	if value.Kind == yaml.SequenceNode {
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+	} else {
+		var s string
+		if err := value.Decode(&s); err != nil {
+			return err
+		}
+		list = strings.Split(s, ",")
+	}
+	for _, tag := range list {
+		if tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")); tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
+}
+
+// taskID returns the linked task from either the task or linked_task key
+func (fm memoryFrontMatter) taskID() string {
+	if fm.Task != "" {
+		return fm.Task
+	}
+	return fm.Linked
+}
+
+// memoryTemplate is shown in $EDITOR by `remember --edit`
+const memoryTemplate = `---
+title:
+tags: []
+project:
+task:
+---
+
+`
+
+// readMemoryInput collects memory content from --file, --edit, stdin ("-")
+// or the command arguments, in that order of precedence.
+func readMemoryInput(c *cli.Context) (string, error) {
+	switch {
+	case c.String("file") != "":
+		data, err := os.ReadFile(c.String("file"))
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %w", c.String("file"), err)
+		}
+		return string(data), nil
+	case c.Bool("edit"):
+		return editInEditor(memoryTemplate + strings.Join(c.Args().Slice(), " "))
+	case c.NArg() == 1 && c.Args().First() == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("could not read stdin: %w", err)
+		}
+		return string(data), nil
+	default:
+		// Keep every argument so unquoted notes are not cut off after the first word
+		return strings.Join(c.Args().Slice(), " "), nil
+	}
+}
+
+// editInEditor opens $VISUAL/$EDITOR (falling back to vi) on a temp file
+// seeded with initial and returns the saved content.
+func editInEditor(initial string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "ramorie-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseFrontMatter splits an optional leading YAML front matter block from the body
+func parseFrontMatter(text string) (memoryFrontMatter, string, error) {
+	var fm memoryFrontMatter
+	header, body, err := splitFrontMatter(text)
+	if err != nil {
+		return fm, "", err
+	}
+	if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+		return fm, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	return fm, body, nil
+}
+
+// splitFrontMatter returns the YAML between leading --- lines and the rest of the text
+func splitFrontMatter(text string) (string, string, error) {
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", text, nil
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	header, body := "", ""
+	switch {
+	case strings.HasPrefix(rest, "---\n"):
+		body = rest[len("---\n"):]
+	case end >= 0:
+		header, body = rest[:end], rest[end+len("\n---\n"):]
+	case strings.HasSuffix(rest, "\n---"):
+		header = strings.TrimSuffix(rest, "\n---")
+	default:
+		return "", "", fmt.Errorf("front matter is not closed with ---")
+	}
+	return header, strings.TrimLeft(body, "\n"), nil
+}
+
+// chunkContent splits content into pieces of at most max bytes, preferring
+// paragraph breaks, then line breaks, then hard cuts.
+func chunkContent(content string, max int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		current.Reset()
+	}
+	add := func(piece, sep string) {
+		if current.Len() > 0 && current.Len()+len(sep)+len(piece) > max {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString(sep)
+		}
+		current.WriteString(piece)
+	}
+
+	for _, para := range strings.Split(content, "\n\n") {
+		if len(para) <= max {
+			add(para, "\n\n")
+			continue
+		}
+		for _, line := range strings.Split(para, "\n") {
+			for len(line) > max {
+				flush()
+				cut := max
+				// Do not split a multi-byte character
+				for cut > 0 && line[cut]&0xC0 == 0x80 {
+					cut--
+				}
+				chunks = append(chunks, line[:cut])
+				line = line[cut:]
+			}
+			add(line, "\n")
+		}
+	}
+	flush()
+	return chunks
+}
+
+// confirm asks a yes/no question on the terminal; it returns false when stdin is not interactive
+func confirm(question string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	fmt.Print(question + " (y/N): ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// resolveProjectArg resolves a project name, full ID or short ID to a full ID
+func resolveProjectArg(client *api.Client, arg string) (string, error) {
+	projects, err := client.ListProjects()
+	if err != nil {
+		return "", fmt.Errorf("could not fetch projects: %w", err)
+	}
+	return matchProject(projects, arg)
+}
+
+// matchProject finds a project by full ID or name, then by a unique ID
+// prefix, so a project named like the start of another's ID is not mistaken
+// for it
+func matchProject(projects []models.Project, arg string) (string, error) {
+	for _, p := range projects {
+		if p.ID.String() == strings.ToLower(arg) || strings.EqualFold(p.Name, arg) {
+			return p.ID.String(), nil
+		}
+	}
+	var found []string
+	for _, p := range projects {
+		if arg != "" && strings.HasPrefix(p.ID.String(), strings.ToLower(arg)) {
+			found = append(found, p.ID.String())
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("project '%s' not found", arg)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("project ID '%s' is ambiguous (%d projects start with it)", arg, len(found))
+}
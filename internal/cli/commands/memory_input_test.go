@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantFM   memoryFrontMatter
+		wantBody string
+		wantErr  bool
+	}{
+		{"no front matter", "just a note", memoryFrontMatter{}, "just a note", false},
+		{
+			"list tags and task",
+			"---\ntitle: Traefik\ntags: [traefik, docker]\nproject: orion\ntask: 3f2a9c1e\n---\n\nBody\n",
+			memoryFrontMatter{Title: "Traefik", Tags: tagList{"traefik", "docker"}, Project: "orion", Task: "3f2a9c1e"},
+			"Body\n", false,
+		},
+		{
+			"comma tags and linked_task",
+			"---\r\ntags: a, b\r\nlinked_task: 42\r\n---\r\nBody",
+			memoryFrontMatter{Tags: tagList{"a", "b"}, Linked: "42"},
+			"Body", false,
+		},
+		{"empty header", "---\n---\nBody", memoryFrontMatter{}, "Body", false},
+		{"unclosed", "---\ntags: [a]\nBody", memoryFrontMatter{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := parseFrontMatter(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(fm, tt.wantFM) {
+				t.Errorf("front matter = %+v, want %+v", fm, tt.wantFM)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestChunkContent(t *testing.T) {
+	content := strings.Repeat("a", 30) + "\n\n" + strings.Repeat("b", 30) + "\n\n" + strings.Repeat("é", 40)
+	chunks := chunkContent(content, 64)
+
+	if got := strings.Join(chunks, ""); strings.ReplaceAll(content, "\n", "") != strings.ReplaceAll(got, "\n", "") {
+		t.Fatalf("chunks lost content: %q", chunks)
+	}
+	for _, c := range chunks {
+		if len(c) > 64 {
+			t.Errorf("chunk of %d bytes exceeds limit", len(c))
+		}
+		if !strings.HasPrefix(c, "a") && !strings.HasPrefix(c, "b") && !strings.HasPrefix(c, "é") {
+			t.Errorf("chunk split inside a character: %q", c)
+		}
+	}
+	if len(chunks) != 3 {
+		t.Errorf("got %d chunks, want 3 (two paragraphs fit together, the long line is cut once)", len(chunks))
+	}
+}
+
+func TestMatchProject(t *testing.T) {
+	projects := []models.Project{
+		{ID: uuid.MustParse("cafe0001-0000-4000-8000-000000000001"), Name: "orion"},
+		{ID: uuid.MustParse("cafe0002-0000-4000-8000-000000000002"), Name: "vega"},
+		{ID: uuid.MustParse("a1b20003-0000-4000-8000-000000000003"), Name: "cafe"},
+	}
+	for arg, want := range map[string]string{
+		"cafe":                                 "a1b20003-0000-4000-8000-000000000003", // a name wins over an ID prefix
+		"Orion":                                "cafe0001-0000-4000-8000-000000000001",
+		"cafe0002":                             "cafe0002-0000-4000-8000-000000000002",
+		"A1B2":                                 "a1b20003-0000-4000-8000-000000000003",
+		"cafe0001-0000-4000-8000-000000000001": "cafe0001-0000-4000-8000-000000000001",
+	} {
+		if got, err := matchProject(projects, arg); err != nil || got != want {
+			t.Errorf("matchProject(%q) = %q, %v; want %q", arg, got, err, want)
+		}
+	}
+	for _, arg := range []string{"cafe0", "lyra", ""} {
+		if got, err := matchProject(projects, arg); err == nil {
+			t.Errorf("matchProject(%q) = %q, want an error", arg, got)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/freshness"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// lifecycleFlags are the expiry and review flags shared by remember and memory schedule
+func lifecycleFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "expires",
+			Usage: "When the memory stops being true and is hidden from recall, e.g. 30d, 6mo or 2025-06-30",
+		},
+		&cli.StringFlag{
+			Name:  "review-after",
+			Usage: "When the memory should be checked in 'memory review', e.g. 90d or 2025-06-30",
+		},
+		&cli.BoolFlag{
+			Name:  "evergreen",
+			Usage: "The memory does not go stale; recall ranking does not decay with its age",
+		},
+	}
+}
+
+// lifecycleUpdates builds the memory update for the lifecycle flags, falling
+// back to the front matter. "never" clears a date.
+func lifecycleUpdates(c *cli.Context, fm memoryFrontMatter) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+	for _, f := range []struct{ flag, key, fallback string }{
+		{"expires", "expires_at", fm.Expires},
+		{"review-after", "review_after", fm.ReviewAfter},
+	} {
+		value := c.String(f.flag)
+		if value == "" {
+			value = f.fallback
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "":
+		case "never", "none":
+			updates[f.key] = nil
+		default:
+			t, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("--%s: %w", f.flag, err)
+			}
+			updates[f.key] = t.UTC().Format(time.RFC3339)
+		}
+	}
+	if c.IsSet("evergreen") {
+		updates["evergreen"] = c.Bool("evergreen")
+	} else if fm.Evergreen {
+		updates["evergreen"] = true
+	}
+	return updates, nil
+}
+
+// printLifecycle shows a memory's expiry, review date and evergreen flag
+func printLifecycle(m models.Memory) {
+	now := time.Now()
+	if m.ExpiresAt != nil {
+		state := ""
+		if freshness.Expired(m, now) {
+			state = " (expired)"
+		}
+		fmt.Printf("   Expires: %s%s\n", m.ExpiresAt.Local().Format("2006-01-02"), state)
+	}
+	if m.ReviewAfter != nil {
+		state := ""
+		if freshness.Due(m, now) && !freshness.Expired(m, now) {
+			state = " (due)"
+		}
+		fmt.Printf("   Review after: %s%s\n", m.ReviewAfter.Local().Format("2006-01-02"), state)
+	}
+	if m.Evergreen {
+		fmt.Println("   Evergreen: yes")
+	}
+	if m.ArchivedAt != nil {
+		fmt.Printf("   Archived: %s\n", m.ArchivedAt.Local().Format("2006-01-02"))
+	}
+}
+
+// memoryScheduleCmd sets the expiry, review date or evergreen flag of a memory.
+func memoryScheduleCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "schedule",
+		Usage:     "Set when a memory expires or should be reviewed",
+		ArgsUsage: "[memory-id]",
+		Flags: append(lifecycleFlags(), &cli.BoolFlag{
+			Name:  "unarchive",
+			Usage: "Bring an archived memory back into recall",
+		}),
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("memory ID is required")
+			}
+			updates, err := lifecycleUpdates(c, memoryFrontMatter{})
+			if err != nil {
+				return err
+			}
+			if c.Bool("unarchive") {
+				updates["archived_at"] = nil
+			}
+			if len(updates) == 0 {
+				return fmt.Errorf("nothing to change (use --expires, --review-after, --evergreen or --unarchive)")
+			}
+
+			client := api.NewClient()
+			memory, err := client.GetMemory(c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			updated, err := client.UpdateMemory(memory.ID.String(), updates)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			fmt.Printf("🗓️  Memory %s updated.\n", updated.ID.String()[:8])
+			printLifecycle(*updated)
+			return nil
+		},
+	}
+}
+
+// memoryReviewCmd walks the memories that are due for review or expired.
+func memoryReviewCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "review",
+		Usage: "Review memories that are past their review date or expired",
+		Description: "For each due memory choose keep (still true, review again later), edit, or archive\n" +
+			"(hidden from recall). Without a terminal the queue is only listed.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project name or ID. Defaults to the active project, or all projects if none is active.",
+			},
+			&cli.StringFlag{
+				Name:  "next",
+				Usage: "When kept or edited memories come up for review again",
+				Value: "90d",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "Only list the review queue",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			next, err := parseUntil(c.String("next"))
+			if err != nil {
+				return fmt.Errorf("--next: %w", err)
+			}
+
+			client := api.NewClient()
+
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				projectID = cfg.ActiveProjectID
+			}
+
+			memories, err := client.ListMemories(projectID, "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			now := time.Now()
+			var queue []models.Memory
+			for _, m := range memories {
+				if freshness.Due(m, now) {
+					queue = append(queue, m)
+				}
+			}
+			if len(queue) == 0 {
+				fmt.Println("✨ No memories are due for review.")
+				return nil
+			}
+			sort.SliceStable(queue, func(i, j int) bool {
+				return freshness.DueDate(queue[i]).Before(freshness.DueDate(queue[j]))
+			})
+
+			interactive := !c.Bool("list") && term.IsTerminal(int(os.Stdin.Fd()))
+			var scanner *secrets.Scanner
+			if interactive {
+				if scanner, err = loadScanner(); err != nil {
+					return err
+				}
+			}
+			reader := bufio.NewReader(os.Stdin)
+			kept, edited, archived := 0, 0, 0
+
+			for i, m := range queue {
+				var reason string
+				if freshness.Expired(m, now) {
+					reason = "expired " + m.ExpiresAt.Local().Format("2006-01-02")
+				} else {
+					reason = "review due since " + m.ReviewAfter.Local().Format("2006-01-02")
+				}
+				if !interactive {
+					fmt.Printf("  %s  %-28s  %s\n", m.ID.String()[:8], reason, truncateString(memoryPreview(m), 60))
+					continue
+				}
+
+				fmt.Printf("\n📋 %d/%d  %s  — %s, last updated %s\n", i+1, len(queue), m.ID.String()[:8], reason, m.UpdatedAt.Local().Format("2006-01-02"))
+				fmt.Println(strings.TrimSpace(m.Content))
+				fmt.Print("\n[k]eep, [e]dit, [a]rchive, [s]kip, [q]uit: ")
+				answer, _ := reader.ReadString('\n')
+
+				// Kept and edited memories are current again until the next review
+				reviewed := map[string]interface{}{
+					"review_after": next.UTC().Format(time.RFC3339),
+					"expires_at":   nil,
+				}
+				switch strings.TrimSpace(strings.ToLower(answer)) {
+				case "k", "keep":
+					if _, err := client.UpdateMemory(m.ID.String(), reviewed); err != nil {
+						fmt.Println(apierrors.ParseAPIError(err))
+						return err
+					}
+					kept++
+				case "e", "edit":
+					content, err := editInEditor(strings.TrimRight(m.Content, "\n") + "\n")
+					if err != nil {
+						return err
+					}
+					if content = strings.TrimSpace(content); content == "" {
+						fmt.Println("Empty content, skipping.")
+						continue
+					}
+					if !constants.IsWithinMemoryLimit(content) {
+						chars, _, _ := constants.GetContentStats(content)
+						fmt.Printf("Content too large (%d chars, maximum %d), skipping.\n", chars, constants.MaxMemoryChars)
+						continue
+					}
+					if content, err = guardLabelled(scanner, "memory", content); err != nil {
+						fmt.Println(err)
+						continue
+					}
+					reviewed["content"] = content
+					if _, err := client.UpdateMemory(m.ID.String(), reviewed); err != nil {
+						fmt.Println(apierrors.ParseAPIError(err))
+						return err
+					}
+					edited++
+				case "a", "archive":
+					if _, err := client.UpdateMemory(m.ID.String(), map[string]interface{}{"archived_at": now.UTC().Format(time.RFC3339)}); err != nil {
+						fmt.Println(apierrors.ParseAPIError(err))
+						return err
+					}
+					archived++
+				case "q", "quit":
+					fmt.Printf("\n🗂️  Kept %d, edited %d, archived %d.\n", kept, edited, archived)
+					return nil
+				}
+			}
+
+			if !interactive {
+				fmt.Printf("\n%d memories due for review. Run 'ramorie memory review' in a terminal to go through them.\n", len(queue))
+				return nil
+			}
+			fmt.Printf("\n🗂️  Kept %d, edited %d, archived %d.\n", kept, edited, archived)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/freshness"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestParseUntil(t *testing.T) {
+	now := time.Now()
+	tests := map[string]time.Time{
+		"30d": now.AddDate(0, 0, 30),
+		"2w":  now.AddDate(0, 0, 14),
+		"6mo": now.AddDate(0, 6, 0),
+		"1y":  now.AddDate(1, 0, 0),
+		"48h": now.Add(48 * time.Hour),
+	}
+	for in, want := range tests {
+		got, err := parseUntil(in)
+		if err != nil || got.Sub(want).Abs() > time.Minute {
+			t.Errorf("parseUntil(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if got, err := parseUntil("2025-06-30"); err != nil || got.Format("2006-01-02") != "2025-06-30" {
+		t.Errorf("parseUntil(date) = %v, %v", got, err)
+	}
+	if _, err := parseUntil("soon"); err == nil {
+		t.Error("parseUntil(soon) succeeded")
+	}
+}
+
+func TestRankByFreshness(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	memory := func(n byte, age time.Duration) models.Memory {
+		return models.Memory{ID: uuid.UUID{n}, UpdatedAt: now.Add(-age)}
+	}
+
+	stale := memory(1, 3*freshness.HalfLife)
+	fresh := memory(2, 0)
+	expired := memory(3, 0)
+	expired.ExpiresAt = &past
+	evergreen := memory(4, 3*freshness.HalfLife)
+	evergreen.Evergreen = true
+
+	// Backend order is by relevance; the stale memory drops below fresher ones
+	got := rankByFreshness([]models.Memory{stale, fresh, evergreen, expired}, now, false)
+	var ids []byte
+	for _, m := range got {
+		ids = append(ids, m.ID[0])
+	}
+	if string(ids) != string([]byte{2, 4, 1}) {
+		t.Errorf("ranking = %v, want [2 4 1]", ids)
+	}
+	if got := rankByFreshness([]models.Memory{stale, expired}, now, true); len(got) != 2 {
+		t.Errorf("all: %d memories, want 2", len(got))
+	}
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	"github.com/terzigolu/josepshbrain-go/internal/diff"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// memoryEditCmd edits a memory's content in $EDITOR.
+func memoryEditCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "edit",
+		Usage:     "Edit a memory in $EDITOR (the previous content is kept in its history)",
+		ArgsUsage: "[memory-id]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("memory ID is required")
+			}
+			memoryID := c.Args().First()
+
+			client := api.NewClient()
+			memory, err := client.GetMemory(memoryID)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			edited, err := editInEditor(strings.TrimRight(memory.Content, "\n") + "\n")
+			if err != nil {
+				return err
+			}
+			edited = strings.TrimSpace(edited)
+			if edited == "" {
+				return fmt.Errorf("memory content cannot be empty (use 'ramorie memory forget' to delete it)")
+			}
+			if edited == strings.TrimSpace(memory.Content) {
+				fmt.Println("No changes.")
+				return nil
+			}
+			if !constants.IsWithinMemoryLimit(edited) {
+				chars, _, _ := constants.GetContentStats(edited)
+				return fmt.Errorf("content too large: %d chars (maximum %d)", chars, constants.MaxMemoryChars)
+			}
+			if edited, err = guardContent("memory", edited); err != nil {
+				return err
+			}
+
+			updated, err := client.UpdateMemory(memory.ID.String(), map[string]interface{}{"content": edited})
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			fmt.Printf("✏️  Memory %s updated.\n", updated.ID.String()[:8])
+			return nil
+		},
+	}
+}
+
+// memoryHistoryCmd lists the stored versions of a memory.
+func memoryHistoryCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "history",
+		Usage:     "List the versions of a memory",
+		ArgsUsage: "[memory-id]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("memory ID is required")
+			}
+
+			client := api.NewClient()
+			revisions, err := client.ListMemoryRevisions(c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if len(revisions) == 0 {
+				fmt.Println("No history yet - the memory has never been edited.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tDATE\tAUTHOR\tCONTENT")
+			fmt.Fprintln(w, "-------\t----\t------\t-------")
+			for i := len(revisions) - 1; i >= 0; i-- {
+				r := revisions[i]
+				version := strconv.Itoa(r.Version)
+				if i == len(revisions)-1 {
+					version += " (current)"
+				}
+				author := r.Author
+				if author == "" {
+					author = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", version, r.CreatedAt.Local().Format("2006-01-02 15:04"), author, truncateString(strings.Join(strings.Fields(r.Content), " "), 50))
+			}
+			w.Flush()
+			return nil
+		},
+	}
+}
+
+// memoryDiffCmd shows a unified diff between two versions of a memory.
+func memoryDiffCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Show changes between versions of a memory (default: previous vs current)",
+		ArgsUsage: "[memory-id] [v1] [v2]",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "context",
+				Aliases: []string{"U"},
+				Usage:   "Lines of context around each change",
+				Value:   3,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("memory ID is required")
+			}
+
+			client := api.NewClient()
+			revisions, err := client.ListMemoryRevisions(c.Args().First())
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			if len(revisions) < 2 {
+				fmt.Println("No history yet - the memory has never been edited.")
+				return nil
+			}
+
+			current := revisions[len(revisions)-1].Version
+			from, to := current-1, current
+			if c.NArg() >= 2 {
+				if from, err = strconv.Atoi(c.Args().Get(1)); err != nil {
+					return fmt.Errorf("invalid version %q", c.Args().Get(1))
+				}
+			}
+			if c.NArg() >= 3 {
+				if to, err = strconv.Atoi(c.Args().Get(2)); err != nil {
+					return fmt.Errorf("invalid version %q", c.Args().Get(2))
+				}
+			}
+
+			a, err := findRevision(revisions, from)
+			if err != nil {
+				return err
+			}
+			b, err := findRevision(revisions, to)
+			if err != nil {
+				return err
+			}
+
+			out := diff.Unified(a.Content, b.Content, fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), c.Int("context"))
+			if out == "" {
+				fmt.Printf("Versions %d and %d are identical.\n", from, to)
+				return nil
+			}
+			fmt.Print(out)
+			return nil
+		},
+	}
+}
+
+// memoryRestoreCmd makes an old version the current content.
+func memoryRestoreCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "restore",
+		Usage:     "Restore an earlier version of a memory",
+		ArgsUsage: "[memory-id]",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:     "version",
+				Usage:    "Version to restore (see 'memory history')",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("memory ID is required")
+			}
+
+			client := api.NewClient()
+			memory, err := client.RestoreMemoryRevision(c.Args().First(), c.Int("version"))
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			fmt.Printf("⏪ Memory %s restored to version %d.\n", memory.ID.String()[:8], c.Int("version"))
+			return nil
+		},
+	}
+}
+
+func findRevision(revisions []api.MemoryRevision, version int) (*api.MemoryRevision, error) {
+	for i := range revisions {
+		if revisions[i].Version == version {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version %d not found (versions 1-%d exist)", version, revisions[len(revisions)-1].Version)
+}
@@ -79,6 +79,10 @@ func NewOverviewCommand() *cli.Command {
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
   ramorie kanban                 Kanban board view
   ramorie reports stats          Task statistics
+  ramorie reports burndown       Burndown chart and velocity
+  ramorie reports flow           Lead/cycle time and throughput
+  ramorie standup                Standup notes since yesterday
+  ramorie digest --week          Weekly digest
 
 ⚙️  CONFIGURATION
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -93,6 +97,8 @@ func NewOverviewCommand() *cli.Command {
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
   ramorie mcp start              Start MCP server
   ramorie mcp status             Check MCP server status
+  ramorie audit tail             Show recent MCP tool calls
+  ramorie audit search --tool X  Search tool calls (--since 24h)
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 💡 TIP: Use 'ramorie <command> --help' for detailed command usage.
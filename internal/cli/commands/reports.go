@@ -5,9 +5,15 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
 
 	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/chart"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
 func NewReportsCommand() *cli.Command {
@@ -19,41 +25,44 @@ func NewReportsCommand() *cli.Command {
 			reportsStatsCmd(),
 			reportsHistoryCmd(),
 			reportsBurndownCmd(),
+			reportsFlowCmd(),
 			reportsSummaryCmd(),
 		},
 	}
 }
 
+// outputFlag selects between rendered charts and the raw report JSON. Each
+// command gets its own flag, since urfave/cli keeps parsed state on it.
+func outputFlag() *cli.StringFlag {
+	return &cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "Output format: text or json", Value: "text"}
+}
+
 func reportsStatsCmd() *cli.Command {
 	return &cli.Command{
 		Name:  "stats",
 		Usage: "Get task stats",
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project name or ID"},
+			outputFlag(),
 		},
 		Action: func(c *cli.Context) error {
-			client := api.NewClient()
-			project := c.String("project")
-			endpoint := "/reports/stats"
-			if project != "" {
-				endpoint += "?project=" + url.QueryEscape(project)
+			params := url.Values{}
+			if project := c.String("project"); project != "" {
+				params.Set("project", project)
 			}
-
-			b, err := client.Request("GET", endpoint, nil)
+			output, err := reportOutput(c)
 			if err != nil {
 				return err
 			}
-
-			var out interface{}
-			if err := json.Unmarshal(b, &out); err != nil {
-				os.Stdout.Write(b)
-				os.Stdout.Write([]byte("\n"))
-				return nil
+			b, err := fetchReport("/reports/stats", params)
+			if err != nil {
+				return err
 			}
-
-			pretty, _ := json.MarshalIndent(out, "", "  ")
-			os.Stdout.Write(pretty)
-			os.Stdout.Write([]byte("\n"))
+			var stats models.TaskStats
+			if output == "json" || !decodeReport(b, &stats, "total_tasks", "by_status") {
+				return printRawJSON(b)
+			}
+			printStats(stats, terminalWidth())
 			return nil
 		},
 	}
@@ -67,44 +76,36 @@ func reportsHistoryCmd() *cli.Command {
 			&cli.IntFlag{Name: "days", Aliases: []string{"d"}, Usage: "How many days", Value: 7},
 			&cli.IntFlag{Name: "limit", Aliases: []string{"n"}, Usage: "Max items", Value: 15},
 			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project name or ID"},
+			outputFlag(),
 		},
 		Action: func(c *cli.Context) error {
-			client := api.NewClient()
 			days := c.Int("days")
-			limit := c.Int("limit")
-			project := c.String("project")
-
 			params := url.Values{}
 			if days > 0 {
 				params.Set("days", fmt.Sprintf("%d", days))
 			}
-			if limit > 0 {
+			if limit := c.Int("limit"); limit > 0 {
 				params.Set("limit", fmt.Sprintf("%d", limit))
 			}
-			if project != "" {
+			if project := c.String("project"); project != "" {
 				params.Set("project", project)
 			}
-
-			endpoint := "/reports/history"
-			if encoded := params.Encode(); encoded != "" {
-				endpoint += "?" + encoded
+			output, err := reportOutput(c)
+			if err != nil {
+				return err
 			}
-
-			b, err := client.Request("GET", endpoint, nil)
+			b, err := fetchReport("/reports/history", params)
 			if err != nil {
 				return err
 			}
-
-			var out interface{}
-			if err := json.Unmarshal(b, &out); err != nil {
-				os.Stdout.Write(b)
-				os.Stdout.Write([]byte("\n"))
-				return nil
+			var report models.HistoryReport
+			if output == "json" || !decodeReport(b, &report, "history", "by_day") {
+				return printRawJSON(b)
 			}
-
-			pretty, _ := json.MarshalIndent(out, "", "  ")
-			os.Stdout.Write(pretty)
-			os.Stdout.Write([]byte("\n"))
+			if days <= 0 {
+				days = 7
+			}
+			printHistory(report, days, time.Now(), terminalWidth())
 			return nil
 		},
 	}
@@ -118,49 +119,247 @@ func reportsBurndownCmd() *cli.Command {
 			&cli.IntFlag{Name: "days", Aliases: []string{"d"}, Usage: "How many days", Value: 30},
 			&cli.StringFlag{Name: "interval", Aliases: []string{"i"}, Usage: "daily or weekly", Value: "daily"},
 			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project name or ID"},
+			outputFlag(),
 		},
 		Action: func(c *cli.Context) error {
-			client := api.NewClient()
-			days := c.Int("days")
-			interval := c.String("interval")
-			project := c.String("project")
-
 			params := url.Values{}
-			if days > 0 {
+			if days := c.Int("days"); days > 0 {
 				params.Set("days", fmt.Sprintf("%d", days))
 			}
-			if interval != "" {
+			if interval := c.String("interval"); interval != "" {
 				params.Set("interval", interval)
 			}
-			if project != "" {
+			if project := c.String("project"); project != "" {
 				params.Set("project", project)
 			}
-
-			endpoint := "/reports/burndown"
-			if encoded := params.Encode(); encoded != "" {
-				endpoint += "?" + encoded
+			output, err := reportOutput(c)
+			if err != nil {
+				return err
 			}
-
-			b, err := client.Request("GET", endpoint, nil)
+			b, err := fetchReport("/reports/burndown", params)
 			if err != nil {
 				return err
 			}
-
-			var out interface{}
-			if err := json.Unmarshal(b, &out); err != nil {
-				os.Stdout.Write(b)
-				os.Stdout.Write([]byte("\n"))
-				return nil
+			var report models.BurndownReport
+			if output == "json" || !decodeReport(b, &report, "points") {
+				return printRawJSON(b)
 			}
-
-			pretty, _ := json.MarshalIndent(out, "", "  ")
-			os.Stdout.Write(pretty)
-			os.Stdout.Write([]byte("\n"))
+			printBurndown(report, terminalWidth())
 			return nil
 		},
 	}
 }
 
+func fetchReport(endpoint string, params url.Values) ([]byte, error) {
+	if encoded := params.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	return api.NewClient().Request("GET", endpoint, nil)
+}
+
+// reportOutput returns the --output format, rejecting unknown values
+func reportOutput(c *cli.Context) (string, error) {
+	switch output := c.String("output"); output {
+	case "text", "json":
+		return output, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (use text or json)", output)
+	}
+}
+
+// decodeReport decodes a report response into v. It fails when the response
+// is not a JSON object with at least one of keys, so a backend using other
+// key names falls back to the raw JSON instead of rendering empty charts.
+func decodeReport(b []byte, v interface{}, keys ...string) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(b, &fields) != nil {
+		return false
+	}
+	found := false
+	for _, key := range keys {
+		if _, ok := fields[key]; ok {
+			found = true
+			break
+		}
+	}
+	return found && json.Unmarshal(b, v) == nil
+}
+
+// printRawJSON pretty-prints a response, or writes it as is if it is not JSON
+func printRawJSON(b []byte) error {
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		os.Stdout.Write(b)
+		os.Stdout.Write([]byte("\n"))
+		return nil
+	}
+	pretty, _ := json.MarshalIndent(out, "", "  ")
+	os.Stdout.Write(pretty)
+	os.Stdout.Write([]byte("\n"))
+	return nil
+}
+
+// terminalWidth is the width of stdout, or 80 when it is not a terminal
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w >= 40 {
+		return w
+	}
+	return 80
+}
+
+func printStats(stats models.TaskStats, width int) {
+	fmt.Printf("📊 %d tasks, %d completed (%.1f%%)\n", stats.TotalTasks, stats.CompletedTasks, stats.CompletionRate)
+	barWidth := min(width-32, 40)
+	for _, group := range []struct {
+		title  string
+		counts map[string]int
+		order  []string
+	}{
+		{"STATUS", stats.ByStatus, []string{"TODO", "IN_PROGRESS", "IN_REVIEW", "COMPLETED"}},
+		{"PRIORITY", stats.ByPriority, []string{"H", "M", "L"}},
+	} {
+		if len(group.counts) == 0 {
+			continue
+		}
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tCOUNT\tSHARE\n", group.title)
+		for _, key := range orderedKeys(group.counts, group.order) {
+			n := group.counts[key]
+			share := 0.0
+			if stats.TotalTasks > 0 {
+				share = float64(n) / float64(stats.TotalTasks)
+			}
+			label := key
+			if label == "" {
+				label = "(none)"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%3.0f%%\t%s\n", label, n, share*100, chart.Bar(share, 1, barWidth))
+		}
+		w.Flush()
+	}
+}
+
+// orderedKeys lists the keys of counts in the given order, then any others alphabetically
+func orderedKeys(counts map[string]int, order []string) []string {
+	var keys []string
+	known := map[string]bool{}
+	for _, k := range order {
+		known[k] = true
+		if _, ok := counts[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	var rest []string
+	for k := range counts {
+		if !known[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func printBurndown(report models.BurndownReport, width int) {
+	points := report.Points
+	if len(points) == 0 {
+		fmt.Println("No burndown data for this period.")
+		return
+	}
+	first, last := points[0], points[len(points)-1]
+	fmt.Printf("📉 Burndown (%s, %s → %s)\n\n", report.Interval, first.Date, last.Date)
+
+	actual := make([]float64, len(points))
+	ideal := make([]float64, len(points))
+	for i, p := range points {
+		actual[i] = float64(p.Remaining)
+		if p.Ideal != nil {
+			ideal[i] = *p.Ideal
+		} else if len(points) > 1 {
+			// Straight line from the starting work down to zero
+			ideal[i] = float64(first.Remaining) * float64(len(points)-1-i) / float64(len(points)-1)
+		}
+	}
+	lines := chart.Line([]chart.Series{
+		{Name: "ideal", Values: ideal, Mark: '·'},
+		{Name: "remaining", Values: actual, Mark: '●'},
+	}, []string{first.Date, last.Date}, min(width, 120), 12)
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+
+	completed := 0
+	for _, p := range points {
+		completed += p.Completed
+	}
+	fmt.Printf("\nRemaining: %d (started at %d), %d completed in this period\n", last.Remaining, first.Remaining, completed)
+
+	weeks := weeklyVelocity(points)
+	if len(weeks) > 0 {
+		if fit := width - 30; len(weeks) > fit {
+			weeks = weeks[len(weeks)-fit:]
+		}
+		total := 0.0
+		for _, v := range weeks {
+			total += v
+		}
+		fmt.Printf("Velocity:  %s  %.1f tasks/week\n", chart.Sparkline(weeks), total/float64(len(weeks)))
+	}
+}
+
+// weeklyVelocity sums completed tasks per calendar week, oldest first
+func weeklyVelocity(points []models.BurndownPoint) []float64 {
+	var weeks []float64
+	var current time.Time
+	for _, p := range points {
+		date, err := time.Parse("2006-01-02", p.Date)
+		if err != nil {
+			continue
+		}
+		monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		if len(weeks) == 0 || !monday.Equal(current) {
+			weeks = append(weeks, 0)
+			current = monday
+		}
+		weeks[len(weeks)-1] += float64(p.Completed)
+	}
+	return weeks
+}
+
+func printHistory(report models.HistoryReport, days int, now time.Time, width int) {
+	byDay := report.ByDay
+	if len(byDay) == 0 {
+		byDay = map[string]int{}
+		for _, a := range report.History {
+			byDay[a.CreatedAt.Local().Format("2006-01-02")]++
+		}
+	}
+	total := 0
+	for _, n := range byDay {
+		total += n
+	}
+	fmt.Printf("🗓  Activity, last %d days (%d events)\n\n", days, total)
+	for _, l := range chart.Heatmap(byDay, now.AddDate(0, 0, -(days-1)), now, width) {
+		fmt.Println(l)
+	}
+	if len(report.History) == 0 {
+		return
+	}
+
+	fmt.Println("\nRecent:")
+	titleWidth := width - 45
+	if titleWidth < 20 {
+		titleWidth = 20
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, a := range report.History {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			a.CreatedAt.Local().Format("2006-01-02 15:04"), a.EntityType, a.Action,
+			truncateString(a.Title, titleWidth))
+	}
+	w.Flush()
+}
+
 func reportsSummaryCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "summary",
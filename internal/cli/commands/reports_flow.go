@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/chart"
+	"github.com/terzigolu/josepshbrain-go/internal/flow"
+	"github.com/urfave/cli/v2"
+)
+
+// flowGroupings are the dimensions `reports flow --by` can break times down by
+var flowGroupings = []string{"project", "tag", "priority"}
+
+// flowReport is the --output json shape of `reports flow`
+type flowReport struct {
+	From       time.Time               `json:"from"`
+	To         time.Time               `json:"to"`
+	Lead       flow.Percentiles        `json:"lead"`
+	Cycle      flow.Percentiles        `json:"cycle"`
+	Groups     map[string][]flow.Group `json:"groups"`
+	Throughput []flow.Week             `json:"throughput"`
+	WIP        []flow.WIP              `json:"wip"`
+	Completed  []flow.Item             `json:"completed"`
+}
+
+func reportsFlowCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "flow",
+		Usage: "Lead time, cycle time, work-in-progress age and throughput",
+		Description: "Lead time runs from creation to completion, cycle time from start to completion.\n" +
+			"Times are computed from task timestamps on this machine, so any backend works.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project name or ID"},
+			&cli.IntFlag{Name: "days", Aliases: []string{"d"}, Usage: "Count tasks completed in the last N days", Value: 90},
+			&cli.StringSliceFlag{Name: "by", Usage: "Break down by project, tag and/or priority (default: all)"},
+			outputFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			output, err := reportOutput(c)
+			if err != nil {
+				return err
+			}
+			client := api.NewClient()
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			}
+			by := c.StringSlice("by")
+			if len(by) == 0 {
+				by = flowGroupings
+			}
+			for _, g := range by {
+				if !containsFold(flowGroupings, g) {
+					return fmt.Errorf("unknown grouping %q (use %s)", g, strings.Join(flowGroupings, ", "))
+				}
+			}
+			days := c.Int("days")
+			if days <= 0 {
+				days = 90
+			}
+
+			tasks, err := client.ListTasks(projectID, "")
+			if err != nil {
+				return err
+			}
+			projectNames := map[string]string{}
+			if projects, err := client.ListProjects(); err == nil {
+				for _, p := range projects {
+					projectNames[p.ID.String()] = p.Name
+				}
+			}
+
+			to := time.Now()
+			from := to.AddDate(0, 0, -days)
+			done, wip := flow.Measure(tasks, from, to)
+			report := flowReport{
+				From:       from,
+				To:         to,
+				Groups:     map[string][]flow.Group{},
+				Throughput: flow.Throughput(done, from, to),
+				WIP:        wip,
+				Completed:  done,
+			}
+			var lead, cycle []time.Duration
+			for _, it := range done {
+				lead = append(lead, it.Lead)
+				if it.Cycle > 0 {
+					cycle = append(cycle, it.Cycle)
+				}
+			}
+			report.Lead, report.Cycle = flow.Summarize(lead), flow.Summarize(cycle)
+			for _, g := range by {
+				g = strings.ToLower(g)
+				report.Groups[g] = flow.GroupBy(done, func(it flow.Item) []string {
+					switch g {
+					case "project":
+						if name := projectNames[it.Task.ProjectID.String()]; name != "" {
+							return []string{name}
+						}
+						return []string{it.Task.ProjectID.String()[:8]}
+					case "tag":
+						return getTagsAsStrings(it.Task.Tags)
+					default:
+						if it.Task.Priority == "" {
+							return []string{"(none)"}
+						}
+						return []string{it.Task.Priority}
+					}
+				})
+			}
+
+			if output == "json" {
+				b, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+				return nil
+			}
+			printFlow(report, by, days, terminalWidth())
+			return nil
+		},
+	}
+}
+
+func printFlow(r flowReport, by []string, days, width int) {
+	fmt.Printf("⏱  Flow, last %d days: %d completed, %d in progress\n", days, len(r.Completed), len(r.WIP))
+	if len(r.Completed) == 0 && len(r.WIP) == 0 {
+		fmt.Println("\nNo tasks were completed or in progress in this period.")
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tTASKS\tP50\tP85\tP95")
+	fmt.Fprintf(w, "Lead time\t%s\n", percentileCells(r.Lead))
+	fmt.Fprintf(w, "Cycle time\t%s\n", percentileCells(r.Cycle))
+	w.Flush()
+
+	if len(r.Throughput) > 0 {
+		weeks := r.Throughput
+		if fit := width - 30; len(weeks) > fit {
+			weeks = weeks[len(weeks)-fit:]
+		}
+		counts := make([]float64, len(weeks))
+		total := 0
+		for i, wk := range weeks {
+			counts[i] = float64(wk.Completed)
+			total += wk.Completed
+		}
+		fmt.Printf("Throughput: %s  %.1f tasks/week\n", chart.Sparkline(counts), float64(total)/float64(len(weeks)))
+	}
+
+	for _, g := range by {
+		groups := r.Groups[strings.ToLower(g)]
+		if len(groups) == 0 {
+			continue
+		}
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "BY %s\tLEAD\tP50\tP85\tP95\tCYCLE\tP50\tP85\tP95\n", strings.ToUpper(g))
+		for _, grp := range groups {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", truncateString(grp.Key, 24), percentileCells(grp.Lead), percentileCells(grp.Cycle))
+		}
+		w.Flush()
+	}
+
+	if len(r.WIP) > 0 {
+		fmt.Println("\nWork in progress (oldest first):")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, item := range r.WIP {
+			flag := ""
+			// Anything older than most completed work took is worth a look
+			if r.Cycle.Count > 0 && item.Age > r.Cycle.P85 {
+				flag = "⚠ older than p85 cycle time"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.TaskID[:8], truncateString(item.Title, 40), formatFlowDuration(item.Age), flag)
+		}
+		w.Flush()
+	}
+
+	var points []chart.Point
+	var labels []string
+	for _, it := range r.Completed {
+		if it.Cycle > 0 {
+			points = append(points, chart.Point{X: float64(it.Completed.Unix()), Y: flow.Days(it.Cycle)})
+			labels = append(labels, it.Completed.Local().Format("2006-01-02"))
+		}
+	}
+	if len(points) > 0 {
+		fmt.Println("\nCycle time in days by completion date:")
+		rules := []chart.Rule{{Y: flow.Days(r.Cycle.P85), Name: "p85"}}
+		for _, l := range chart.Scatter(points, rules, labels, min(width, 100), 10) {
+			fmt.Println(l)
+		}
+	}
+}
+
+// percentileCells renders count and percentiles as tab-separated cells
+func percentileCells(p flow.Percentiles) string {
+	if p.Count == 0 {
+		return "0\t-\t-\t-"
+	}
+	return fmt.Sprintf("%d\t%s\t%s\t%s", p.Count, formatFlowDuration(p.P50), formatFlowDuration(p.P85), formatFlowDuration(p.P95))
+}
+
+// formatFlowDuration shows hours below a day and days otherwise
+func formatFlowDuration(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%.0fh", d.Hours())
+	}
+	return fmt.Sprintf("%.1fd", flow.Days(d))
+}
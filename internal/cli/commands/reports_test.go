@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestDecodeReport(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		ok   bool
+	}{
+		{`{"total_tasks": 3, "by_status": {"TODO": 3}}`, true},
+		{`{"by_status": {}}`, true},
+		{`{"stats": {"total": 3}}`, false}, // other key names
+		{`{}`, false},
+		{`[1, 2]`, false},
+		{`{"total_tasks": "three"}`, false},
+		{`not json`, false},
+	} {
+		var stats models.TaskStats
+		if got := decodeReport([]byte(tc.body), &stats, "total_tasks", "by_status"); got != tc.ok {
+			t.Errorf("decodeReport(%s) = %v, want %v", tc.body, got, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// NewScanCommand creates the 'scan' command.
+func NewScanCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "scan",
+		Usage: "Scan stored memories and tasks for secrets and personal data",
+		Description: "Checks memory content, task titles, descriptions and annotations with the same rules\n" +
+			"used before content is stored. Allowlist false positives in ~/.ramorie/" + secrets.AllowlistFile + ":\n" +
+			"one literal value, 'rule:<id>' or 're:<regexp>' per line.",
+		Subcommands: []*cli.Command{
+			scanTodosCmd(),
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "Project name or ID (default: all projects)",
+			},
+			&cli.BoolFlag{
+				Name:  "redact",
+				Usage: "Replace secrets found in memories and task titles/descriptions with [REDACTED:<rule>]",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Redact without asking",
+			},
+			&cli.BoolFlag{Name: "json", Usage: "Print findings as JSON"},
+		},
+		Action: func(c *cli.Context) error {
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			client := api.NewClient()
+
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			}
+
+			hits, err := scanStored(client, scanner, projectID)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			if c.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(hits)
+			}
+			if len(hits) == 0 {
+				fmt.Println("✨ No secrets or personal data found.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "WHERE\tID\tLINE\tKIND\tRULE\tMATCH")
+			fmt.Fprintln(w, "-----\t--\t----\t----\t----\t-----")
+			for _, h := range hits {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", h.Where, h.ID[:8], h.Line, h.Kind, h.Rule, h.Masked)
+			}
+			w.Flush()
+			fmt.Printf("\n🔍 %d findings in %d items.\n", len(hits), countScanItems(hits))
+
+			if !c.Bool("redact") {
+				return nil
+			}
+			if !c.Bool("yes") && !confirm("Redact the secrets in memories and tasks?") {
+				return fmt.Errorf("aborted (use --yes to redact without asking)")
+			}
+			redacted, err := redactStored(client, scanner, hits)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			fmt.Printf("🧹 Redacted %d items.\n", redacted)
+			return nil
+		},
+	}
+}
+
+// scanHit is a finding in stored content
+type scanHit struct {
+	Where string `json:"where"` // memory, task title, task description, annotation
+	ID    string `json:"id"`
+	secrets.Finding
+}
+
+// scanStored scans the memories and tasks of a project, or of every project
+func scanStored(client *api.Client, scanner *secrets.Scanner, projectID string) ([]scanHit, error) {
+	hits := []scanHit{}
+	add := func(where, id, text string) {
+		for _, f := range scanner.Scan(text) {
+			hits = append(hits, scanHit{Where: where, ID: id, Finding: f})
+		}
+	}
+
+	memories, err := client.ListMemories(projectID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memories {
+		add("memory", m.ID.String(), m.Content)
+	}
+
+	tasks, err := client.ListTasks(projectID, "")
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([][]models.Annotation, len(tasks))
+	errs := make([]error, len(tasks))
+	forEachLimited(8, len(tasks), func(i int) {
+		annotations[i], errs[i] = client.ListAnnotations(tasks[i].ID.String())
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	for i, t := range tasks {
+		add("task title", t.ID.String(), t.Title)
+		add("task description", t.ID.String(), t.Description)
+		for _, a := range annotations[i] {
+			add("annotation", a.ID.String(), a.Content)
+		}
+	}
+	return hits, nil
+}
+
+// redactStored rewrites the memories and tasks with secrets in hits. Annotations
+// cannot be edited and are left as they are.
+func redactStored(client *api.Client, scanner *secrets.Scanner, hits []scanHit) (int, error) {
+	redacted := 0
+	done := map[string]bool{}
+	for _, h := range hits {
+		if h.Kind != secrets.KindSecret || done[h.Where+h.ID] {
+			continue
+		}
+		done[h.Where+h.ID] = true
+
+		switch h.Where {
+		case "memory":
+			memory, err := client.GetMemory(h.ID)
+			if err != nil {
+				return redacted, err
+			}
+			if _, err := client.UpdateMemory(h.ID, map[string]interface{}{"content": redactSecrets(scanner, memory.Content)}); err != nil {
+				return redacted, err
+			}
+		case "task title", "task description":
+			task, err := client.GetTask(h.ID)
+			if err != nil {
+				return redacted, err
+			}
+			field, text := "title", task.Title
+			if h.Where == "task description" {
+				field, text = "description", task.Description
+			}
+			if _, err := client.UpdateTask(h.ID, map[string]interface{}{field: redactSecrets(scanner, text)}); err != nil {
+				return redacted, err
+			}
+		default:
+			continue
+		}
+		redacted++
+	}
+	return redacted, nil
+}
+
+// redactSecrets replaces the secrets in text, leaving personal data alone
+func redactSecrets(scanner *secrets.Scanner, text string) string {
+	var found []secrets.Finding
+	for _, f := range scanner.Scan(text) {
+		if f.Kind == secrets.KindSecret {
+			found = append(found, f)
+		}
+	}
+	return secrets.Redact(text, found)
+}
+
+func countScanItems(hits []scanHit) int {
+	seen := map[string]bool{}
+	for _, h := range hits {
+		seen[h.Where+h.ID] = true
+	}
+	return len(seen)
+}
+
+// guardContent checks text that is about to be stored against the scan
+// policy. Blocked secrets return an error; otherwise the text to store is
+// returned (redacted if the policy says so) after warning about each finding.
+func guardContent(label, text string) (string, error) {
+	scanner, err := loadScanner()
+	if err != nil {
+		return "", err
+	}
+	return guardLabelled(scanner, label, text)
+}
+
+// loadScanner loads the scan settings once, for commands that store many items
+func loadScanner() (*secrets.Scanner, error) {
+	scanner, err := secrets.LoadDefault()
+	if err != nil {
+		return nil, fmt.Errorf("could not load scan settings: %w", err)
+	}
+	return scanner, nil
+}
+
+// guardLabelled is guardContent with a scanner from loadScanner
+func guardLabelled(scanner *secrets.Scanner, label, text string) (string, error) {
+	checked, err := guardWith(scanner, label, text)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", label, err)
+	}
+	return checked, nil
+}
+
+// guardWith is guardContent with a preloaded scanner; errors are not labelled
+func guardWith(scanner *secrets.Scanner, label, text string) (string, error) {
+	checked, findings, err := scanner.Check(text)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range findings {
+		verb := "contains"
+		if f.Action == secrets.PolicyRedact {
+			verb = "redacted"
+		}
+		fmt.Printf("⚠️  %s %s %s: %s\n", strings.ToUpper(label[:1])+label[1:], verb, secrets.Summary([]secrets.Finding{f}), f.Masked)
+	}
+	return checked, nil
+}
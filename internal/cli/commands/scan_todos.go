@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/todos"
+	"github.com/urfave/cli/v2"
+)
+
+// scanTodosCmd turns TODO comments in source code into tasks.
+func scanTodosCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "todos",
+		Usage:     "Create tasks from TODO, FIXME and HACK comments in source code",
+		ArgsUsage: "[path]",
+		Description: "Walks path (default: the current directory), skipping files ignored by .gitignore.\n" +
+			"Comments may name an owner and a priority: '// TODO(alice) [high]: retry on 503'.\n" +
+			"Without a priority FIXME is high, TODO medium and HACK low. Each task remembers its\n" +
+			"comment by a fingerprint (file, marker and text), so re-running skips comments that\n" +
+			"already have a task and completes the tasks whose comments were removed.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project for the tasks (name or ID). Defaults to the active project."},
+			&cli.StringSliceFlag{Name: "tags", Aliases: []string{"t"}, Usage: "Extra tags added to every new task"},
+			&cli.StringSliceFlag{Name: "exclude", Aliases: []string{"x"}, Usage: "Skip files or directories matching a glob (e.g. testdata, *.md)"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of tasks created or completed in parallel", Value: 4},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Show the tasks that would be created and completed without changing anything"},
+		},
+		Action: func(c *cli.Context) error {
+			path := c.Args().First()
+			if path == "" {
+				path = "."
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			scan, err := todos.Scan(path, c.StringSlice("exclude"))
+			if err != nil {
+				return fmt.Errorf("could not scan %s: %w", path, err)
+			}
+			items := scan.Items
+
+			client := api.NewClient()
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				projectID = cfg.ActiveProjectID
+			}
+			if projectID == "" {
+				return fmt.Errorf("no active project set. Use 'ramorie project use <id>' or specify --project")
+			}
+			tasks, err := client.ListTasks(projectID, "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			found := map[string]bool{}
+			for _, it := range items {
+				found[it.Fingerprint] = true
+			}
+			existing := map[string]bool{}
+			var gone []models.Task
+			for _, t := range tasks {
+				fp, file := todos.CodeRef(t.Description)
+				if fp == "" {
+					continue
+				}
+				existing[fp] = true
+				// Only comments this scan could have seen count as removed
+				if !found[fp] && scan.Covers(file) && t.Status != "COMPLETED" {
+					gone = append(gone, t)
+				}
+			}
+			var create []todos.Item
+			for _, it := range items {
+				if !existing[it.Fingerprint] {
+					create = append(create, it)
+				}
+			}
+			unchanged := len(items) - len(create)
+
+			if c.Bool("dry-run") {
+				for _, it := range create {
+					fmt.Printf("  + %s:%d [%s] %s %s\n", it.File, it.Line, it.Priority, todoLabel(it), truncateString(it.Title(), 60))
+				}
+				for _, t := range gone {
+					_, file := todos.CodeRef(t.Description)
+					fmt.Printf("  - %s %s (removed from %s)\n", t.ID.String()[:8], truncateString(t.Title, 60), file)
+				}
+				fmt.Printf("\nDry run: %d tasks would be created, %d completed, %d comments already have tasks.\n", len(create), len(gone), unchanged)
+				return nil
+			}
+
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			createErrs := make([]error, len(create))
+			forEachLimited(concurrency, len(create), func(i int) {
+				it := create[i]
+				title, err := guardLabelled(scanner, it.File, it.Title())
+				if err != nil {
+					createErrs[i] = err
+					return
+				}
+				description, err := guardLabelled(scanner, it.File, it.Description())
+				if err != nil {
+					createErrs[i] = err
+					return
+				}
+				tags := []string{strings.ToLower(it.Kind)}
+				if it.Owner != "" {
+					tags = append(tags, "owner:"+it.Owner)
+				}
+				for _, tag := range c.StringSlice("tags") {
+					if !containsFold(tags, tag) {
+						tags = append(tags, tag)
+					}
+				}
+				_, createErrs[i] = client.CreateTask(projectID, title, description, it.Priority, tags...)
+			})
+			completeErrs := make([]error, len(gone))
+			forEachLimited(concurrency, len(gone), func(i int) {
+				t := gone[i]
+				_, file := todos.CodeRef(t.Description)
+				if _, err := client.CreateAnnotation(t.ID.String(), "Comment removed from "+file+"; completed by ramorie scan todos"); err != nil {
+					completeErrs[i] = err
+					return
+				}
+				completeErrs[i] = client.CompleteTask(t.ID.String())
+			})
+
+			created, completed, failed := 0, 0, 0
+			for i, err := range createErrs {
+				if err != nil {
+					failed++
+					fmt.Printf("❌ %s:%d: %s\n", create[i].File, create[i].Line, apierrors.ParseAPIError(err))
+				} else {
+					created++
+				}
+			}
+			for i, err := range completeErrs {
+				if err != nil {
+					failed++
+					fmt.Printf("❌ %s: %s\n", gone[i].ID.String()[:8], apierrors.ParseAPIError(err))
+				} else {
+					completed++
+				}
+			}
+			fmt.Printf("📝 TODOs: %d tasks created, %d completed, %d comments already have tasks.\n", created, completed, unchanged)
+			if failed > 0 {
+				return fmt.Errorf("%d TODO tasks failed to sync", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// todoLabel is the marker as written: TODO(alice)
+func todoLabel(it todos.Item) string {
+	if it.Owner != "" {
+		return it.Kind + "(" + it.Owner + ")"
+	}
+	return it.Kind
+}
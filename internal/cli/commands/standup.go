@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/digest"
+	"github.com/urfave/cli/v2"
+)
+
+// digestFlags are shared by standup and digest
+func digestFlags(since string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "since", Usage: "Start of the period (yesterday, 24h, 7d, 2006-01-02)", Value: since},
+		&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Only this project (name or ID)"},
+		&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Usage: "Output format: " + strings.Join(digest.Formats, " or "), Value: "markdown"},
+	}
+}
+
+// NewStandupCommand creates the standup command.
+func NewStandupCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "standup",
+		Usage: "Standup notes: completed, started, blocked, new memories and decisions by project",
+		Description: "Assembled from your tasks, memories and decisions without AI.\n" +
+			"Blocked tasks carry a \"blocked\" tag or wait on an unfinished dependency.\n" +
+			"For an AI-written summary use `ramorie reports summary`.",
+		Flags: digestFlags("yesterday"),
+		Action: func(c *cli.Context) error {
+			return printDigest(c, "Standup – "+time.Now().Format("Mon 2 Jan 2006"))
+		},
+	}
+}
+
+// NewDigestCommand creates the digest command.
+func NewDigestCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "digest",
+		Usage: "Weekly digest of completed and started work, blockers, memories and decisions",
+		Flags: append(digestFlags(""),
+			&cli.BoolFlag{Name: "week", Usage: "Cover the last 7 days (the default)"},
+		),
+		Action: func(c *cli.Context) error {
+			if c.String("since") != "" && c.Bool("week") {
+				return fmt.Errorf("use either --week or --since")
+			}
+			if c.String("since") == "" {
+				if err := c.Set("since", "week"); err != nil {
+					return err
+				}
+			}
+			return printDigest(c, "Weekly digest")
+		},
+	}
+}
+
+func printDigest(c *cli.Context, title string) error {
+	since, err := parseSince(c.String("since"))
+	if err != nil {
+		return err
+	}
+	client := api.NewClient()
+	projectID := c.String("project")
+	if projectID != "" {
+		if projectID, err = resolveProjectArg(client, projectID); err != nil {
+			return err
+		}
+	}
+
+	var in digest.Input
+	if in.Projects, err = client.ListProjects(); err != nil {
+		return err
+	}
+	if in.Tasks, err = client.ListTasks(projectID, ""); err != nil {
+		return err
+	}
+	if in.Memories, err = client.ListMemories(projectID, ""); err != nil {
+		return err
+	}
+	// Decisions are optional; a backend without them still gets a standup
+	if decisions, err := client.ListDecisions("", "", 200); err == nil {
+		for _, d := range decisions {
+			if projectID == "" || d.ProjectID != nil && *d.ProjectID == projectID {
+				in.Decisions = append(in.Decisions, d)
+			}
+		}
+	}
+
+	out, err := digest.Build(title, in, since, time.Now()).Render(c.String("format"))
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
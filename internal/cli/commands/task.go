@@ -9,6 +9,7 @@ import (
 
 	"github.com/terzigolu/josepshbrain-go/internal/api"
 	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/worklog"
 	"github.com/urfave/cli/v2"
 )
 
@@ -33,6 +34,9 @@ func NewTaskCommand() *cli.Command {
 			taskMoveCmd(),
 			taskNextCmd(),
 			taskProgressCmd(),
+			taskExportCmd(),
+			taskImportCmd(),
+			taskBranchCmd(),
 		},
 	}
 }
@@ -177,6 +181,17 @@ func taskCreateCmd() *cli.Command {
 				return fmt.Errorf("no active project set. Use 'ramorie project use <id>' or specify --project")
 			}
 
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			if title, err = guardLabelled(scanner, "task title", title); err != nil {
+				return err
+			}
+			if description, err = guardLabelled(scanner, "task description", description); err != nil {
+				return err
+			}
+
 			task, err := client.CreateTask(projectID, title, description, priority, tags...)
 			if err != nil {
 				fmt.Println(apierrors.ParseAPIError(err))
@@ -314,6 +329,19 @@ func taskUpdateCmd() *cli.Command {
 			if len(updateData) == 0 {
 				return fmt.Errorf("at least one flag is required to update")
 			}
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			for _, field := range []string{"title", "description"} {
+				if text, ok := updateData[field].(string); ok {
+					checked, err := guardLabelled(scanner, "task "+field, text)
+					if err != nil {
+						return err
+					}
+					updateData[field] = checked
+				}
+			}
 
 			client := api.NewClient()
 			task, err := client.UpdateTask(taskID, updateData)
@@ -341,11 +369,13 @@ func taskStartCmd() *cli.Command {
 			taskID := c.Args().First()
 
 			client := api.NewClient()
-			err := client.StartTask(taskID)
+			task, err := client.StartTask(taskID)
 			if err != nil {
 				fmt.Println(apierrors.ParseAPIError(err))
 				return err
 			}
+			// The full ID, so `export ical` cannot confuse tasks sharing a prefix
+			recordWork(task.ID.String(), worklog.Start)
 
 			shortID := taskID
 			if len(taskID) > 8 {
@@ -377,6 +407,7 @@ func taskCompleteCmd() *cli.Command {
 				fmt.Println(apierrors.ParseAPIError(err))
 				return err
 			}
+			recordWork(taskID, worklog.Stop)
 
 			shortID := taskID
 			if len(taskID) > 8 {
@@ -407,6 +438,7 @@ func taskStopCmd() *cli.Command {
 				fmt.Println(apierrors.ParseAPIError(err))
 				return err
 			}
+			recordWork(taskID, worklog.Stop)
 
 			shortID := taskID
 			if len(taskID) > 8 {
@@ -419,6 +451,14 @@ func taskStopCmd() *cli.Command {
 	}
 }
 
+// recordWork notes a start or stop in the local work log for `export ical`;
+// a failure there must not fail the command
+func recordWork(taskID, action string) {
+	if err := worklog.Record(taskID, action); err != nil {
+		fmt.Printf("⚠️  Could not record work session: %v\n", err)
+	}
+}
+
 // taskActiveCmd shows the currently active task.
 func taskActiveCmd() *cli.Command {
 	return &cli.Command{
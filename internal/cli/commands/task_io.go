@@ -0,0 +1,556 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/urfave/cli/v2"
+)
+
+// taskColumns are the fields task export can write, in their default order
+var taskColumns = []string{"id", "title", "description", "status", "priority", "tags", "project", "created_at", "updated_at", "started_at", "completed_at"}
+
+// defaultTaskColumns are written when --columns is not given
+var defaultTaskColumns = []string{"id", "title", "status", "priority", "tags", "project", "created_at", "completed_at"}
+
+// taskImportFields are the task fields an import can fill; title is required
+var taskImportFields = []string{"title", "description", "priority", "status", "tags", "project"}
+
+// taskExportCmd writes tasks as CSV or JSON Lines.
+func taskExportCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export tasks as CSV or JSON Lines",
+		ArgsUsage: "[file]",
+		Description: "Writes to stdout unless a file is given. Columns: " + strings.Join(taskColumns, ", ") + ".\n" +
+			"The output can be read back with `ramorie task import`.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Usage: "Output format: csv or jsonl (default from the file extension, else csv)"},
+			&cli.StringSliceFlag{Name: "columns", Aliases: []string{"c"}, Usage: "Columns to write (default " + strings.Join(defaultTaskColumns, ",") + ")"},
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Only tasks of this project (name or ID); default all projects"},
+			&cli.StringFlag{Name: "status", Aliases: []string{"s"}, Usage: "Only tasks with this status"},
+		},
+		Action: func(c *cli.Context) error {
+			file := c.Args().First()
+			format, err := taskFileFormat(c.String("format"), file)
+			if err != nil {
+				return err
+			}
+			columns := c.StringSlice("columns")
+			if len(columns) == 0 {
+				columns = append([]string(nil), defaultTaskColumns...)
+			}
+			for i, col := range columns {
+				columns[i] = strings.ToLower(strings.TrimSpace(col))
+				if !containsFold(taskColumns, columns[i]) {
+					return fmt.Errorf("unknown column '%s' (available: %s)", col, strings.Join(taskColumns, ", "))
+				}
+			}
+			status := ""
+			if c.String("status") != "" {
+				if status, err = models.ParseStatus(c.String("status")); err != nil {
+					return err
+				}
+			}
+
+			client := api.NewClient()
+			projectID := c.String("project")
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			}
+			projects, err := client.ListProjects()
+			if err != nil {
+				return fmt.Errorf("could not fetch projects: %w", err)
+			}
+			names := map[string]string{}
+			for _, p := range projects {
+				names[p.ID.String()] = p.Name
+			}
+			tasks, err := client.ListTasks(projectID, status)
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+			var w io.Writer = os.Stdout
+			if file != "" && file != "-" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			if err := writeTasks(w, format, columns, tasks, names); err != nil {
+				return err
+			}
+			if file != "" && file != "-" {
+				fmt.Printf("📤 Exported %d tasks to %s\n", len(tasks), file)
+			}
+			return nil
+		},
+	}
+}
+
+// taskFileFormat is the explicit format or the one implied by the file name
+func taskFileFormat(format, file string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".jsonl", ".ndjson":
+			format = "jsonl"
+		default:
+			format = "csv"
+		}
+	}
+	format = strings.ToLower(format)
+	if format != "csv" && format != "jsonl" {
+		return "", fmt.Errorf("invalid format '%s' (use csv or jsonl)", format)
+	}
+	return format, nil
+}
+
+// taskValue is one column of a task as text; project holds the project name
+func taskValue(t models.Task, column string, projects map[string]string) string {
+	stamp := func(tm *time.Time) string {
+		if tm == nil || tm.IsZero() {
+			return ""
+		}
+		return tm.UTC().Format(time.RFC3339)
+	}
+	switch column {
+	case "id":
+		return t.ID.String()
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	case "status":
+		return t.Status
+	case "priority":
+		return t.Priority
+	case "tags":
+		return strings.Join(getTagsAsStrings(t.Tags), ",")
+	case "project":
+		if name, ok := projects[t.ProjectID.String()]; ok {
+			return name
+		}
+		return t.ProjectID.String()
+	case "created_at":
+		return stamp(&t.CreatedAt)
+	case "updated_at":
+		return stamp(&t.UpdatedAt)
+	case "started_at":
+		return stamp(t.StartedAt)
+	case "completed_at":
+		return stamp(t.CompletedAt)
+	}
+	return ""
+}
+
+func writeTasks(w io.Writer, format string, columns []string, tasks []models.Task, projects map[string]string) error {
+	if format == "jsonl" {
+		enc := json.NewEncoder(w)
+		for _, t := range tasks {
+			row := map[string]interface{}{}
+			for _, col := range columns {
+				v := taskValue(t, col, projects)
+				switch {
+				case col == "tags":
+					// Keep tags a list in JSON so they survive commas
+					row[col] = getTagsAsStrings(t.Tags)
+				case v == "" && strings.HasSuffix(col, "_at"):
+					row[col] = nil
+				default:
+					row[col] = v
+				}
+			}
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			record[i] = taskValue(t, col, projects)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// taskRecord is one row of an import file keyed by lowercased column name
+type taskRecord struct {
+	Line   int
+	Fields map[string]string
+}
+
+// readTaskRecords parses a CSV file with a header row, or JSON Lines
+func readTaskRecords(r io.Reader, format string) ([]taskRecord, error) {
+	var records []taskRecord
+	if format == "jsonl" {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &obj); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			fields := map[string]string{}
+			for k, v := range obj {
+				fields[strings.ToLower(strings.TrimSpace(k))] = jsonText(v)
+			}
+			records = append(records, taskRecord{Line: line, Fields: fields})
+		}
+		return records, scanner.Err()
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		fields := map[string]string{}
+		empty := true
+		for i, v := range record {
+			if i < len(header) && header[i] != "" {
+				fields[header[i]] = v
+			}
+			if strings.TrimSpace(v) != "" {
+				empty = false
+			}
+		}
+		if !empty {
+			records = append(records, taskRecord{Line: line, Fields: fields})
+		}
+	}
+	return records, nil
+}
+
+// jsonText flattens a JSON value to the text a CSV cell would hold
+func jsonText(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []interface{}:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			parts = append(parts, jsonText(item))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// parseFieldMap reads --map title=Summary,priority=Prio into field → column
+func parseFieldMap(specs []string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, spec := range specs {
+		for _, pair := range strings.Split(spec, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			field, column, ok := strings.Cut(pair, "=")
+			field = strings.ToLower(strings.TrimSpace(field))
+			column = strings.ToLower(strings.TrimSpace(column))
+			if !ok || field == "" || column == "" {
+				return nil, fmt.Errorf("invalid mapping '%s' (use field=Column)", pair)
+			}
+			if !containsFold(taskImportFields, field) {
+				return nil, fmt.Errorf("unknown field '%s' in mapping (available: %s)", field, strings.Join(taskImportFields, ", "))
+			}
+			m[field] = column
+		}
+	}
+	return m, nil
+}
+
+// taskRow is a validated import row
+type taskRow struct {
+	Line        int
+	Title       string
+	Description string
+	Priority    string
+	Status      string
+	Project     string // name or ID from the file, resolved into ProjectID
+	ProjectID   string
+	Tags        []string
+	Err         error
+	Task        *models.Task
+}
+
+// buildTaskRow maps a record onto task fields and normalizes priority and status
+func buildTaskRow(rec taskRecord, fieldMap map[string]string) *taskRow {
+	get := func(field string) string {
+		return strings.TrimSpace(rec.Fields[fieldMapColumn(fieldMap, field)])
+	}
+	row := &taskRow{Line: rec.Line, Title: get("title"), Description: get("description"), Project: get("project")}
+	for _, tag := range strings.FieldsFunc(get("tags"), func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" && !containsFold(row.Tags, tag) {
+			row.Tags = append(row.Tags, tag)
+		}
+	}
+	var err error
+	if row.Priority, err = models.ParsePriority(get("priority")); err != nil {
+		row.Err = err
+	} else if row.Status, err = models.ParseStatus(get("status")); err != nil {
+		row.Err = err
+	} else if row.Title == "" {
+		row.Err = fmt.Errorf("title is empty")
+	}
+	return row
+}
+
+// taskImportCmd creates tasks from a CSV or JSON Lines file.
+func taskImportCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Create tasks from a CSV or JSON Lines file",
+		ArgsUsage: "<file>",
+		Description: "CSV files need a header row. Columns named like task fields (" + strings.Join(taskImportFields, ", ") + ")\n" +
+			"are used directly; --map reads fields from other columns, e.g. --map title=Summary,priority=Prio.\n" +
+			"Priorities (H/High, M, L) and statuses (todo, in progress, done, ...) are normalized.\n" +
+			"Every row is validated first; nothing is created while rows are invalid unless --skip-invalid is set.",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{Name: "map", Aliases: []string{"m"}, Usage: "Map task fields to columns: field=Column[,field=Column]"},
+			&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Usage: "Input format: csv or jsonl (default from the file extension, else csv)"},
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Project for rows without one (name or ID). Defaults to the active project."},
+			&cli.StringSliceFlag{Name: "tags", Aliases: []string{"t"}, Usage: "Extra tags added to every imported task"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of tasks created in parallel", Value: 4},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Validate and show what would be created without changing anything"},
+			&cli.BoolFlag{Name: "skip-invalid", Usage: "Import the valid rows even when others fail validation"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("import file is required (use - for stdin)")
+			}
+			file := c.Args().First()
+			format, err := taskFileFormat(c.String("format"), file)
+			if err != nil {
+				return err
+			}
+			fieldMap, err := parseFieldMap(c.StringSlice("map"))
+			if err != nil {
+				return err
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+
+			var r io.Reader = os.Stdin
+			if file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			records, err := readTaskRecords(r, format)
+			if err != nil {
+				return fmt.Errorf("could not read %s: %w", file, err)
+			}
+			if len(records) == 0 {
+				fmt.Println("No rows found.")
+				return nil
+			}
+			// A CSV header without the title column is a mapping mistake, not bad rows
+			if _, ok := records[0].Fields[fieldMapColumn(fieldMap, "title")]; !ok && format == "csv" {
+				return fmt.Errorf("no '%s' column found; map one with --map title=<column>", fieldMapColumn(fieldMap, "title"))
+			}
+
+			client := api.NewClient()
+			projects, err := client.ListProjects()
+			if err != nil {
+				return fmt.Errorf("could not fetch projects: %w", err)
+			}
+			defaultProject := c.String("project")
+			if defaultProject != "" {
+				if defaultProject, err = resolveProjectArg(client, defaultProject); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				defaultProject = cfg.ActiveProjectID
+			}
+
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			rows := make([]*taskRow, len(records))
+			invalid := 0
+			for i, rec := range records {
+				row := buildTaskRow(rec, fieldMap)
+				for _, tag := range c.StringSlice("tags") {
+					if !containsFold(row.Tags, tag) {
+						row.Tags = append(row.Tags, tag)
+					}
+				}
+				if row.Err == nil {
+					row.ProjectID = defaultProject
+					if row.Project != "" {
+						row.ProjectID, row.Err = matchProject(projects, row.Project)
+					} else if row.ProjectID == "" {
+						row.Err = fmt.Errorf("no project; set a project column, --project or an active project")
+					}
+				}
+				if row.Err == nil {
+					if row.Title, err = guardLabelled(scanner, "task title", row.Title); err == nil {
+						row.Description, err = guardLabelled(scanner, "task description", row.Description)
+					}
+					row.Err = err
+				}
+				if row.Err != nil {
+					invalid++
+				}
+				rows[i] = row
+			}
+
+			names := map[string]string{}
+			for _, p := range projects {
+				names[p.ID.String()] = p.Name
+			}
+			if c.Bool("dry-run") {
+				for _, row := range rows {
+					if row.Err != nil {
+						fmt.Printf("  ! line %d: %v\n", row.Line, row.Err)
+						continue
+					}
+					line := fmt.Sprintf("  + line %d: %s [%s, %s] → %s", row.Line, truncateString(row.Title, 60), row.Priority, row.Status, names[row.ProjectID])
+					if len(row.Tags) > 0 {
+						line += " #" + strings.Join(row.Tags, " #")
+					}
+					fmt.Println(line)
+				}
+				fmt.Printf("\nDry run: %d tasks would be created, %d rows invalid.\n", len(rows)-invalid, invalid)
+				if invalid > 0 && !c.Bool("skip-invalid") {
+					return fmt.Errorf("%d rows failed validation", invalid)
+				}
+				return nil
+			}
+			if invalid > 0 {
+				for _, row := range rows {
+					if row.Err != nil {
+						fmt.Printf("❌ line %d: %v\n", row.Line, row.Err)
+					}
+				}
+				if !c.Bool("skip-invalid") {
+					return fmt.Errorf("%d rows failed validation; nothing was imported (fix them or use --skip-invalid)", invalid)
+				}
+			}
+
+			var mu sync.Mutex
+			var failed, incomplete []*taskRow
+			created := 0
+			forEachLimited(concurrency, len(rows), func(i int) {
+				row := rows[i]
+				if row.Err != nil {
+					return
+				}
+				task, err := client.CreateTask(row.ProjectID, row.Title, row.Description, row.Priority, row.Tags...)
+				var statusErr error
+				if err == nil && row.Status != "TODO" {
+					if updated, err := client.UpdateTask(task.ID.String(), map[string]interface{}{"status": row.Status}); err != nil {
+						statusErr = err
+					} else {
+						task = updated
+					}
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					row.Err = err
+					failed = append(failed, row)
+					return
+				}
+				// The task exists either way; importing the row again would duplicate it
+				row.Task = task
+				created++
+				if statusErr != nil {
+					row.Err = statusErr
+					incomplete = append(incomplete, row)
+				}
+			})
+			sort.Slice(failed, func(i, j int) bool { return failed[i].Line < failed[j].Line })
+			for _, row := range failed {
+				fmt.Printf("❌ line %d: %s\n", row.Line, apierrors.ParseAPIError(row.Err))
+			}
+			sort.Slice(incomplete, func(i, j int) bool { return incomplete[i].Line < incomplete[j].Line })
+			for _, row := range incomplete {
+				fmt.Printf("⚠️  line %d: created task %s, but could not set its status to %s: %s\n",
+					row.Line, row.Task.ID.String()[:8], row.Status, apierrors.ParseAPIError(row.Err))
+			}
+
+			fmt.Printf("📥 Imported %d tasks", created)
+			if len(incomplete) > 0 {
+				fmt.Printf(" (%d without their status)", len(incomplete))
+			}
+			if invalid > 0 {
+				fmt.Printf(", skipped %d invalid rows", invalid)
+			}
+			fmt.Println(".")
+			if len(failed) > 0 {
+				return fmt.Errorf("%d tasks failed to import", len(failed))
+			}
+			return nil
+		},
+	}
+}
+
+// fieldMapColumn is the column a task field is read from
+func fieldMapColumn(fieldMap map[string]string, field string) string {
+	if c, ok := fieldMap[field]; ok {
+		return c
+	}
+	return field
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestTaskImportRows(t *testing.T) {
+	csvData := "\ufeffSummary,Prio,State,Labels\n" +
+		"Fix login,High,done,\"auth, bug\"\n" +
+		",,,\n" +
+		"Write docs,urgent,todo,\n" +
+		",L,,\n"
+	records, err := readTaskRecords(strings.NewReader(csvData), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %+v", records)
+	}
+	fieldMap, err := parseFieldMap([]string{"title=Summary,priority=Prio", "status=State,tags=Labels"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := buildTaskRow(records[0], fieldMap)
+	if row.Err != nil || row.Line != 2 || row.Title != "Fix login" || row.Priority != "H" || row.Status != "COMPLETED" || strings.Join(row.Tags, "|") != "auth|bug" {
+		t.Errorf("row = %+v", row)
+	}
+	if row := buildTaskRow(records[1], fieldMap); row.Err == nil || !strings.Contains(row.Err.Error(), "priority") || row.Line != 4 {
+		t.Errorf("bad priority row = %+v", row)
+	}
+	if row := buildTaskRow(records[2], fieldMap); row.Err == nil || !strings.Contains(row.Err.Error(), "title") {
+		t.Errorf("missing title row = %+v", row)
+	}
+
+	if _, err := parseFieldMap([]string{"owner=Assignee"}); err == nil {
+		t.Error("unknown field should fail")
+	}
+	if _, err := parseFieldMap([]string{"title"}); err == nil {
+		t.Error("mapping without column should fail")
+	}
+}
+
+func TestTaskExportRoundTrip(t *testing.T) {
+	done := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	project := uuid.New()
+	tasks := []models.Task{{
+		ID: uuid.New(), ProjectID: project, Title: "Ship \"v2\", finally", Status: "COMPLETED", Priority: "H",
+		Tags: []interface{}{"release", "q1"}, CreatedAt: done.Add(-time.Hour), CompletedAt: &done,
+	}}
+	names := map[string]string{project.String(): "orion"}
+
+	for _, format := range []string{"csv", "jsonl"} {
+		var b bytes.Buffer
+		if err := writeTasks(&b, format, defaultTaskColumns, tasks, names); err != nil {
+			t.Fatal(err)
+		}
+		records, err := readTaskRecords(&b, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("%s: records = %+v", format, records)
+		}
+		row := buildTaskRow(records[0], nil)
+		if row.Err != nil || row.Title != tasks[0].Title || row.Status != "COMPLETED" || row.Priority != "H" ||
+			row.Project != "orion" || strings.Join(row.Tags, ",") != "release,q1" {
+			t.Errorf("%s: row = %+v", format, row)
+		}
+		if got := records[0].Fields["completed_at"]; got != "2025-03-04T10:00:00Z" {
+			t.Errorf("%s: completed_at = %q", format, got)
+		}
+	}
+}
+
+func TestParseTaskValues(t *testing.T) {
+	for in, want := range map[string]string{"": "M", "high": "H", " l ": "L", "Medium": "M"} {
+		if got, err := models.ParsePriority(in); err != nil || got != want {
+			t.Errorf("ParsePriority(%q) = %q, %v", in, got, err)
+		}
+	}
+	for in, want := range map[string]string{"": "TODO", "in progress": "IN_PROGRESS", "Done": "COMPLETED", "in-review": "IN_REVIEW"} {
+		if got, err := models.ParseStatus(in); err != nil || got != want {
+			t.Errorf("ParseStatus(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := models.ParseStatus("someday"); err == nil {
+		t.Error("unknown status should fail")
+	}
+}
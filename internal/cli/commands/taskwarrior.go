@@ -0,0 +1,444 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	apierrors "github.com/terzigolu/josepshbrain-go/internal/errors"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+	"github.com/terzigolu/josepshbrain-go/internal/taskwarrior"
+	"github.com/urfave/cli/v2"
+)
+
+// twImport is a Taskwarrior task on its way into ramorie
+type twImport struct {
+	taskwarrior.Task
+	ProjectID string
+	Existing  string // ramorie task this task already is, if any
+	Created   *models.Task
+	Notes     int    // annotations created
+	Invalid   string // why the task cannot be imported
+	Err       error
+	Missing   string // what a created task lacks because Err stopped its import
+}
+
+// importTaskwarriorCmd imports the JSON of Taskwarrior's `task export`.
+func importTaskwarriorCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "taskwarrior",
+		Aliases:   []string{"tw"},
+		Usage:     "Import tasks from Taskwarrior `task export` JSON",
+		ArgsUsage: "<file|->",
+		Description: "Reads the output of `task export` (use - for stdin). Taskwarrior projects become\n" +
+			"projects (created when missing), and tags, priorities, annotations, due dates, status\n" +
+			"and dependencies are carried over. Deleted tasks and recurrence templates are skipped.\n" +
+			"Tasks already in ramorie (same UUID, or same title in the same project) are skipped,\n" +
+			"so the import can be re-run during a migration.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Import every task into this project instead of the Taskwarrior projects"},
+			&cli.BoolFlag{Name: "skip-completed", Usage: "Only import pending tasks"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of tasks imported in parallel", Value: 4},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Show what would be imported without changing anything"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return fmt.Errorf("taskwarrior export file is required (use - for stdin, e.g. task export | ramorie import taskwarrior -)")
+			}
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			dryRun := c.Bool("dry-run")
+
+			var r io.Reader = os.Stdin
+			if file := c.Args().First(); file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			parsed, err := taskwarrior.Parse(r)
+			if err != nil {
+				return fmt.Errorf("could not read taskwarrior export: %w", err)
+			}
+			var items []*twImport
+			skipped := 0
+			for _, t := range parsed {
+				if !t.Importable() || c.Bool("skip-completed") && t.Status == "completed" {
+					skipped++
+					continue
+				}
+				items = append(items, &twImport{Task: t})
+			}
+			if len(items) == 0 {
+				fmt.Println("No tasks to import.")
+				return nil
+			}
+
+			client := api.NewClient()
+			projects, err := client.ListProjects()
+			if err != nil {
+				return fmt.Errorf("could not fetch projects: %w", err)
+			}
+			existing, err := client.ListTasks("", "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+
+			// Projects: one flag for all, else by Taskwarrior project name
+			defaultProject := c.String("project")
+			if defaultProject != "" {
+				if defaultProject, err = resolveProjectArg(client, defaultProject); err != nil {
+					return err
+				}
+			} else if cfg, err := config.LoadConfig(); err == nil {
+				defaultProject = cfg.ActiveProjectID
+			}
+			byName := map[string]string{}
+			for _, p := range projects {
+				byName[strings.ToLower(p.Name)] = p.ID.String()
+			}
+			var newProjects []string
+			for _, it := range items {
+				switch {
+				case c.String("project") != "" || it.Project == "":
+					it.ProjectID = defaultProject
+					if it.ProjectID == "" {
+						it.Invalid = "no project; use --project or set an active project"
+					}
+				case byName[strings.ToLower(it.Project)] != "":
+					it.ProjectID = byName[strings.ToLower(it.Project)]
+				default:
+					if !containsFold(newProjects, it.Project) {
+						newProjects = append(newProjects, it.Project)
+					}
+				}
+			}
+			if !dryRun {
+				for _, name := range newProjects {
+					p, err := client.CreateProject(name, "Imported from Taskwarrior")
+					if err != nil {
+						fmt.Println(apierrors.ParseAPIError(err))
+						return fmt.Errorf("could not create project '%s': %w", name, err)
+					}
+					byName[strings.ToLower(name)] = p.ID.String()
+				}
+				for _, it := range items {
+					if it.ProjectID == "" && it.Invalid == "" {
+						it.ProjectID = byName[strings.ToLower(it.Project)]
+					}
+				}
+			}
+
+			// Skip tasks that were exported from ramorie or imported before
+			ids := map[string]bool{}
+			titles := map[string]string{}
+			for _, t := range existing {
+				ids[t.ID.String()] = true
+				titles[t.ProjectID.String()+"\x00"+strings.ToLower(t.Title)] = t.ID.String()
+			}
+			for _, it := range items {
+				if ids[strings.ToLower(it.UUID)] {
+					it.Existing = strings.ToLower(it.UUID)
+				} else if id, ok := titles[it.ProjectID+"\x00"+strings.ToLower(strings.TrimSpace(it.Description))]; ok && it.ProjectID != "" {
+					it.Existing = id
+				}
+			}
+
+			// Secret scan of what is about to be stored
+			scanner, err := loadScanner()
+			if err != nil {
+				return err
+			}
+			for _, it := range items {
+				if it.Invalid == "" && it.Existing == "" {
+					if err := guardTaskwarrior(scanner, it); err != nil {
+						it.Invalid = err.Error()
+					}
+				}
+			}
+
+			if dryRun {
+				printTaskwarriorPlan(items, newProjects, skipped)
+				return nil
+			}
+
+			forEachLimited(concurrency, len(items), func(i int) {
+				it := items[i]
+				if it.Invalid != "" || it.Existing != "" {
+					return
+				}
+				task, err := client.CreateTask(it.ProjectID, strings.TrimSpace(it.Description), "", it.RamoriePriority(), it.Tags...)
+				if err != nil {
+					it.Err = err
+					return
+				}
+				it.Created = task
+				update := map[string]interface{}{}
+				if status := it.RamorieStatus(); status != "TODO" {
+					update["status"] = status
+				}
+				if it.Due != nil {
+					update["due_date"] = it.Due.UTC().Format(time.RFC3339)
+				}
+				if len(update) > 0 {
+					if _, err := client.UpdateTask(task.ID.String(), update); err != nil {
+						var missing []string
+						if _, ok := update["status"]; ok {
+							missing = append(missing, "status")
+						}
+						if _, ok := update["due_date"]; ok {
+							missing = append(missing, "due date")
+						}
+						if len(it.Annotations) > 0 {
+							missing = append(missing, "annotations")
+						}
+						it.Err, it.Missing = err, strings.Join(missing, ", ")
+						return
+					}
+				}
+				for _, a := range it.Annotations {
+					if strings.TrimSpace(a.Description) == "" {
+						continue
+					}
+					if _, err := client.CreateAnnotation(task.ID.String(), a.Description); err != nil {
+						it.Err, it.Missing = err, "some annotations"
+						return
+					}
+					it.Notes++
+				}
+			})
+
+			// Dependencies once every task has its ramorie ID
+			resolved := map[string]string{}
+			for _, it := range items {
+				switch {
+				case it.Created != nil:
+					resolved[strings.ToLower(it.UUID)] = it.Created.ID.String()
+				case it.Existing != "":
+					resolved[strings.ToLower(it.UUID)] = it.Existing
+				}
+			}
+			type dependency struct{ task, blocking string }
+			var deps []dependency
+			for _, it := range items {
+				if it.Created == nil {
+					continue
+				}
+				for _, uuid := range it.Depends {
+					if blocking, ok := resolved[strings.ToLower(uuid)]; ok {
+						deps = append(deps, dependency{it.Created.ID.String(), blocking})
+					} else if ids[strings.ToLower(uuid)] {
+						deps = append(deps, dependency{it.Created.ID.String(), strings.ToLower(uuid)})
+					}
+				}
+			}
+			var mu sync.Mutex
+			var depErr error
+			depFailed := 0
+			forEachLimited(concurrency, len(deps), func(i int) {
+				if err := client.AddTaskDependency(deps[i].task, deps[i].blocking); err != nil {
+					mu.Lock()
+					depFailed++
+					depErr = err
+					mu.Unlock()
+				}
+			})
+
+			imported, notes, failed, incomplete := 0, 0, 0, 0
+			for _, it := range items {
+				if it.Invalid != "" {
+					failed++
+					fmt.Printf("❌ %s: %s\n", truncateString(it.Description, 50), it.Invalid)
+					continue
+				}
+				// A re-run skips the created task, so what it lacks has to be added by hand
+				if it.Created != nil && it.Err != nil {
+					incomplete++
+					fmt.Printf("⚠️  %s: created as %s without its %s: %s\n", truncateString(it.Description, 50), it.Created.ID.String()[:8], it.Missing, apierrors.ParseAPIError(it.Err))
+				} else if it.Err != nil {
+					failed++
+					fmt.Printf("❌ %s: %s\n", truncateString(it.Description, 50), apierrors.ParseAPIError(it.Err))
+					continue
+				}
+				if it.Created != nil {
+					imported++
+					notes += it.Notes
+				}
+			}
+			fmt.Printf("📥 Imported %d tasks with %d annotations and %d dependencies", imported, notes, len(deps)-depFailed)
+			if len(newProjects) > 0 {
+				fmt.Printf(", created %d projects", len(newProjects))
+			}
+			fmt.Printf("; skipped %d existing, %d deleted or recurring.\n", len(items)-imported-failed, skipped)
+			if depFailed > 0 {
+				fmt.Printf("⚠️  %d dependencies could not be created: %s\n", depFailed, apierrors.ParseAPIError(depErr))
+			}
+			if incomplete > 0 {
+				fmt.Printf("⚠️  %d tasks were created incompletely; re-running the import skips them.\n", incomplete)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d tasks failed to import", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// guardTaskwarrior applies the secret scan policy to the title and annotations of a task
+func guardTaskwarrior(scanner *secrets.Scanner, it *twImport) error {
+	title, err := guardLabelled(scanner, "task title", it.Description)
+	if err != nil {
+		return err
+	}
+	notes := make([]string, len(it.Annotations))
+	for i, a := range it.Annotations {
+		if notes[i], err = guardLabelled(scanner, "annotation", a.Description); err != nil {
+			return err
+		}
+	}
+	it.Description = title
+	for i := range it.Annotations {
+		it.Annotations[i].Description = notes[i]
+	}
+	return nil
+}
+
+func printTaskwarriorPlan(items []*twImport, newProjects []string, skipped int) {
+	for _, name := range newProjects {
+		fmt.Printf("  + project %s\n", name)
+	}
+	create := 0
+	for _, it := range items {
+		switch {
+		case it.Invalid != "":
+			fmt.Printf("  ! %s: %s\n", truncateString(it.Description, 60), it.Invalid)
+		case it.Existing != "":
+			fmt.Printf("  = %s (exists as %s)\n", truncateString(it.Description, 60), it.Existing[:8])
+		default:
+			create++
+			line := fmt.Sprintf("  + %s [%s, %s]", truncateString(it.Description, 60), it.RamoriePriority(), it.RamorieStatus())
+			if it.Project != "" {
+				line += " project:" + it.Project
+			}
+			if it.Due != nil {
+				line += " due:" + it.Due.Format("2006-01-02")
+			}
+			if len(it.Tags) > 0 {
+				line += " +" + strings.Join(it.Tags, " +")
+			}
+			if len(it.Annotations) > 0 {
+				line += fmt.Sprintf(" (%d notes)", len(it.Annotations))
+			}
+			if len(it.Depends) > 0 {
+				line += fmt.Sprintf(" (%d deps)", len(it.Depends))
+			}
+			fmt.Println(line)
+		}
+	}
+	fmt.Printf("\nDry run: %d tasks would be imported, %d skipped as existing, invalid, deleted or recurring.\n", create, len(items)-create+skipped)
+}
+
+// exportTaskwarriorCmd writes tasks as Taskwarrior import JSON.
+func exportTaskwarriorCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "taskwarrior",
+		Aliases:   []string{"tw"},
+		Usage:     "Export tasks as Taskwarrior JSON (for `task import`)",
+		ArgsUsage: "[file]",
+		Description: "Writes to stdout unless a file is given. Task IDs become Taskwarrior UUIDs, so\n" +
+			"tasks moved back with `ramorie import taskwarrior` are recognised and skipped.\n" +
+			"Task descriptions become the first annotation.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Aliases: []string{"p"}, Usage: "Only tasks of this project (name or ID); default all projects"},
+			&cli.BoolFlag{Name: "skip-completed", Usage: "Only export open tasks"},
+			&cli.IntFlag{Name: "concurrency", Usage: "Number of parallel API requests", Value: 4},
+		},
+		Action: func(c *cli.Context) error {
+			concurrency := c.Int("concurrency")
+			if concurrency < 1 {
+				concurrency = 1
+			}
+			client := api.NewClient()
+			projectID := c.String("project")
+			var err error
+			if projectID != "" {
+				if projectID, err = resolveProjectArg(client, projectID); err != nil {
+					return err
+				}
+			}
+			projects, err := client.ListProjects()
+			if err != nil {
+				return fmt.Errorf("could not fetch projects: %w", err)
+			}
+			names := map[string]string{}
+			for _, p := range projects {
+				names[p.ID.String()] = p.Name
+			}
+			listed, err := client.ListTasks(projectID, "")
+			if err != nil {
+				fmt.Println(apierrors.ParseAPIError(err))
+				return err
+			}
+			var tasks []models.Task
+			for _, t := range listed {
+				if !c.Bool("skip-completed") || t.Status != "COMPLETED" {
+					tasks = append(tasks, t)
+				}
+			}
+			sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+			// The full task carries annotations and dependencies
+			var mu sync.Mutex
+			var firstErr error
+			forEachLimited(concurrency, len(tasks), func(i int) {
+				full, err := client.GetTask(tasks[i].ID.String())
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+				tasks[i] = *full
+			})
+			if firstErr != nil {
+				fmt.Println(apierrors.ParseAPIError(firstErr))
+				return firstErr
+			}
+
+			out := make([]taskwarrior.Task, 0, len(tasks))
+			for _, t := range tasks {
+				out = append(out, taskwarrior.FromRamorie(t, names[t.ProjectID.String()], getTagsAsStrings(t.Tags)))
+			}
+			data, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return err
+			}
+			data = append(data, '\n')
+			file := c.Args().First()
+			if file == "" || file == "-" {
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(file, data, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("📤 Exported %d tasks to %s (load with: task import %s)\n", len(out), file, file)
+			return nil
+		},
+	}
+}
@@ -1,8 +1,84 @@
 package commands
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// parseSince parses a relative or absolute point in time.
+// Accepts "today", "yesterday", Go durations ("36h"), day counts ("7d"),
+// dates ("2006-01-02") and RFC3339 timestamps.
+func parseSince(s string) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "week", "last-week":
+		return midnight.AddDate(0, 0, -7), nil
+	}
+
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use e.g. yesterday, 24h, 7d or 2006-01-02)", s)
+}
+
+// parseUntil parses a point in the future, the counterpart of parseSince.
+// Accepts "tomorrow", "next-week", Go durations ("36h"), day, week, month
+// and year counts ("30d", "2w", "6mo", "1y"), dates and RFC3339 timestamps.
+func parseUntil(s string) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	case "next-week":
+		return midnight.AddDate(0, 0, 7), nil
+	}
+
+	for _, unit := range []struct {
+		suffix              string
+		years, months, days int
+	}{{"mo", 0, 1, 0}, {"d", 0, 0, 1}, {"w", 0, 0, 7}, {"y", 1, 0, 0}} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, unit.suffix)); err == nil && strings.HasSuffix(s, unit.suffix) {
+			return now.AddDate(n*unit.years, n*unit.months, n*unit.days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use e.g. tomorrow, 30d, 6mo, 1y or 2006-01-02)", s)
+}
@@ -17,15 +17,28 @@ const (
 type Config struct {
 	APIKey          string `json:"api_key"`
 	ActiveProjectID string `json:"active_project_id"`
+
+	// Secret and PII scanning policies (block, redact or warn); empty means the default
+	ScanPolicy string `json:"scan_policy,omitempty"`
+	PIIPolicy  string `json:"pii_policy,omitempty"`
+}
+
+// GetConfigDir returns the ramorie data directory (~/.ramorie)
+func GetConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configDirNew), nil
 }
 
 // GetConfigPath returns the path to the new config file (~/.ramorie/config.json)
 func GetConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, configDirNew, configFileName), nil
+	return filepath.Join(dir, configFileName), nil
 }
 
 // getLegacyConfigPath returns the path to the legacy config file (~/.jbrain/config.json)
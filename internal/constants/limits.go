@@ -7,6 +7,10 @@ const (
 	// MaxMemoryChars is the maximum characters allowed for a single memory (~750K tokens)
 	MaxMemoryChars = 3_000_000
 
+	// MaxAttachmentBytes is the maximum size of an attachment. It mirrors
+	// MaxMemoryChars so a text attachment always fits the recall index.
+	MaxAttachmentBytes = MaxMemoryChars
+
 	// MaxAIInputChars is the maximum characters for AI operations (~800K tokens)
 	MaxAIInputChars = 3_200_000
 
@@ -27,6 +31,11 @@ func IsWithinMemoryLimit(content string) bool {
 	return len(content) <= MaxMemoryChars
 }
 
+// IsWithinAttachmentLimit checks if a file is small enough to attach
+func IsWithinAttachmentLimit(size int64) bool {
+	return size <= MaxAttachmentBytes
+}
+
 // GetContentStats returns content statistics
 func GetContentStats(content string) (chars int, tokens int, usagePercent float64) {
 	chars = len(content)
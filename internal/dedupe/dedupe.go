@@ -0,0 +1,230 @@
+// Package dedupe finds duplicate and near-duplicate texts locally, using
+// MinHash signatures over character shingles to find candidate pairs and
+// exact shingle Jaccard similarity to confirm them.
+package dedupe
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	shingleSize = 4
+	numHashes   = 120
+
+	// recall is the least chance the bands give a pair at the threshold of
+	// becoming a candidate
+	recall = 0.99
+
+	// MinThreshold is the lowest threshold the signatures support: below
+	// it, even bands of one row miss too many pairs
+	MinThreshold = 0.05
+)
+
+// Cluster is a group of near-duplicate texts, by index into the input
+type Cluster struct {
+	Members []int
+
+	// Similarity is the lowest similarity among the pairs that joined the cluster
+	Similarity float64
+}
+
+// Normalize lower-cases text and reduces punctuation and whitespace to single spaces
+func Normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Similarity returns the Jaccard similarity (0-1) of the shingles of two texts
+func Similarity(a, b string) float64 {
+	return jaccard(shingles(a), shingles(b))
+}
+
+// Closest returns the index of the text most similar to query and its
+// similarity, or -1 when texts is empty.
+func Closest(query string, texts []string) (int, float64) {
+	q := shingles(query)
+	best, bestSim := -1, 0.0
+	for i, t := range texts {
+		if sim := jaccard(q, shingles(t)); best < 0 || sim > bestSim {
+			best, bestSim = i, sim
+		}
+	}
+	return best, bestSim
+}
+
+// Clusters groups texts whose similarity is at least threshold. Only groups
+// of two or more are returned, ordered by their first member.
+func Clusters(texts []string, threshold float64) []Cluster {
+	rows := bandRows(threshold)
+	bands := numHashes / rows
+	sets := make([]map[uint64]struct{}, len(texts))
+	buckets := map[[2]uint64][]int{}
+	for i, t := range texts {
+		sets[i] = shingles(t)
+		sig := signature(sets[i])
+		for b := 0; b < bands; b++ {
+			h := fnv.New64a()
+			for _, v := range sig[b*rows : (b+1)*rows] {
+				var buf [8]byte
+				for k := range buf {
+					buf[k] = byte(v >> (8 * k))
+				}
+				h.Write(buf[:])
+			}
+			key := [2]uint64{uint64(b), h.Sum64()}
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	parent := make([]int, len(texts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	minSim := map[int]float64{}
+	checked := map[[2]int]bool{}
+	for _, members := range buckets {
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				pair := [2]int{members[x], members[y]}
+				if checked[pair] {
+					continue
+				}
+				checked[pair] = true
+				sim := jaccard(sets[pair[0]], sets[pair[1]])
+				if sim < threshold {
+					continue
+				}
+				ra, rb := find(pair[0]), find(pair[1])
+				low := sim
+				for _, r := range []int{ra, rb} {
+					if s, ok := minSim[r]; ok && s < low {
+						low = s
+					}
+				}
+				if ra != rb {
+					if rb < ra {
+						ra, rb = rb, ra
+					}
+					parent[rb] = ra
+					delete(minSim, rb)
+				}
+				minSim[ra] = low
+			}
+		}
+	}
+
+	groups := map[int][]int{}
+	for i := range texts {
+		groups[find(i)] = append(groups[find(i)], i)
+	}
+	var clusters []Cluster
+	for root, members := range groups {
+		if len(members) > 1 {
+			clusters = append(clusters, Cluster{Members: members, Similarity: minSim[root]})
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Members[0] < clusters[j].Members[0] })
+	return clusters
+}
+
+// bandRows picks the widest bands that still make a pair at the threshold a
+// candidate with at least the recall chance; wider bands mean fewer
+// candidates to confirm. With 3 rows, 40 bands catch pairs at 0.5 >99% of
+// the time.
+func bandRows(threshold float64) int {
+	for rows := numHashes; rows > 1; rows-- {
+		if numHashes%rows != 0 {
+			continue
+		}
+		bands := float64(numHashes / rows)
+		if 1-math.Pow(1-math.Pow(threshold, float64(rows)), bands) >= recall {
+			return rows
+		}
+	}
+	return 1
+}
+
+// shingles hashes every run of shingleSize characters of the normalized text
+func shingles(text string) map[uint64]struct{} {
+	runes := []rune(Normalize(text))
+	set := map[uint64]struct{}{}
+	if len(runes) == 0 {
+		return set
+	}
+	n := shingleSize
+	if len(runes) < n {
+		n = len(runes)
+	}
+	for i := 0; i+n <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+n])))
+		set[h.Sum64()] = struct{}{}
+	}
+	return set
+}
+
+func jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// signature is the MinHash of a shingle set: the minimum of each of
+// numHashes independent hash functions over its members.
+func signature(set map[uint64]struct{}) [numHashes]uint64 {
+	var sig [numHashes]uint64
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for x := range set {
+		for i := range sig {
+			if h := mix(x ^ seeds[i]); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+var seeds = func() [numHashes]uint64 {
+	var s [numHashes]uint64
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range s {
+		state += 0x9e3779b97f4a7c15
+		s[i] = mix(state)
+	}
+	return s
+}()
+
+// mix is the splitmix64 finalizer
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
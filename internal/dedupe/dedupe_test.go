@@ -0,0 +1,58 @@
+package dedupe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  Traefik: exposedByDefault=FALSE!\n"); got != "traefik exposedbydefault false" {
+		t.Errorf("Normalize = %q", got)
+	}
+}
+
+func TestClusters(t *testing.T) {
+	texts := []string{
+		"Traefik needs the docker provider enabled and exposedByDefault=false.",
+		"Use docker buildx for multi-arch images",
+		"traefik needs docker provider enabled and exposedByDefault = false",
+		"Traefik requires the docker provider to be enabled, with exposedByDefault set to false.",
+		"use Docker buildx for multi-arch images!",
+		"Traefik dashboard is on port 8080 in staging",
+	}
+
+	clusters := Clusters(texts, 0.45)
+	var members [][]int
+	for _, c := range clusters {
+		members = append(members, c.Members)
+		if c.Similarity < 0.45 || c.Similarity > 1 {
+			t.Errorf("cluster %v similarity = %.2f", c.Members, c.Similarity)
+		}
+	}
+	if want := [][]int{{0, 2, 3}, {1, 4}}; !reflect.DeepEqual(members, want) {
+		t.Errorf("Clusters = %v, want %v", members, want)
+	}
+
+	if got := Clusters(texts, 0.99); len(got) != 1 || !reflect.DeepEqual(got[0].Members, []int{1, 4}) {
+		t.Errorf("exact clusters = %v, want only the buildx pair", got)
+	}
+}
+
+func TestBandRows(t *testing.T) {
+	tests := map[float64]int{0.05: 1, 0.3: 2, 0.5: 3, 0.8: 6, 1: 120}
+	for threshold, want := range tests {
+		if got := bandRows(threshold); got != want {
+			t.Errorf("bandRows(%g) = %d, want %d", threshold, got, want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	i, sim := Closest("docker buildx for multi arch images", []string{"Traefik dashboard on 8080", "Use docker buildx for multi-arch images"})
+	if i != 1 || sim < 0.8 {
+		t.Errorf("Closest = %d, %.2f", i, sim)
+	}
+	if i, _ := Closest("anything", nil); i != -1 {
+		t.Errorf("Closest on empty = %d", i)
+	}
+}
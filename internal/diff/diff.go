@@ -0,0 +1,196 @@
+// Package diff renders line-based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// opKind is the kind of a single line edit
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a unified diff from a to b with the given number of context
+// lines, labelled with fromName/toName. It returns "" when the texts are equal.
+func Unified(a, b, fromName, toName string, context int) string {
+	if a == b {
+		return ""
+	}
+	ops := lineOps(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	sb.WriteString("--- " + fromName + "\n")
+	sb.WriteString("+++ " + toName + "\n")
+
+	// Walk the edit script, emitting hunks around changed lines
+	i := 0
+	aLine, bLine := 1, 1
+	for i < len(ops) {
+		if ops[i].kind == opEqual {
+			i++
+			aLine++
+			bLine++
+			continue
+		}
+
+		// Start the hunk up to `context` equal lines before the change
+		start := i
+		for start > 0 && i-start < context && ops[start-1].kind == opEqual {
+			start--
+		}
+		hunkA := aLine - (i - start)
+		hunkB := bLine - (i - start)
+
+		// Extend until more than 2*context equal lines separate changes
+		end := i
+		equalRun := 0
+		for end < len(ops) {
+			if ops[end].kind == opEqual {
+				if equalRun == 2*context {
+					break
+				}
+				equalRun++
+			} else {
+				equalRun = 0
+			}
+			end++
+		}
+		// Keep only `context` trailing equal lines
+		end -= max(equalRun-context, 0)
+
+		var body strings.Builder
+		countA, countB := 0, 0
+		for _, o := range ops[start:end] {
+			switch o.kind {
+			case opEqual:
+				body.WriteString(" " + o.line + "\n")
+				countA++
+				countB++
+			case opDelete:
+				body.WriteString("-" + o.line + "\n")
+				countA++
+			case opInsert:
+				body.WriteString("+" + o.line + "\n")
+				countB++
+			}
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(hunkA, countA), hunkRange(hunkB, countB)))
+		sb.WriteString(body.String())
+
+		// Advance line counters past the hunk
+		for _, o := range ops[i:end] {
+			if o.kind != opInsert {
+				aLine++
+			}
+			if o.kind != opDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range points at the line before the hunk
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineOps computes a shortest edit script with Myers' O(ND) algorithm
+func lineOps(a, b []string) []op {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD
+	v := make([]int, 2*maxD+2)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset, d, k)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack rebuilds the edit script from the saved V arrays
+func backtrack(trace [][]int, a, b []string, offset, d, k int) []op {
+	x, y := len(a), len(b)
+	var ops []op
+	for ; d > 0; d-- {
+		v := trace[d]
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{opEqual, a[x]})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, op{opInsert, b[y]})
+		} else {
+			x--
+			ops = append(ops, op{opDelete, a[x]})
+		}
+		k = prevK
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, op{opEqual, a[x]})
+	}
+
+	// Reverse into forward order
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
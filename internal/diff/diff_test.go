@@ -0,0 +1,44 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", 3, ""},
+		{
+			"change in middle",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			3,
+			"--- v1\n+++ v2\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			"insert into empty",
+			"",
+			"hello\n",
+			3,
+			"--- v1\n+++ v2\n@@ -0,0 +1 @@\n+hello\n",
+		},
+		{
+			"two hunks",
+			"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+			"A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n",
+			1,
+			"--- v1\n+++ v2\n@@ -1,2 +1,2 @@\n-a\n+A\n b\n@@ -9,2 +9,2 @@\n i\n-j\n+J\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(tt.a, tt.b, "v1", "v2", tt.context)
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package digest assembles standups and weekly digests from tasks,
+// memories and decisions: what was completed, started and is blocked, and
+// what was learned and decided, grouped by project. It needs no AI; the
+// report renders as Markdown or Slack mrkdwn.
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Formats are the supported output formats
+var Formats = []string{"markdown", "slack"}
+
+// Input is everything a report is built from
+type Input struct {
+	Projects  []models.Project
+	Tasks     []models.Task
+	Memories  []models.Memory
+	Decisions []api.Decision
+}
+
+// Entry is one line of a report
+type Entry struct {
+	ID   string // short ID
+	Text string
+	Note string // e.g. what a blocked task waits on
+}
+
+// Section is the activity of one project
+type Section struct {
+	Project   string
+	ProjectID string // "" for decisions without a project
+	Completed []Entry
+	Started   []Entry
+	Blocked   []Entry
+	Memories  []Entry
+	Decisions []Entry
+}
+
+// Report is a standup or digest covering Since to Until
+type Report struct {
+	Title    string
+	Since    time.Time
+	Until    time.Time
+	Sections []Section
+}
+
+// noProject heads decisions that belong to no project
+const noProject = "General"
+
+// Build collects the activity between since and until. Blockers are
+// reported whenever they are still open, not only if they are new.
+func Build(title string, in Input, since, until time.Time) Report {
+	names := map[string]string{}
+	for _, p := range in.Projects {
+		names[p.ID.String()] = p.Name
+	}
+	tasks := map[string]models.Task{}
+	for _, t := range in.Tasks {
+		tasks[t.ID.String()] = t
+	}
+	// Keyed by project ID, so projects sharing a name stay apart
+	sections := map[string]*Section{}
+	section := func(projectID string) *Section {
+		if sections[projectID] == nil {
+			name := names[projectID]
+			if name == "" {
+				name = noProject
+				if projectID != "" {
+					name = projectID[:8]
+				}
+			}
+			sections[projectID] = &Section{Project: name, ProjectID: projectID}
+		}
+		return sections[projectID]
+	}
+	within := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	for _, t := range in.Tasks {
+		s := section(t.ProjectID.String())
+		e := Entry{ID: t.ID.String()[:8], Text: t.Title}
+		switch {
+		case completedAt(t) != nil:
+			if within(*completedAt(t)) {
+				s.Completed = append(s.Completed, e)
+			}
+			continue
+		case t.StartedAt != nil && within(*t.StartedAt),
+			t.StartedAt == nil && t.Status == "IN_PROGRESS" && within(t.UpdatedAt):
+			s.Started = append(s.Started, e)
+		}
+		if note, ok := blocked(t, tasks); ok {
+			e.Note = note
+			s.Blocked = append(s.Blocked, e)
+		}
+	}
+	for _, m := range in.Memories {
+		if within(m.CreatedAt) && m.ArchivedAt == nil {
+			text := models.MemoryTitle(m.Content)
+			if r := []rune(text); len(r) > 100 {
+				text = string(r[:97]) + "..."
+			}
+			if m.Type != "" {
+				text = "[" + m.Type + "] " + text
+			}
+			s := section(m.ProjectID.String())
+			s.Memories = append(s.Memories, Entry{ID: m.ID.String()[:8], Text: text})
+		}
+	}
+	for _, d := range in.Decisions {
+		if !within(d.CreatedAt) {
+			continue
+		}
+		projectID := ""
+		if d.ProjectID != nil {
+			projectID = *d.ProjectID
+		}
+		text := d.Title
+		if d.ADRNumber != "" {
+			text = d.ADRNumber + " " + text
+		}
+		s := section(projectID)
+		s.Decisions = append(s.Decisions, Entry{ID: d.ID[:8], Text: text, Note: strings.ToLower(d.Status)})
+	}
+
+	r := Report{Title: title, Since: since, Until: until}
+	for _, s := range sections {
+		if len(s.Completed)+len(s.Started)+len(s.Blocked)+len(s.Memories)+len(s.Decisions) > 0 {
+			r.Sections = append(r.Sections, *s)
+		}
+	}
+	sort.Slice(r.Sections, func(i, j int) bool {
+		// Decisions without a project come last
+		a, b := r.Sections[i].Project, r.Sections[j].Project
+		if (r.Sections[i].ProjectID == "") != (r.Sections[j].ProjectID == "") {
+			return r.Sections[j].ProjectID == ""
+		}
+		if !strings.EqualFold(a, b) {
+			return strings.ToLower(a) < strings.ToLower(b)
+		}
+		return r.Sections[i].ProjectID < r.Sections[j].ProjectID
+	})
+	return r
+}
+
+// completedAt is when a task was completed, or nil if it is not. Backends
+// without completion times fall back to the last update.
+func completedAt(t models.Task) *time.Time {
+	if t.CompletedAt != nil {
+		return t.CompletedAt
+	}
+	if t.Status == "COMPLETED" {
+		return &t.UpdatedAt
+	}
+	return nil
+}
+
+// blocked reports whether an open task is tagged blocked or waits on an
+// unfinished dependency, and what it waits on
+func blocked(t models.Task, tasks map[string]models.Task) (string, bool) {
+	var waits []string
+	for _, dep := range t.BlockingTasks {
+		blocker, ok := tasks[dep.BlockingTaskID.String()]
+		if !ok && dep.BlockingTask != nil {
+			blocker, ok = *dep.BlockingTask, true
+		}
+		if !ok {
+			waits = append(waits, dep.BlockingTaskID.String()[:8])
+		} else if completedAt(blocker) == nil {
+			waits = append(waits, blocker.Title+" ("+blocker.ID.String()[:8]+")")
+		}
+	}
+	if len(waits) > 0 {
+		return "waiting on " + strings.Join(waits, ", "), true
+	}
+	for _, tag := range models.TagNames(t.Tags) {
+		if strings.EqualFold(tag, "blocked") {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// Counts totals the entries of each kind across all sections
+func (r Report) Counts() (completed, started, blockers, memories, decisions int) {
+	for _, s := range r.Sections {
+		completed += len(s.Completed)
+		started += len(s.Started)
+		blockers += len(s.Blocked)
+		memories += len(s.Memories)
+		decisions += len(s.Decisions)
+	}
+	return
+}
+
+// Render formats the report as markdown or slack
+func (r Report) Render(format string) (string, error) {
+	var st style
+	switch strings.ToLower(format) {
+	case "", "markdown", "md":
+		st = style{heading: "# %s\n", project: "## %s\n", kind: "**%s**\n", bullet: "- ", em: "_%s_"}
+	case "slack":
+		st = style{heading: "*%s*\n", project: "*%s*\n", kind: "_%s_\n", bullet: "• ", em: "_%s_"}
+	default:
+		return "", fmt.Errorf("unknown format %q (use %s)", format, strings.Join(Formats, " or "))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, st.heading, r.Title)
+	fmt.Fprintf(&b, st.em+"\n", period(r.Since, r.Until))
+	if len(r.Sections) == 0 {
+		b.WriteString("\nNothing to report.\n")
+		return b.String(), nil
+	}
+	completed, started, blockers, memories, decisions := r.Counts()
+	fmt.Fprintf(&b, "\n%d completed · %d started · %d blocked · %d memories · %d decisions\n",
+		completed, started, blockers, memories, decisions)
+
+	for _, s := range r.Sections {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, st.project, s.Project)
+		for _, group := range []struct {
+			title   string
+			entries []Entry
+		}{
+			{"Completed", s.Completed},
+			{"Started", s.Started},
+			{"Blocked", s.Blocked},
+			{"New memories", s.Memories},
+			{"Decisions", s.Decisions},
+		} {
+			if len(group.entries) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, st.kind, group.title)
+			for _, e := range group.entries {
+				line := st.bullet + e.Text + " `" + e.ID + "`"
+				if e.Note != "" {
+					line += " — " + e.Note
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+type style struct {
+	heading, project, kind, bullet, em string
+}
+
+func period(since, until time.Time) string {
+	const layout = "Mon 2 Jan 15:04"
+	if until.Sub(since) >= 48*time.Hour {
+		return since.Format("Mon 2 Jan") + " – " + until.Format("Mon 2 Jan 2006")
+	}
+	return "Since " + since.Format(layout)
+}
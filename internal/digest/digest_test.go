@@ -0,0 +1,119 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestBuild(t *testing.T) {
+	since := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	inside, before := since.Add(2*time.Hour), since.Add(-2*time.Hour)
+
+	orion := models.Project{ID: uuid.New(), Name: "orion"}
+	lyra := models.Project{ID: uuid.New(), Name: "lyra"}
+	api1 := models.Task{ID: uuid.New(), ProjectID: orion.ID, Title: "Ship API", Status: "IN_PROGRESS", StartedAt: &before}
+	tasks := []models.Task{
+		{ID: uuid.New(), ProjectID: orion.ID, Title: "Fix login", Status: "COMPLETED", CompletedAt: &inside},
+		{ID: uuid.New(), ProjectID: orion.ID, Title: "Old work", Status: "COMPLETED", CompletedAt: &before},
+		{ID: uuid.New(), ProjectID: lyra.ID, Title: "Design onboarding", Status: "IN_PROGRESS", StartedAt: &inside},
+		api1,
+		{ID: uuid.New(), ProjectID: orion.ID, Title: "Write docs", Status: "TODO",
+			BlockingTasks: []models.Dependency{{BlockingTaskID: api1.ID}}},
+		{ID: uuid.New(), ProjectID: lyra.ID, Title: "Waiting on vendor", Status: "TODO", Tags: []interface{}{"Blocked"}},
+	}
+	memories := []models.Memory{
+		{ID: uuid.New(), ProjectID: orion.ID, Content: "Tokens expire after 1h\nmore detail", Type: "gotcha", CreatedAt: inside},
+		{ID: uuid.New(), ProjectID: orion.ID, Content: "old", CreatedAt: before},
+	}
+	decisions := []api.Decision{{ID: "dddddddd-1", ADRNumber: "ADR-007", Title: "Use Postgres", Status: "ACCEPTED", CreatedAt: inside}}
+
+	r := Build("Standup", Input{Projects: []models.Project{orion, lyra}, Tasks: tasks, Memories: memories, Decisions: decisions}, since, until)
+
+	var names []string
+	for _, s := range r.Sections {
+		names = append(names, s.Project)
+	}
+	if strings.Join(names, ",") != "lyra,orion,General" {
+		t.Fatalf("sections = %v", names)
+	}
+	lyraS, orionS, general := r.Sections[0], r.Sections[1], r.Sections[2]
+	if len(orionS.Completed) != 1 || orionS.Completed[0].Text != "Fix login" {
+		t.Errorf("orion completed = %+v", orionS.Completed)
+	}
+	if len(lyraS.Started) != 1 || len(orionS.Started) != 0 {
+		t.Errorf("started: lyra %+v, orion %+v", lyraS.Started, orionS.Started)
+	}
+	if len(orionS.Blocked) != 1 || !strings.Contains(orionS.Blocked[0].Note, "waiting on Ship API") {
+		t.Errorf("orion blocked = %+v", orionS.Blocked)
+	}
+	if len(lyraS.Blocked) != 1 || lyraS.Blocked[0].Text != "Waiting on vendor" {
+		t.Errorf("lyra blocked = %+v", lyraS.Blocked)
+	}
+	if len(orionS.Memories) != 1 || orionS.Memories[0].Text != "[gotcha] Tokens expire after 1h" {
+		t.Errorf("orion memories = %+v", orionS.Memories)
+	}
+	if len(general.Decisions) != 1 || general.Decisions[0].Text != "ADR-007 Use Postgres" {
+		t.Errorf("decisions = %+v", general.Decisions)
+	}
+}
+
+func TestBuildSameNamedProjects(t *testing.T) {
+	since := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	inside := since.Add(2 * time.Hour)
+
+	work := models.Project{ID: uuid.MustParse("0e000000-0000-4000-8000-000000000001"), Name: "notes"}
+	home := models.Project{ID: uuid.MustParse("0e000000-0000-4000-8000-000000000002"), Name: "notes"}
+	memories := []models.Memory{
+		{ID: uuid.New(), ProjectID: work.ID, Content: "## Deploys freeze on Fridays\nmore", CreatedAt: inside},
+		{ID: uuid.New(), ProjectID: home.ID, Content: "Boiler service is due", CreatedAt: inside},
+	}
+
+	r := Build("Standup", Input{Projects: []models.Project{home, work}, Memories: memories}, since, since.Add(24*time.Hour))
+
+	if len(r.Sections) != 2 || r.Sections[0].ProjectID != work.ID.String() {
+		t.Fatalf("sections = %+v, want one per project", r.Sections)
+	}
+	if got := r.Sections[0].Memories[0].Text; got != "Deploys freeze on Fridays" {
+		t.Errorf("memory text = %q", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := Report{
+		Title: "Standup",
+		Since: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC),
+		Sections: []Section{{
+			Project:   "orion",
+			Completed: []Entry{{ID: "aaaaaaa1", Text: "Fix login"}},
+			Blocked:   []Entry{{ID: "aaaaaaa2", Text: "Write docs", Note: "waiting on Ship API (aaaaaaa3)"}},
+		}},
+	}
+	md, err := r.Render("markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Standup\n", "_Since Mon 3 Mar 00:00_", "## orion\n", "**Completed**\n- Fix login `aaaaaaa1`\n", "- Write docs `aaaaaaa2` — waiting on Ship API (aaaaaaa3)\n"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	slack, _ := r.Render("slack")
+	if !strings.Contains(slack, "*orion*\n_Completed_\n• Fix login `aaaaaaa1`\n") {
+		t.Errorf("slack output:\n%s", slack)
+	}
+	if _, err := r.Render("html"); err == nil {
+		t.Error("unknown format should fail")
+	}
+
+	empty, _ := Report{Title: "Standup"}.Render("markdown")
+	if !strings.Contains(empty, "Nothing to report.") {
+		t.Errorf("empty report:\n%s", empty)
+	}
+}
@@ -0,0 +1,178 @@
+package fakeapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/attach"
+	"github.com/terzigolu/josepshbrain-go/internal/constants"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func (st *Store) findAttachment(ident string) *models.Attachment {
+	for i := range st.Attachments {
+		if matchID(st.Attachments[i].ID, ident) {
+			return &st.Attachments[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, constants.MaxAttachmentBytes+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, attach.ErrTooLarge.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read file")
+		return
+	}
+	if !constants.IsWithinAttachmentLimit(int64(len(data))) {
+		writeError(w, http.StatusRequestEntityTooLarge, attach.ErrTooLarge.Error())
+		return
+	}
+
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	a := models.Attachment{OwnerType: r.FormValue("owner_type"), Name: header.Filename}
+	switch a.OwnerType {
+	case "task":
+		t := st.findTask(r.FormValue("owner_id"))
+		if t == nil {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		projectID := t.ProjectID
+		a.OwnerID, a.ProjectID = t.ID, &projectID
+	case "memory":
+		m := st.findMemory(r.FormValue("owner_id"))
+		if m == nil {
+			writeError(w, http.StatusNotFound, "memory not found")
+			return
+		}
+		projectID := m.ProjectID
+		a.OwnerID, a.ProjectID = m.ID, &projectID
+	default:
+		writeError(w, http.StatusBadRequest, "owner_type must be task or memory")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	a.SHA256 = hex.EncodeToString(sum[:])
+	// Attaching the same file twice returns the existing attachment
+	for _, existing := range st.Attachments {
+		if existing.OwnerID == a.OwnerID && existing.SHA256 == a.SHA256 {
+			writeJSON(w, http.StatusOK, existing)
+			return
+		}
+	}
+	a.ID = st.newID()
+	a.Size = int64(len(data))
+	a.MimeType = attach.ContentType(a.Name, data[:min(len(data), 512)])
+	a.CreatedAt = st.now()
+	if st.blobs == nil {
+		st.blobs = map[string][]byte{}
+	}
+	st.blobs[a.SHA256] = data
+	st.Attachments = append(st.Attachments, a)
+	writeJSON(w, http.StatusCreated, a)
+}
+
+func (s *Server) listAttachments(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	q := r.URL.Query()
+	attachments := []models.Attachment{}
+	// Newest first
+	for i := len(st.Attachments) - 1; i >= 0; i-- {
+		a := st.Attachments[i]
+		if q.Get("owner_type") != "" && a.OwnerType != q.Get("owner_type") {
+			continue
+		}
+		if q.Get("owner_id") != "" && !matchID(a.OwnerID, q.Get("owner_id")) {
+			continue
+		}
+		if q.Get("project_id") != "" && (a.ProjectID == nil || !matchID(*a.ProjectID, q.Get("project_id"))) {
+			continue
+		}
+		if search := strings.TrimSpace(q.Get("search")); search != "" {
+			if !attach.IsText(a.MimeType) {
+				continue
+			}
+			if a.Snippet = attach.Snippet(string(st.blobs[a.SHA256]), search, 80); a.Snippet == "" {
+				continue
+			}
+		}
+		attachments = append(attachments, a)
+	}
+	writeJSON(w, http.StatusOK, attachments)
+}
+
+func (s *Server) getAttachment(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	a := st.findAttachment(r.PathValue("id"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (s *Server) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	a := st.findAttachment(r.PathValue("id"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	w.Header().Set("Content-Type", a.MimeType)
+	_, _ = w.Write(st.blobs[a.SHA256])
+}
+
+func (s *Server) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i, a := range st.Attachments {
+		if matchID(a.ID, r.PathValue("id")) {
+			st.Attachments = append(st.Attachments[:i], st.Attachments[i+1:]...)
+			if !st.contentShared(a.SHA256) {
+				delete(st.blobs, a.SHA256)
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "attachment not found")
+}
+
+// contentShared reports whether another attachment still uses the content
+func (st *Store) contentShared(sum string) bool {
+	for _, a := range st.Attachments {
+		if a.SHA256 == sum {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package fakeapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// reportWindow parses ?days= and ?project= into the period ending at the
+// store clock and an optional project filter
+func (st *Store) reportWindow(r *http.Request, defaultDays int) (from, to time.Time, projectID *uuid.UUID, ok bool) {
+	days := defaultDays
+	if v, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && v > 0 {
+		days = v
+	}
+	to = st.Clock.UTC().Truncate(24 * time.Hour)
+	from = to.AddDate(0, 0, -(days - 1))
+	if ident := r.URL.Query().Get("project"); ident != "" {
+		p := st.findProject(ident)
+		if p == nil {
+			return from, to, nil, false
+		}
+		projectID = &p.ID
+	}
+	return from, to, projectID, true
+}
+
+// completedAt is when a task was completed, falling back to its last
+// update for fixtures without completion times
+func completedAt(t models.Task) (time.Time, bool) {
+	if t.CompletedAt != nil {
+		return *t.CompletedAt, true
+	}
+	return t.UpdatedAt, t.Status == "COMPLETED"
+}
+
+func (s *Server) burndown(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	from, to, projectID, ok := st.reportWindow(r, 30)
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	interval := r.URL.Query().Get("interval")
+	step := 1
+	if interval == "weekly" {
+		step = 7
+	} else {
+		interval = "daily"
+	}
+
+	report := models.BurndownReport{Interval: interval, Points: []models.BurndownPoint{}}
+	for end := from.AddDate(0, 0, step-1); !end.After(to.AddDate(0, 0, step-1)); end = end.AddDate(0, 0, step) {
+		start := end.AddDate(0, 0, -step+1)
+		cutoff := end.AddDate(0, 0, 1)
+		p := models.BurndownPoint{Date: end.Format("2006-01-02")}
+		for _, t := range st.Tasks {
+			if projectID != nil && t.ProjectID != *projectID || !t.CreatedAt.Before(cutoff) {
+				continue
+			}
+			done, completed := completedAt(t)
+			switch {
+			case !completed || !done.Before(cutoff):
+				p.Remaining++
+			case !done.Before(start):
+				p.Completed++
+			}
+		}
+		report.Points = append(report.Points, p)
+	}
+	if n := len(report.Points); n > 0 {
+		first := float64(report.Points[0].Remaining)
+		for i := range report.Points {
+			ideal := first
+			if n > 1 {
+				ideal = first * float64(n-1-i) / float64(n-1)
+			}
+			report.Points[i].Ideal = &ideal
+		}
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) history(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	from, to, projectID, ok := st.reportWindow(r, 7)
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	limit := 15
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+
+	var all []models.Activity
+	add := func(a models.Activity, project uuid.UUID) {
+		if projectID != nil && project != *projectID {
+			return
+		}
+		if a.CreatedAt.Before(from) || !a.CreatedAt.Before(to.AddDate(0, 0, 1)) {
+			return
+		}
+		all = append(all, a)
+	}
+	for _, t := range st.Tasks {
+		add(models.Activity{Action: "created", EntityType: "task", EntityID: t.ID, Title: t.Title, CreatedAt: t.CreatedAt}, t.ProjectID)
+		if done, completed := completedAt(t); completed {
+			add(models.Activity{Action: "completed", EntityType: "task", EntityID: t.ID, Title: t.Title, CreatedAt: done}, t.ProjectID)
+		} else if t.UpdatedAt.After(t.CreatedAt) {
+			add(models.Activity{Action: "updated", EntityType: "task", EntityID: t.ID, Title: t.Title, CreatedAt: t.UpdatedAt}, t.ProjectID)
+		}
+	}
+	for _, m := range st.Memories {
+		title := []rune(m.Content)
+		if len(title) > 60 {
+			title = title[:60]
+		}
+		add(models.Activity{Action: "created", EntityType: "memory", EntityID: m.ID, Title: string(title), CreatedAt: m.CreatedAt}, m.ProjectID)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+
+	report := models.HistoryReport{History: all[:min(limit, len(all))], ByDay: map[string]int{}}
+	if report.History == nil {
+		report.History = []models.Activity{}
+	}
+	for _, a := range all {
+		report.ByDay[a.CreatedAt.Format("2006-01-02")]++
+	}
+	writeJSON(w, http.StatusOK, report)
+}
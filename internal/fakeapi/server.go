@@ -0,0 +1,960 @@
+// Package fakeapi provides an in-memory fake of the ramorie /v1 backend for tests.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Server is an httptest server answering /v1 requests from a Store
+type Server struct {
+	*httptest.Server
+	Store *Store
+}
+
+// NewServer starts a fake backend serving the given store
+func NewServer(store *Store) *Server {
+	s := &Server{Store: store}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// APIClient returns an API client pointed at the fake backend
+func (s *Server) APIClient() *api.Client {
+	return &api.Client{
+		BaseURL:    s.URL + "/v1",
+		HTTPClient: s.Client(),
+		APIKey:     "test-key",
+	}
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/projects", s.listProjects)
+	mux.HandleFunc("POST /v1/projects", s.createProject)
+	mux.HandleFunc("GET /v1/projects/{id}", s.getProject)
+	mux.HandleFunc("POST /v1/projects/{id}/use", s.useProject)
+
+	mux.HandleFunc("GET /v1/tasks", s.listTasks)
+	mux.HandleFunc("POST /v1/tasks", s.createTask)
+	mux.HandleFunc("GET /v1/tasks/active", s.getActiveTask)
+	mux.HandleFunc("GET /v1/tasks/{id}", s.getTask)
+	mux.HandleFunc("PUT /v1/tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /v1/tasks/{id}", s.deleteTask)
+	mux.HandleFunc("POST /v1/tasks/{id}/start", s.taskTransition("IN_PROGRESS", true))
+	mux.HandleFunc("POST /v1/tasks/{id}/done", s.taskTransition("COMPLETED", false))
+	mux.HandleFunc("POST /v1/tasks/{id}/stop", s.taskTransition("", false))
+	mux.HandleFunc("POST /v1/tasks/{id}/annotations", s.createAnnotation)
+	mux.HandleFunc("POST /v1/tasks/{id}/dependencies", s.createDependency)
+	mux.HandleFunc("GET /v1/tasks/{id}/subtasks", s.listSubtasks)
+	mux.HandleFunc("POST /v1/tasks/{id}/subtasks", s.createSubtask)
+	mux.HandleFunc("GET /v1/tasks/{id}/memories", s.listTaskMemories)
+
+	mux.HandleFunc("GET /v1/memories", s.listMemories)
+	mux.HandleFunc("POST /v1/memories", s.createMemory)
+	mux.HandleFunc("GET /v1/memories/{id}", s.getMemory)
+	mux.HandleFunc("PUT /v1/memories/{id}", s.updateMemory)
+	mux.HandleFunc("DELETE /v1/memories/{id}", s.deleteMemory)
+	mux.HandleFunc("GET /v1/memories/{id}/tasks", s.listMemoryTasks)
+	mux.HandleFunc("GET /v1/memories/{id}/revisions", s.listRevisions)
+	mux.HandleFunc("POST /v1/memories/{id}/revisions/{version}/restore", s.restoreRevision)
+	mux.HandleFunc("POST /v1/memory-task-links", s.createLink)
+	mux.HandleFunc("GET /v1/memory-links", s.listMemoryLinks)
+	mux.HandleFunc("POST /v1/memory-links", s.createMemoryLink)
+	mux.HandleFunc("DELETE /v1/memory-links/{id}", s.deleteMemoryLink)
+
+	mux.HandleFunc("GET /v1/attachments", s.listAttachments)
+	mux.HandleFunc("POST /v1/attachments", s.uploadAttachment)
+	mux.HandleFunc("GET /v1/attachments/{id}", s.getAttachment)
+	mux.HandleFunc("GET /v1/attachments/{id}/content", s.downloadAttachment)
+	mux.HandleFunc("DELETE /v1/attachments/{id}", s.deleteAttachment)
+
+	mux.HandleFunc("GET /v1/contexts", s.listContexts)
+	mux.HandleFunc("POST /v1/contexts", s.createContext)
+
+	mux.HandleFunc("GET /v1/decisions", s.listDecisions)
+	mux.HandleFunc("POST /v1/decisions", s.createDecision)
+
+	mux.HandleFunc("GET /v1/me/focus", s.getFocus)
+	mux.HandleFunc("POST /v1/me/focus", s.setFocus)
+	mux.HandleFunc("DELETE /v1/me/focus", s.clearFocus)
+
+	mux.HandleFunc("GET /v1/reports/stats", s.stats)
+	mux.HandleFunc("GET /v1/reports/burndown", s.burndown)
+	mux.HandleFunc("GET /v1/reports/history", s.history)
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func decode(r *http.Request, v interface{}) bool {
+	return json.NewDecoder(r.Body).Decode(v) == nil
+}
+
+// --- Projects ---
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.Store.Projects)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if !decode(r, &req) || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.findProject(req.Name) != nil {
+		writeError(w, http.StatusConflict, "project already exists")
+		return
+	}
+	now := st.now()
+	p := models.Project{ID: st.newID(), Name: req.Name, Description: req.Description, CreatedAt: now, UpdatedAt: now}
+	st.Projects = append(st.Projects, p)
+	writeJSON(w, http.StatusCreated, p)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	p := st.findProject(r.PathValue("id"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) useProject(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	p := st.findProject(r.PathValue("id"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	for i := range st.Projects {
+		st.Projects[i].IsActive = st.Projects[i].ID == p.ID
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "project activated"})
+}
+
+// --- Tasks ---
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	projectID := q.Get("project_id")
+	status := q.Get("status")
+	search := strings.ToLower(q.Get("q"))
+	priorities := q["priorities"]
+
+	tasks := []models.Task{}
+	for _, t := range st.Tasks {
+		if projectID != "" && !matchID(t.ProjectID, projectID) {
+			continue
+		}
+		if status != "" && t.Status != status {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(t.Title+" "+t.Description), search) {
+			continue
+		}
+		if len(priorities) > 0 && !contains(priorities, t.Priority) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tasks": tasks, "total": len(tasks)})
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProjectID   string   `json:"project_id"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Priority    string   `json:"priority"`
+		Tags        []string `json:"tags"`
+	}
+	if !decode(r, &req) || req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	p := st.findProject(req.ProjectID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	now := st.now()
+	t := models.Task{
+		ID:          st.newID(),
+		ProjectID:   p.ID,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      "TODO",
+		Priority:    req.Priority,
+		Tags:        req.Tags,
+		Annotations: []models.Annotation{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	st.Tasks = append(st.Tasks, t)
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	if v, ok := req["title"].(string); ok {
+		t.Title = v
+	}
+	if v, ok := req["description"].(string); ok {
+		t.Description = v
+	}
+	if v, ok := req["status"].(string); ok {
+		t.Status = v
+	}
+	if v, ok := req["priority"].(string); ok {
+		t.Priority = v
+	}
+	if v, ok := req["project_id"].(string); ok {
+		if p := st.findProject(v); p != nil {
+			t.ProjectID = p.ID
+		}
+	}
+	if v, ok := req["due_date"]; ok {
+		t.DueDate = nil
+		if s, ok := v.(string); ok {
+			if due, err := time.Parse(time.RFC3339, s); err == nil {
+				t.DueDate = &due
+			}
+		}
+	}
+	t.UpdatedAt = st.now()
+	st.stamp(t)
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i, t := range st.Tasks {
+		if matchID(t.ID, r.PathValue("id")) {
+			st.Tasks = append(st.Tasks[:i], st.Tasks[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "task not found")
+}
+
+// taskTransition changes a task's status (if set) and its active state
+func (s *Server) taskTransition(status string, activate bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		st := s.Store
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		t := st.findTask(r.PathValue("id"))
+		if t == nil {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		if status != "" {
+			t.Status = status
+		}
+		t.UpdatedAt = st.now()
+		st.stamp(t)
+		if activate {
+			id := t.ID
+			st.ActiveTaskID = &id
+		} else if st.ActiveTaskID != nil && *st.ActiveTaskID == t.ID {
+			st.ActiveTaskID = nil
+		}
+		writeJSON(w, http.StatusOK, t)
+	}
+}
+
+// stamp records when a task was first started and when it was completed,
+// as the real backend does on status changes
+func (st *Store) stamp(t *models.Task) {
+	now := t.UpdatedAt
+	switch t.Status {
+	case "IN_PROGRESS", "IN_REVIEW":
+		if t.StartedAt == nil {
+			t.StartedAt = &now
+		}
+		t.CompletedAt = nil
+	case "COMPLETED":
+		if t.CompletedAt == nil {
+			t.CompletedAt = &now
+		}
+	default:
+		t.CompletedAt = nil
+	}
+}
+
+func (s *Server) getActiveTask(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var active *models.Task
+	if st.ActiveTaskID != nil {
+		active = st.findTask(st.ActiveTaskID.String())
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"active_task": active})
+}
+
+func (s *Server) createAnnotation(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Content string `json:"content"`
+	}
+	if !decode(r, &req) || req.Content == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	a := models.Annotation{ID: st.newID(), TaskID: t.ID, Content: req.Content, CreatedAt: st.now()}
+	t.Annotations = append(t.Annotations, a)
+	writeJSON(w, http.StatusCreated, a)
+}
+
+func (s *Server) createDependency(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		BlockingTaskID string `json:"blocking_task_id"`
+	}
+	if !decode(r, &req) || req.BlockingTaskID == "" {
+		writeError(w, http.StatusBadRequest, "blocking_task_id is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t, blocking := st.findTask(r.PathValue("id")), st.findTask(req.BlockingTaskID)
+	if t == nil || blocking == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	if t.ID == blocking.ID {
+		writeError(w, http.StatusBadRequest, "a task cannot depend on itself")
+		return
+	}
+	d := models.Dependency{ID: st.newID(), BlockingTaskID: blocking.ID, BlockedTaskID: t.ID}
+	t.BlockingTasks = append(t.BlockingTasks, d)
+	writeJSON(w, http.StatusCreated, d)
+}
+
+func (s *Server) listSubtasks(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	subs := []models.Subtask{}
+	for _, sub := range st.Subtasks {
+		if sub.TaskID == t.ID {
+			subs = append(subs, sub)
+		}
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (s *Server) createSubtask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Description string `json:"description"`
+	}
+	if !decode(r, &req) || req.Description == "" {
+		writeError(w, http.StatusBadRequest, "description is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	sub := models.Subtask{ID: st.newID(), TaskID: t.ID, Description: req.Description, CreatedAt: st.now()}
+	st.Subtasks = append(st.Subtasks, sub)
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (s *Server) listTaskMemories(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	memories := []models.Memory{}
+	for _, m := range st.Memories {
+		if st.linked(t.ID, m) {
+			memories = append(memories, st.withProject(m))
+		}
+	}
+	writeJSON(w, http.StatusOK, memories)
+}
+
+// linked reports whether a memory is auto-linked or manually linked to a task
+func (st *Store) linked(taskID uuid.UUID, m models.Memory) bool {
+	if m.LinkedTaskID != nil && *m.LinkedTaskID == taskID {
+		return true
+	}
+	for _, l := range st.Links {
+		if l.TaskID == taskID && l.MemoryID == m.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Memories ---
+
+func (s *Server) listMemories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	projectID := q.Get("project_id")
+	search := strings.ToLower(q.Get("search"))
+	memoryType := q.Get("type")
+
+	memories := []models.Memory{}
+	for _, m := range st.Memories {
+		if projectID != "" && !matchID(m.ProjectID, projectID) {
+			continue
+		}
+		if memoryType != "" && !strings.EqualFold(m.Type, memoryType) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(m.Content), search) {
+			continue
+		}
+		memories = append(memories, st.withProject(m))
+	}
+	// The backend returns newest first
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.After(memories[j].CreatedAt)
+	})
+	writeJSON(w, http.StatusOK, api.MemoriesListResponse{Memories: memories, Total: len(memories), Limit: len(memories)})
+}
+
+func (s *Server) createMemory(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProjectID   string            `json:"project_id"`
+		Content     string            `json:"content"`
+		Tags        []string          `json:"tags"`
+		Type        string            `json:"type"`
+		Fields      map[string]string `json:"fields"`
+		ExpiresAt   *time.Time        `json:"expires_at"`
+		ReviewAfter *time.Time        `json:"review_after"`
+		Evergreen   bool              `json:"evergreen"`
+	}
+	if !decode(r, &req) || req.Content == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	p := st.findProject(req.ProjectID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+	now := st.now()
+	m := models.Memory{ID: st.newID(), ProjectID: p.ID, Content: req.Content, Type: req.Type, Fields: req.Fields, CreatedAt: now, UpdatedAt: now,
+		ExpiresAt: req.ExpiresAt, ReviewAfter: req.ReviewAfter, Evergreen: req.Evergreen}
+	if len(req.Tags) > 0 {
+		m.Tags = req.Tags
+	}
+	if st.ActiveTaskID != nil {
+		id := *st.ActiveTaskID
+		m.LinkedTaskID = &id
+	}
+	st.Memories = append(st.Memories, m)
+	writeJSON(w, http.StatusCreated, st.withProject(m))
+}
+
+func (s *Server) getMemory(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.findMemory(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, st.withProject(*m))
+}
+
+func (s *Server) updateMemory(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.findMemory(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	if v, ok := req["content"].(string); ok && v != m.Content {
+		st.addRevision(*m, v)
+		m.Content = v
+	}
+	if v, ok := req["tags"]; ok {
+		m.Tags = v
+	}
+	for key, field := range map[string]**time.Time{"expires_at": &m.ExpiresAt, "review_after": &m.ReviewAfter, "archived_at": &m.ArchivedAt} {
+		v, ok := req[key]
+		if !ok {
+			continue
+		}
+		if v == nil {
+			*field = nil
+			continue
+		}
+		s, _ := v.(string)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+key)
+			return
+		}
+		*field = &t
+	}
+	if v, ok := req["evergreen"].(bool); ok {
+		m.Evergreen = v
+	}
+	m.UpdatedAt = st.now()
+	writeJSON(w, http.StatusOK, st.withProject(*m))
+}
+
+func (s *Server) listRevisions(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.findMemory(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	revisions := []api.MemoryRevision{}
+	for _, rev := range st.Revisions {
+		if rev.MemoryID == m.ID.String() {
+			revisions = append(revisions, rev)
+		}
+	}
+	writeJSON(w, http.StatusOK, api.MemoryRevisionListResponse{Revisions: revisions})
+}
+
+func (s *Server) restoreRevision(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.findMemory(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	for _, rev := range st.Revisions {
+		if rev.MemoryID == m.ID.String() && r.PathValue("version") == strconv.Itoa(rev.Version) {
+			if rev.Content != m.Content {
+				st.addRevision(*m, rev.Content)
+				m.Content = rev.Content
+				m.UpdatedAt = st.now()
+			}
+			writeJSON(w, http.StatusOK, st.withProject(*m))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "revision not found")
+}
+
+func (s *Server) deleteMemory(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i, m := range st.Memories {
+		if matchID(m.ID, r.PathValue("id")) {
+			st.Memories = append(st.Memories[:i], st.Memories[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "memory not found")
+}
+
+func (s *Server) listMemoryTasks(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	m := st.findMemory(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	tasks := []models.Task{}
+	for _, t := range st.Tasks {
+		if st.linked(t.ID, *m) {
+			tasks = append(tasks, t)
+		}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) createLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TaskID       string `json:"task_id"`
+		MemoryID     string `json:"memory_id"`
+		RelationType string `json:"relation_type"`
+	}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	t := st.findTask(req.TaskID)
+	m := st.findMemory(req.MemoryID)
+	if t == nil || m == nil {
+		writeError(w, http.StatusNotFound, "task or memory not found")
+		return
+	}
+	l := Link{TaskID: t.ID, MemoryID: m.ID, RelationType: req.RelationType}
+	st.Links = append(st.Links, l)
+	writeJSON(w, http.StatusCreated, l)
+}
+
+func (s *Server) createMemoryLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MemoryID       string `json:"memory_id"`
+		TargetMemoryID string `json:"target_memory_id"`
+		RelationType   string `json:"relation_type"`
+	}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	from := st.findMemory(req.MemoryID)
+	to := st.findMemory(req.TargetMemoryID)
+	if from == nil || to == nil {
+		writeError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+	if from.ID == to.ID {
+		writeError(w, http.StatusBadRequest, "a memory cannot link to itself")
+		return
+	}
+	if req.RelationType == "" {
+		req.RelationType = "see-also"
+	}
+	for _, l := range st.MemoryLinks {
+		if l.MemoryID == from.ID && l.TargetMemoryID == to.ID && l.RelationType == req.RelationType {
+			writeError(w, http.StatusConflict, "memories are already linked")
+			return
+		}
+	}
+	l := models.MemoryLink{ID: st.newID(), MemoryID: from.ID, TargetMemoryID: to.ID, RelationType: req.RelationType, CreatedAt: st.now()}
+	st.MemoryLinks = append(st.MemoryLinks, l)
+	writeJSON(w, http.StatusCreated, l)
+}
+
+func (s *Server) listMemoryLinks(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	memoryID := r.URL.Query().Get("memory_id")
+	projectID := r.URL.Query().Get("project_id")
+	links := []models.MemoryLink{}
+	for _, l := range st.MemoryLinks {
+		if memoryID != "" && !matchID(l.MemoryID, memoryID) && !matchID(l.TargetMemoryID, memoryID) {
+			continue
+		}
+		if projectID != "" {
+			m := st.findMemory(l.MemoryID.String())
+			if m == nil || !matchID(m.ProjectID, projectID) {
+				continue
+			}
+		}
+		links = append(links, l)
+	}
+	writeJSON(w, http.StatusOK, links)
+}
+
+func (s *Server) deleteMemoryLink(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i, l := range st.MemoryLinks {
+		if matchID(l.ID, r.PathValue("id")) {
+			st.MemoryLinks = append(st.MemoryLinks[:i], st.MemoryLinks[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "memory link not found")
+}
+
+// --- Contexts ---
+
+func (s *Server) listContexts(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	contexts := append([]models.Context{}, st.Contexts...)
+	writeJSON(w, http.StatusOK, contexts)
+}
+
+func (s *Server) createContext(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if !decode(r, &req) || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := st.now()
+	ctx := models.Context{ID: st.newID(), Name: req.Name, CreatedAt: now, UpdatedAt: now}
+	if req.Description != "" {
+		ctx.Description = &req.Description
+	}
+	st.Contexts = append(st.Contexts, ctx)
+	writeJSON(w, http.StatusCreated, ctx)
+}
+
+// --- Decisions ---
+
+func (s *Server) listDecisions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	decisions := []api.Decision{}
+	for _, d := range st.Decisions {
+		if status := q.Get("status"); status != "" && d.Status != status {
+			continue
+		}
+		if area := q.Get("area"); area != "" && !strings.EqualFold(d.Area, area) {
+			continue
+		}
+		decisions = append(decisions, d)
+	}
+	writeJSON(w, http.StatusOK, api.DecisionListResponse{Decisions: decisions, Total: int64(len(decisions))})
+}
+
+func (s *Server) createDecision(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		Status       string `json:"status"`
+		Area         string `json:"area"`
+		Context      string `json:"context"`
+		Consequences string `json:"consequences"`
+	}
+	if !decode(r, &req) || req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := st.now()
+	d := api.Decision{
+		ID:          st.newID().String(),
+		ADRNumber:   adrNumber(len(st.Decisions) + 1),
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      req.Status,
+		Area:        req.Area,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if d.Status == "" {
+		d.Status = "draft"
+	}
+	if req.Context != "" {
+		d.Context = &req.Context
+	}
+	if req.Consequences != "" {
+		d.Consequences = &req.Consequences
+	}
+	st.Decisions = append(st.Decisions, d)
+	writeJSON(w, http.StatusCreated, d)
+}
+
+func adrNumber(n int) string {
+	return fmt.Sprintf("ADR-%03d", n)
+}
+
+// --- Focus ---
+
+func (s *Server) focus() api.UserFocus {
+	st := s.Store
+	f := api.UserFocus{ActiveContextPackID: st.ActiveContextPackID}
+	if st.ActiveContextPackID != nil {
+		if p := st.findContextPack(*st.ActiveContextPackID); p != nil {
+			f.ActivePack = &api.FocusPackDetail{
+				ID:          p.ID,
+				Name:        p.Name,
+				Description: p.Description,
+				Type:        p.Type,
+				Status:      p.Status,
+				Contexts:    []api.FocusContextPreview{},
+			}
+		}
+	}
+	return f
+}
+
+func (s *Server) getFocus(w http.ResponseWriter, r *http.Request) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.focus())
+}
+
+func (s *Server) setFocus(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ContextPackID string `json:"context_pack_id"`
+	}
+	if !decode(r, &req) {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	p := st.findContextPack(req.ContextPackID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "context pack not found")
+		return
+	}
+	id := p.ID
+	st.ActiveContextPackID = &id
+	writeJSON(w, http.StatusOK, map[string]interface{}{"message": "focus updated", "focus": s.focus()})
+}
+
+func (s *Server) clearFocus(w http.ResponseWriter, r *http.Request) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+	s.Store.ActiveContextPackID = nil
+	writeJSON(w, http.StatusOK, map[string]string{"message": "focus cleared"})
+}
+
+// --- Reports ---
+
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	st := s.Store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	byStatus := map[string]int{}
+	byPriority := map[string]int{}
+	for _, t := range st.Tasks {
+		byStatus[t.Status]++
+		byPriority[t.Priority]++
+	}
+	total := len(st.Tasks)
+	rate := 0.0
+	if total > 0 {
+		rate = float64(byStatus["COMPLETED"]) / float64(total) * 100
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_tasks":     total,
+		"completed_tasks": byStatus["COMPLETED"],
+		"completion_rate": rate,
+		"by_status":       byStatus,
+		"by_priority":     byPriority,
+	})
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
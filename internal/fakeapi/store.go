@@ -0,0 +1,162 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Store is the in-memory state behind the fake backend.
+// It is loaded from a JSON fixture file and mutated by API calls.
+type Store struct {
+	Projects     []models.Project     `json:"projects"`
+	Tasks        []models.Task        `json:"tasks"`
+	Memories     []models.Memory      `json:"memories"`
+	Subtasks     []models.Subtask     `json:"subtasks"`
+	Contexts     []models.Context     `json:"contexts"`
+	Decisions    []api.Decision       `json:"decisions"`
+	ContextPacks []api.ContextPack    `json:"context_packs"`
+	Links        []Link               `json:"links"`
+	MemoryLinks  []models.MemoryLink  `json:"memory_links"`
+	Revisions    []api.MemoryRevision `json:"revisions"`
+	Attachments  []models.Attachment  `json:"attachments"`
+
+	ActiveTaskID        *uuid.UUID `json:"active_task_id,omitempty"`
+	ActiveContextPackID *string    `json:"active_context_pack_id,omitempty"`
+
+	// Clock is the time used for new entities; it advances one second per call
+	Clock time.Time `json:"clock"`
+
+	mu     sync.Mutex
+	nextID uint64
+	blobs  map[string][]byte // attachment content by SHA-256
+}
+
+// Link is a memory-task link created via /memory-task-links
+type Link struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	MemoryID     uuid.UUID `json:"memory_id"`
+	RelationType string    `json:"relation_type"`
+}
+
+// LoadStore reads a fixture file into a new store
+func LoadStore(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if s.Clock.IsZero() {
+		s.Clock = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	}
+	return &s, nil
+}
+
+// newID returns a deterministic UUID so results are stable across runs
+func (s *Store) newID() uuid.UUID {
+	s.nextID++
+	// The counter leads so short IDs differ; the rest marks the ID as fake
+	var id uuid.UUID
+	for i := 0; i < 4; i++ {
+		id[3-i] = byte(s.nextID >> (8 * i))
+	}
+	copy(id[4:], "fakeapi-uuid")
+	return id
+}
+
+// now returns the store clock and advances it
+func (s *Store) now() time.Time {
+	t := s.Clock
+	s.Clock = s.Clock.Add(time.Second)
+	return t
+}
+
+// matchID reports whether an ID matches a full or short (prefix) identifier
+func matchID(id uuid.UUID, ident string) bool {
+	ident = strings.ToLower(strings.TrimSpace(ident))
+	return ident != "" && strings.HasPrefix(id.String(), ident)
+}
+
+func (s *Store) findProject(ident string) *models.Project {
+	for i := range s.Projects {
+		if matchID(s.Projects[i].ID, ident) || s.Projects[i].Name == ident {
+			return &s.Projects[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) findTask(ident string) *models.Task {
+	for i := range s.Tasks {
+		if matchID(s.Tasks[i].ID, ident) {
+			return &s.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) findMemory(ident string) *models.Memory {
+	for i := range s.Memories {
+		if matchID(s.Memories[i].ID, ident) {
+			return &s.Memories[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) findContextPack(ident string) *api.ContextPack {
+	for i := range s.ContextPacks {
+		if strings.HasPrefix(s.ContextPacks[i].ID, ident) {
+			return &s.ContextPacks[i]
+		}
+	}
+	return nil
+}
+
+// withProject attaches the project relation the real backend embeds in responses
+func (s *Store) withProject(m models.Memory) models.Memory {
+	if p := s.findProject(m.ProjectID.String()); p != nil {
+		cp := *p
+		m.Project = &cp
+	}
+	return m
+}
+
+// addRevision records new content as the next version of a memory,
+// storing the previous content as version 1 if the memory had no history
+func (s *Store) addRevision(previous models.Memory, content string) {
+	latest := 0
+	for _, r := range s.Revisions {
+		if r.MemoryID == previous.ID.String() && r.Version > latest {
+			latest = r.Version
+		}
+	}
+	if latest == 0 {
+		s.Revisions = append(s.Revisions, api.MemoryRevision{
+			ID:        s.newID().String(),
+			MemoryID:  previous.ID.String(),
+			Version:   1,
+			Content:   previous.Content,
+			CreatedAt: previous.CreatedAt,
+		})
+		latest = 1
+	}
+	s.Revisions = append(s.Revisions, api.MemoryRevision{
+		ID:        s.newID().String(),
+		MemoryID:  previous.ID.String(),
+		Version:   latest + 1,
+		Content:   content,
+		Author:    "fakeapi",
+		CreatedAt: s.now(),
+	})
+}
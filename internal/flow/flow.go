@@ -0,0 +1,169 @@
+// Package flow computes delivery metrics from task timestamps: lead time
+// (created → completed), cycle time (started → completed), the age of work
+// in progress and weekly throughput. It only needs the task list, so the
+// numbers are the same against any backend.
+package flow
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Item is a completed task with its flow times. Cycle is zero when the task
+// was never started.
+type Item struct {
+	Task      models.Task   `json:"-"`
+	TaskID    string        `json:"task_id"`
+	Title     string        `json:"title"`
+	Completed time.Time     `json:"completed_at"`
+	Lead      time.Duration `json:"lead_ns"`
+	Cycle     time.Duration `json:"cycle_ns,omitempty"`
+}
+
+// WIP is a task in progress and how long it has been in progress
+type WIP struct {
+	Task   models.Task   `json:"-"`
+	TaskID string        `json:"task_id"`
+	Title  string        `json:"title"`
+	Since  time.Time     `json:"since"`
+	Age    time.Duration `json:"age_ns"`
+}
+
+// Measure splits tasks into those completed in [from, to] and those in
+// progress at to. Tasks without a CompletedAt are not counted as completed,
+// and in-progress tasks without a StartedAt age from their creation.
+func Measure(tasks []models.Task, from, to time.Time) ([]Item, []WIP) {
+	var done []Item
+	var wip []WIP
+	for _, t := range tasks {
+		switch {
+		case t.CompletedAt != nil:
+			c := *t.CompletedAt
+			if c.Before(from) || c.After(to) {
+				continue
+			}
+			item := Item{Task: t, TaskID: t.ID.String(), Title: t.Title, Completed: c, Lead: nonNegative(c.Sub(t.CreatedAt))}
+			if t.StartedAt != nil {
+				item.Cycle = nonNegative(c.Sub(*t.StartedAt))
+			}
+			done = append(done, item)
+		case t.Status == "IN_PROGRESS" || t.Status == "IN_REVIEW":
+			since := t.CreatedAt
+			if t.StartedAt != nil {
+				since = *t.StartedAt
+			}
+			wip = append(wip, WIP{Task: t, TaskID: t.ID.String(), Title: t.Title, Since: since, Age: nonNegative(to.Sub(since))})
+		}
+	}
+	sort.Slice(done, func(i, j int) bool { return done[i].Completed.Before(done[j].Completed) })
+	sort.Slice(wip, func(i, j int) bool { return wip[i].Age > wip[j].Age })
+	return done, wip
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Percentiles summarizes a set of durations
+type Percentiles struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50_ns"`
+	P85   time.Duration `json:"p85_ns"`
+	P95   time.Duration `json:"p95_ns"`
+}
+
+// Summarize returns the 50th, 85th and 95th percentiles (nearest rank)
+func Summarize(durations []time.Duration) Percentiles {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return Percentiles{
+		Count: len(sorted),
+		P50:   Percentile(sorted, 50),
+		P85:   Percentile(sorted, 85),
+		P95:   Percentile(sorted, 95),
+	}
+}
+
+// Percentile returns the nearest-rank p-th percentile of sorted durations
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// Group is the lead and cycle time percentiles of the items sharing a key
+type Group struct {
+	Key   string      `json:"key"`
+	Lead  Percentiles `json:"lead"`
+	Cycle Percentiles `json:"cycle"`
+}
+
+// GroupBy summarizes items per key. An item can belong to several groups
+// (one per tag, say); items with no keys are left out. Groups are ordered
+// by size, largest first.
+func GroupBy(items []Item, keys func(Item) []string) []Group {
+	lead := map[string][]time.Duration{}
+	cycle := map[string][]time.Duration{}
+	for _, it := range items {
+		for _, k := range keys(it) {
+			lead[k] = append(lead[k], it.Lead)
+			if it.Cycle > 0 {
+				cycle[k] = append(cycle[k], it.Cycle)
+			}
+		}
+	}
+	groups := make([]Group, 0, len(lead))
+	for k, d := range lead {
+		groups = append(groups, Group{Key: k, Lead: Summarize(d), Cycle: Summarize(cycle[k])})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Lead.Count != groups[j].Lead.Count {
+			return groups[i].Lead.Count > groups[j].Lead.Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// Week is the number of tasks completed in the week starting Start (a Monday)
+type Week struct {
+	Start     time.Time `json:"start"`
+	Completed int       `json:"completed"`
+}
+
+// Throughput counts completed items per week from the week of from to the
+// week of to, including weeks with nothing completed
+func Throughput(items []Item, from, to time.Time) []Week {
+	start := monday(from)
+	var weeks []Week
+	for w := start; !w.After(to); w = w.AddDate(0, 0, 7) {
+		weeks = append(weeks, Week{Start: w})
+	}
+	for _, it := range items {
+		// Weeks across a DST change are an hour short or long, so round
+		i := int(math.Round(Days(monday(it.Completed.In(start.Location())).Sub(start)) / 7))
+		if i >= 0 && i < len(weeks) {
+			weeks[i].Completed++
+		}
+	}
+	return weeks
+}
+
+func monday(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// Days expresses a duration in days, for display and charts
+func Days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
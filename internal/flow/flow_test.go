@@ -0,0 +1,120 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+var base = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC) // Monday
+
+func at(days float64) *time.Time {
+	t := base.Add(time.Duration(days * 24 * float64(time.Hour)))
+	return &t
+}
+
+func task(status string, created, started, completed *time.Time) models.Task {
+	t := models.Task{ID: uuid.New(), Status: status, CreatedAt: *created, StartedAt: started, CompletedAt: completed}
+	return t
+}
+
+func TestMeasure(t *testing.T) {
+	tasks := []models.Task{
+		task("COMPLETED", at(0), at(2), at(5)),
+		task("COMPLETED", at(1), nil, at(3)),
+		task("COMPLETED", at(-30), at(-29), at(-20)), // before the window
+		task("IN_PROGRESS", at(4), at(6), nil),
+		task("IN_PROGRESS", at(7), nil, nil),
+		task("TODO", at(1), nil, nil),
+	}
+	done, wip := Measure(tasks, base, *at(10))
+
+	if len(done) != 2 {
+		t.Fatalf("got %d completed items, want 2", len(done))
+	}
+	if done[0].Lead != 48*time.Hour || done[0].Cycle != 0 {
+		t.Errorf("unstarted task: lead %v cycle %v", done[0].Lead, done[0].Cycle)
+	}
+	if done[1].Lead != 5*24*time.Hour || done[1].Cycle != 3*24*time.Hour {
+		t.Errorf("started task: lead %v cycle %v", done[1].Lead, done[1].Cycle)
+	}
+	if len(wip) != 2 || wip[0].Age != 4*24*time.Hour || wip[1].Age != 3*24*time.Hour {
+		t.Errorf("wip = %+v", wip)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	var d []time.Duration
+	for i := 20; i >= 1; i-- {
+		d = append(d, time.Duration(i)*time.Hour)
+	}
+	p := Summarize(d)
+	if p.Count != 20 || p.P50 != 10*time.Hour || p.P85 != 17*time.Hour || p.P95 != 19*time.Hour {
+		t.Errorf("Summarize = %+v", p)
+	}
+	if Summarize(nil).P95 != 0 {
+		t.Error("empty percentiles should be zero")
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	items := []Item{
+		{TaskID: "a", Lead: time.Hour, Cycle: time.Hour},
+		{TaskID: "b", Lead: 3 * time.Hour},
+		{TaskID: "c", Lead: 5 * time.Hour, Cycle: 2 * time.Hour},
+	}
+	tags := map[string][]string{"a": {"api", "db"}, "b": {"api"}, "c": nil}
+	groups := GroupBy(items, func(it Item) []string { return tags[it.TaskID] })
+	if len(groups) != 2 || groups[0].Key != "api" || groups[1].Key != "db" {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if groups[0].Lead.Count != 2 || groups[0].Cycle.Count != 1 || groups[0].Lead.P95 != 3*time.Hour {
+		t.Errorf("api group = %+v", groups[0])
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	items := []Item{{Completed: *at(0)}, {Completed: *at(1)}, {Completed: *at(15)}}
+	weeks := Throughput(items, *at(-3), *at(16))
+	got := []int{}
+	for _, w := range weeks {
+		got = append(got, w.Completed)
+	}
+	want := []int{0, 2, 0, 1}
+	if len(got) != len(want) {
+		t.Fatalf("weeks = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("weeks = %v, want %v", got, want)
+		}
+	}
+	if weeks[1].Start.Weekday() != time.Monday {
+		t.Errorf("weeks should start on Monday, got %v", weeks[1].Start.Weekday())
+	}
+}
+
+func TestThroughputDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	// Clocks go forward on 2025-03-30, so that week is 167 hours long
+	from := time.Date(2025, 3, 24, 9, 0, 0, 0, berlin)
+	items := []Item{
+		{Completed: time.Date(2025, 3, 30, 23, 0, 0, 0, berlin)},
+		{Completed: time.Date(2025, 3, 31, 0, 30, 0, 0, berlin)},
+		{Completed: time.Date(2025, 4, 7, 10, 0, 0, 0, berlin)},
+		{Completed: time.Date(2025, 4, 6, 22, 30, 0, 0, time.UTC)}, // 00:30 Monday in Berlin
+	}
+	weeks := Throughput(items, from, time.Date(2025, 4, 8, 0, 0, 0, 0, berlin))
+	got := []int{}
+	for _, w := range weeks {
+		got = append(got, w.Completed)
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("weeks = %v, want [1 1 2]", got)
+	}
+}
@@ -0,0 +1,98 @@
+// Package freshness decides whether a memory is still current: expired and
+// archived memories are hidden from recall, memories past their review date
+// are queued for review, and recall ranking decays with age unless a memory
+// is evergreen.
+package freshness
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+const (
+	// HalfLife is the age at which a memory's recall weight has halved
+	HalfLife = 180 * 24 * time.Hour
+
+	// MinWeight keeps old but relevant memories findable
+	MinWeight = 0.25
+)
+
+// Expired reports whether m is past its expiry date
+func Expired(m models.Memory, now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
+
+// Hidden reports whether recall leaves m out by default
+func Hidden(m models.Memory, now time.Time) bool {
+	return m.ArchivedAt != nil || Expired(m, now)
+}
+
+// Due reports whether m belongs in the review queue: not archived, and past
+// its review date or expired
+func Due(m models.Memory, now time.Time) bool {
+	if m.ArchivedAt != nil {
+		return false
+	}
+	return Expired(m, now) || (m.ReviewAfter != nil && !now.Before(*m.ReviewAfter))
+}
+
+// DueDate is when m became due for review (the earlier of its review and
+// expiry dates), or the zero time if it has neither
+func DueDate(m models.Memory) time.Time {
+	var due time.Time
+	for _, t := range []*time.Time{m.ReviewAfter, m.ExpiresAt} {
+		if t != nil && (due.IsZero() || t.Before(due)) {
+			due = *t
+		}
+	}
+	return due
+}
+
+// Weight scales a recall score by age: 1 for new and evergreen memories,
+// halving every HalfLife since the last update, never below MinWeight
+func Weight(m models.Memory, now time.Time) float64 {
+	if m.Evergreen {
+		return 1
+	}
+	last := m.UpdatedAt
+	if last.IsZero() {
+		last = m.CreatedAt
+	}
+	age := now.Sub(last)
+	if last.IsZero() || age <= 0 {
+		return 1
+	}
+	return math.Max(MinWeight, math.Pow(0.5, float64(age)/float64(HalfLife)))
+}
+
+// ParseDate parses a date ("2006-01-02", local midnight) or an RFC3339 timestamp
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use 2006-01-02 or an RFC3339 timestamp)", s)
+}
+
+// Unapplied returns the lifecycle updates (expires_at, review_after,
+// evergreen) that m does not reflect, e.g. because the backend ignored them
+// when creating it. Clearing updates (nil, false) hold for a memory without
+// the field.
+func Unapplied(m models.Memory, updates map[string]interface{}) map[string]interface{} {
+	set := map[string]bool{"expires_at": m.ExpiresAt != nil, "review_after": m.ReviewAfter != nil, "evergreen": m.Evergreen}
+	missing := map[string]interface{}{}
+	for key, value := range updates {
+		want := value != nil && value != false
+		if has, ok := set[key]; ok && has != want {
+			missing[key] = value
+		}
+	}
+	return missing
+}
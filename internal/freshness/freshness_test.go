@@ -0,0 +1,66 @@
+package freshness
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestWeight(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    models.Memory
+		want float64
+	}{
+		{"new", models.Memory{UpdatedAt: now}, 1},
+		{"one half-life", models.Memory{UpdatedAt: now.Add(-HalfLife)}, 0.5},
+		{"falls back to created", models.Memory{CreatedAt: now.Add(-HalfLife)}, 0.5},
+		{"floor", models.Memory{UpdatedAt: now.Add(-10 * HalfLife)}, MinWeight},
+		{"evergreen", models.Memory{UpdatedAt: now.Add(-10 * HalfLife), Evergreen: true}, 1},
+	}
+	for _, tt := range tests {
+		if got := Weight(tt.m, now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Weight = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDueAndHidden(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	review := models.Memory{ReviewAfter: &past}
+	if !Due(review, now) || Hidden(review, now) {
+		t.Errorf("memory past review date: due=%v hidden=%v", Due(review, now), Hidden(review, now))
+	}
+	expired := models.Memory{ExpiresAt: &past, ReviewAfter: &future}
+	if !Due(expired, now) || !Hidden(expired, now) || !DueDate(expired).Equal(past) {
+		t.Errorf("expired memory: due=%v hidden=%v", Due(expired, now), Hidden(expired, now))
+	}
+	archived := models.Memory{ReviewAfter: &past, ArchivedAt: &past}
+	if Due(archived, now) || !Hidden(archived, now) {
+		t.Errorf("archived memory: due=%v hidden=%v", Due(archived, now), Hidden(archived, now))
+	}
+	if Due(models.Memory{ReviewAfter: &future}, now) {
+		t.Error("memory with a future review date is due")
+	}
+}
+
+func TestUnapplied(t *testing.T) {
+	expires := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	updates := map[string]interface{}{"expires_at": "2025-03-01T00:00:00Z", "review_after": "2025-02-01T00:00:00Z", "evergreen": true}
+	missing := Unapplied(models.Memory{ExpiresAt: &expires, Evergreen: true}, updates)
+	if len(missing) != 1 || missing["review_after"] != "2025-02-01T00:00:00Z" {
+		t.Errorf("missing = %v, want only review_after", missing)
+	}
+	cleared := map[string]interface{}{"expires_at": nil, "evergreen": false, "content": "x"}
+	if missing := Unapplied(models.Memory{}, cleared); len(missing) != 0 {
+		t.Errorf("clearing updates on a memory without the fields: missing = %v", missing)
+	}
+	if missing := Unapplied(models.Memory{ExpiresAt: &expires}, cleared); len(missing) != 1 || missing["expires_at"] != nil {
+		t.Errorf("clearing an expiry that is set: missing = %v", missing)
+	}
+}
@@ -0,0 +1,200 @@
+// Package gitlink links git commits and branches to tasks: a Ramorie-Task
+// trailer in the commit message names the task a commit belongs to.
+package gitlink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// TrailerKey is the commit message trailer naming the task
+const TrailerKey = "Ramorie-Task"
+
+// Hooks are the hooks InstallHooks writes
+var Hooks = []string{"prepare-commit-msg", "post-commit"}
+
+// marker identifies hooks written by ramorie
+const marker = "# Installed by ramorie git install-hooks"
+
+// ErrForeignHook is returned when a hook exists that ramorie did not write
+var ErrForeignHook = errors.New("hook exists and was not installed by ramorie")
+
+// Git runs git in dir and returns its trimmed output
+func Git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// HooksDir returns the hooks directory of the repository at dir, honouring core.hooksPath
+func HooksDir(dir string) (string, error) {
+	path, err := Git(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// HookScript is the shell script of a hook. It runs a hook it replaced
+// (<hook>.local) first, then calls back into ramorie, which never fails the commit.
+func HookScript(hook, ramorie string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s\nlocal_hook=\"$(dirname \"$0\")/%s.local\"\n"+
+		"if [ -x \"$local_hook\" ]; then \"$local_hook\" \"$@\" || exit $?; fi\n"+
+		"%s git hook %s \"$@\" || true\n", marker, hook, shellQuote(ramorie), hook)
+}
+
+// InstallHook writes a hook into dir. An existing hook written by someone
+// else is only replaced with force, after being saved as <hook>.local.
+// It reports whether the hook was already up to date.
+func InstallHook(dir, hook, ramorie string, force bool) (unchanged bool, err error) {
+	path := filepath.Join(dir, hook)
+	script := HookScript(hook, ramorie)
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && string(existing) == script:
+		return true, nil
+	case err == nil && !bytes.Contains(existing, []byte(marker)):
+		if !force {
+			return false, fmt.Errorf("%s: %w (use --force to replace it; it is kept as %s.local)", path, ErrForeignHook, hook)
+		}
+		if err := os.Rename(path, path+".local"); err != nil {
+			return false, err
+		}
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return false, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	return false, os.WriteFile(path, []byte(script), 0755)
+}
+
+// Commit is a commit linked to a task
+type Commit struct {
+	Hash    string
+	Subject string
+	TaskIDs []string // values of the Ramorie-Task trailers
+}
+
+// Short is the abbreviated hash used in annotations
+func (c Commit) Short() string {
+	if len(c.Hash) > 12 {
+		return c.Hash[:12]
+	}
+	return c.Hash
+}
+
+// Annotation is the task annotation recording the commit
+func (c Commit) Annotation() string {
+	return fmt.Sprintf("Commit %s: %s", c.Short(), c.Subject)
+}
+
+// Annotated reports whether one of the annotations already records the commit
+func (c Commit) Annotated(annotations []string) bool {
+	prefix := "Commit " + c.Short()
+	for _, a := range annotations {
+		if strings.HasPrefix(strings.TrimSpace(a), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// logFormat separates fields with US and commits with RS
+const logFormat = "%H%x1f%s%x1f%(trailers:key=" + TrailerKey + ",valueonly,separator=%x2c)%x1e"
+
+// Log returns the commits carrying a Ramorie-Task trailer, newest first.
+// args are passed on to git log (a revision range, --since, --all, ...).
+func Log(dir string, args ...string) ([]Commit, error) {
+	out, err := Git(dir, append([]string{"log", "--format=" + logFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLog(out), nil
+}
+
+// ParseLog parses git log output in logFormat, keeping commits with trailers
+func ParseLog(out string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Subject: fields[1]}
+		for _, id := range strings.Split(fields[2], ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				c.TaskIDs = append(c.TaskIDs, id)
+			}
+		}
+		if len(c.TaskIDs) > 0 {
+			commits = append(commits, c)
+		}
+	}
+	return commits
+}
+
+// AddTrailer adds the Ramorie-Task trailer to the commit message file unless
+// the message already has one
+func AddTrailer(dir, file, taskID string) error {
+	_, err := Git(dir, "interpret-trailers", "--in-place", "--if-exists", "doNothing",
+		"--trailer", TrailerKey+": "+taskID, file)
+	return err
+}
+
+// BranchName makes a branch name from a task: <prefix><short-id>-<title-slug>
+func BranchName(prefix, taskID, title string) string {
+	if len(taskID) > 8 {
+		taskID = taskID[:8]
+	}
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if dash && b.Len() > 0 {
+				// Cut long titles between words
+				if b.Len() >= 40 {
+					break
+				}
+				b.WriteByte('-')
+			}
+			if b.Len() < 60 {
+				b.WriteRune(r)
+			}
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	name := prefix + taskID
+	if b.Len() > 0 {
+		name += "-" + b.String()
+	}
+	return name
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("/._-+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
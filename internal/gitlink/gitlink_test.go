@@ -0,0 +1,102 @@
+package gitlink
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	out := "c0ffee00c0ffee00\x1fFix ingress\x1faaaaaaa1\x1e\n" +
+		"deadbeefdeadbeef\x1fNo task\x1f\x1e\n" +
+		"0123456789abcdef\x1fShared work\x1faaaaaaa1,bbbbbbb2\x1e\n"
+	commits := ParseLog(out)
+	if len(commits) != 2 {
+		t.Fatalf("commits = %+v", commits)
+	}
+	if commits[1].Short() != "0123456789ab" || strings.Join(commits[1].TaskIDs, " ") != "aaaaaaa1 bbbbbbb2" {
+		t.Errorf("commit = %+v", commits[1])
+	}
+	if a := commits[0].Annotation(); a != "Commit c0ffee00c0ff: Fix ingress" {
+		t.Errorf("annotation = %q", a)
+	}
+	if !commits[0].Annotated([]string{"note", commits[0].Annotation()}) || commits[1].Annotated([]string{commits[0].Annotation()}) {
+		t.Error("Annotated")
+	}
+}
+
+func TestBranchName(t *testing.T) {
+	for _, tc := range []struct{ prefix, title, want string }{
+		{"", "Configure traefik ingress", "aaaaaaa1-configure-traefik-ingress"},
+		{"feat/", "Fix: über-slow  /login (again!)", "feat/aaaaaaa1-fix-ber-slow-login-again"},
+		{"", "日本語", "aaaaaaa1"},
+		{"", strings.Repeat("word ", 20), "aaaaaaa1-word-word-word-word-word-word-word-word-word"},
+	} {
+		if got := BranchName(tc.prefix, "aaaaaaa1-0000-4000-8000-000000000001", tc.title); got != tc.want {
+			t.Errorf("BranchName(%q) = %q, want %q", tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	dir := t.TempDir()
+	if unchanged, err := InstallHook(dir, "post-commit", "/opt/my tools/ramorie", false); err != nil || unchanged {
+		t.Fatalf("install = %v, %v", unchanged, err)
+	}
+	script, _ := os.ReadFile(filepath.Join(dir, "post-commit"))
+	if !strings.Contains(string(script), "'/opt/my tools/ramorie' git hook post-commit \"$@\" || true") {
+		t.Errorf("script:\n%s", script)
+	}
+	if unchanged, err := InstallHook(dir, "post-commit", "/opt/my tools/ramorie", false); err != nil || !unchanged {
+		t.Errorf("reinstall = %v, %v", unchanged, err)
+	}
+
+	foreign := filepath.Join(dir, "prepare-commit-msg")
+	os.WriteFile(foreign, []byte("#!/bin/sh\nexit 0\n"), 0755)
+	if _, err := InstallHook(dir, "prepare-commit-msg", "ramorie", false); !errors.Is(err, ErrForeignHook) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := InstallHook(dir, "prepare-commit-msg", "ramorie", true); err != nil {
+		t.Fatal(err)
+	}
+	if kept, _ := os.ReadFile(foreign + ".local"); string(kept) != "#!/bin/sh\nexit 0\n" {
+		t.Errorf("replaced hook not kept: %q", kept)
+	}
+}
+
+func TestAddTrailerAndLog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if _, err := Git(dir, append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	msg := filepath.Join(dir, "MSG")
+	os.WriteFile(msg, []byte("Fix ingress\n\nLonger body.\n"), 0644)
+	for i := 0; i < 2; i++ { // the second call must not add another trailer
+		if err := AddTrailer(dir, msg, "aaaaaaa1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := os.ReadFile(msg); string(got) != "Fix ingress\n\nLonger body.\n\nRamorie-Task: aaaaaaa1\n" {
+		t.Errorf("message = %q", got)
+	}
+	git("commit", "-q", "--allow-empty", "-F", msg)
+	git("commit", "-q", "--allow-empty", "-m", "Unrelated")
+
+	commits, err := Log(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "Fix ingress" || commits[0].TaskIDs[0] != "aaaaaaa1" {
+		t.Errorf("commits = %+v", commits)
+	}
+}
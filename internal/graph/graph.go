@@ -0,0 +1,291 @@
+// Package graph describes the knowledge graph of memories, tasks and
+// decisions: the typed memory-to-memory relations, and writers that export
+// the graph as Graphviz DOT, Mermaid or GraphML.
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Memory-to-memory relation types
+const (
+	Supersedes  = "supersedes"
+	Contradicts = "contradicts"
+	Elaborates  = "elaborates"
+	SeeAlso     = "see-also"
+)
+
+// Relations are the supported memory relation types
+var Relations = []string{Supersedes, Contradicts, Elaborates, SeeAlso}
+
+// ParseRelation normalizes a relation type; empty means see-also
+func ParseRelation(s string) (string, error) {
+	switch strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToLower(strings.TrimSpace(s))) {
+	case "", "see-also", "seealso", "related":
+		return SeeAlso, nil
+	case "supersedes", "supersede", "replaces":
+		return Supersedes, nil
+	case "contradicts", "contradict", "conflicts":
+		return Contradicts, nil
+	case "elaborates", "elaborate", "details":
+		return Elaborates, nil
+	}
+	return "", fmt.Errorf("unknown relation type %q (use %s)", s, strings.Join(Relations, ", "))
+}
+
+// Inverse is how a relation reads from its target, e.g. "superseded by"
+func Inverse(relation string) string {
+	switch relation {
+	case Supersedes:
+		return "superseded by"
+	case Elaborates:
+		return "elaborated by"
+	default:
+		// contradicts and see-also are symmetric
+		return relation
+	}
+}
+
+// Neighbour is a memory one relation away from another
+type Neighbour struct {
+	ID       uuid.UUID // the other memory
+	LinkID   uuid.UUID
+	Relation string // read from the first memory, e.g. "superseded by"
+	Outgoing bool
+}
+
+// Neighbours returns the memories linked to id, in link order
+func Neighbours(links []models.MemoryLink, id uuid.UUID) []Neighbour {
+	var out []Neighbour
+	for _, l := range links {
+		switch id {
+		case l.MemoryID:
+			out = append(out, Neighbour{ID: l.TargetMemoryID, LinkID: l.ID, Relation: l.RelationType, Outgoing: true})
+		case l.TargetMemoryID:
+			out = append(out, Neighbour{ID: l.MemoryID, LinkID: l.ID, Relation: Inverse(l.RelationType)})
+		}
+	}
+	return out
+}
+
+// Node kinds
+const (
+	KindMemory   = "memory"
+	KindTask     = "task"
+	KindDecision = "decision"
+)
+
+// Node is a memory, task or decision
+type Node struct {
+	ID    string
+	Kind  string
+	Label string
+	Group string // project name, used for clusters
+}
+
+// Edge is a labelled relation between two nodes
+type Edge struct {
+	From, To string
+	Label    string
+}
+
+// Graph is a set of nodes and the edges between them
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+
+	index map[string]bool
+}
+
+// AddNode adds n unless a node with its ID exists
+func (g *Graph) AddNode(n Node) {
+	if g.index == nil {
+		g.index = map[string]bool{}
+	}
+	if g.index[n.ID] {
+		return
+	}
+	g.index[n.ID] = true
+	g.Nodes = append(g.Nodes, n)
+}
+
+// Has reports whether the graph has a node with the given ID
+func (g *Graph) Has(id string) bool {
+	return g.index[id]
+}
+
+// AddEdge adds an edge between existing nodes; edges to unknown nodes
+// (e.g. memories of other projects) are dropped
+func (g *Graph) AddEdge(e Edge) {
+	if g.Has(e.From) && g.Has(e.To) {
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// Formats are the supported export formats
+var Formats = []string{"dot", "mermaid", "graphml"}
+
+// Write exports g in the given format
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "dot", "graphviz":
+		return g.WriteDOT(w)
+	case "mermaid", "mmd":
+		return g.WriteMermaid(w)
+	case "graphml", "xml":
+		return g.WriteGraphML(w)
+	}
+	return fmt.Errorf("unknown graph format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+var dotShapes = map[string]string{KindMemory: "note", KindTask: "box", KindDecision: "hexagon"}
+
+// WriteDOT writes a Graphviz digraph with one cluster per project
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph ramorie {\n  rankdir=LR;\n  node [fontname=\"Helvetica\", fontsize=10];\n  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	for i, group := range g.groups() {
+		indent := "  "
+		if group != "" {
+			fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%s;\n", i, dotQuote(group))
+			indent = "    "
+		}
+		for _, n := range g.Nodes {
+			if n.Group == group {
+				fmt.Fprintf(&b, "%s%s [label=%s, shape=%s];\n", indent, dotQuote(n.ID), dotQuote(n.Label), dotShapes[n.Kind])
+			}
+		}
+		if group != "" {
+			b.WriteString("  }\n")
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// WriteMermaid writes a Mermaid flowchart with one subgraph per project
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i+1)
+	}
+	shapes := map[string][2]string{KindMemory: {"[", "]"}, KindTask: {"([", "])"}, KindDecision: {"{{", "}}"}}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, group := range g.groups() {
+		indent := "  "
+		if group != "" {
+			fmt.Fprintf(&b, "  subgraph g%d[%s]\n", i, mermaidQuote(group))
+			indent = "    "
+		}
+		for _, n := range g.Nodes {
+			if n.Group == group {
+				s := shapes[n.Kind]
+				fmt.Fprintf(&b, "%s%s%s%s%s\n", indent, ids[n.ID], s[0], mermaidQuote(n.Label), s[1])
+			}
+		}
+		if group != "" {
+			b.WriteString("  end\n")
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], mermaidQuote(e.Label), ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ", "|", "#124;").Replace(s) + `"`
+}
+
+// WriteGraphML writes a GraphML document with kind, label and project node data
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type node struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	type graphML struct {
+		XMLName xml.Name `xml:"graphml"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   struct {
+			ID          string `xml:"id,attr"`
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []node `xml:"node"`
+			Edges       []edge `xml:"edge"`
+		} `xml:"graph"`
+	}
+
+	doc := graphML{XMLNS: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []key{
+		{"kind", "node", "kind", "string"},
+		{"label", "node", "label", "string"},
+		{"project", "node", "project", "string"},
+		{"relation", "edge", "relation", "string"},
+	}
+	doc.Graph.ID = "ramorie"
+	doc.Graph.EdgeDefault = "directed"
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{ID: n.ID, Data: []data{{"kind", n.Kind}, {"label", n.Label}, {"project", n.Group}}})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{Source: e.From, Target: e.To, Data: []data{{"relation", e.Label}}})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// groups returns the node groups in order, the ungrouped ("") first
+func (g *Graph) groups() []string {
+	seen := map[string]bool{}
+	var groups []string
+	for _, n := range g.Nodes {
+		if !seen[n.Group] {
+			seen[n.Group] = true
+			groups = append(groups, n.Group)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i] == "" && groups[j] != "" })
+	return groups
+}
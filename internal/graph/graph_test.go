@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func sample() *Graph {
+	g := &Graph{}
+	g.AddNode(Node{ID: "m1", Kind: KindMemory, Label: `Traefik "labels"`, Group: "orion"})
+	g.AddNode(Node{ID: "m2", Kind: KindMemory, Label: "Traefik v3", Group: "orion"})
+	g.AddNode(Node{ID: "t1", Kind: KindTask, Label: "Configure ingress", Group: "orion"})
+	g.AddNode(Node{ID: "d1", Kind: KindDecision, Label: "ADR-001 Use Traefik"})
+	g.AddNode(Node{ID: "m1", Kind: KindMemory, Label: "duplicate"})
+	g.AddEdge(Edge{From: "m2", To: "m1", Label: Supersedes})
+	g.AddEdge(Edge{From: "t1", To: "m2", Label: "related"})
+	g.AddEdge(Edge{From: "m1", To: "elsewhere", Label: SeeAlso})
+	return g
+}
+
+func TestParseRelation(t *testing.T) {
+	for in, want := range map[string]string{"": SeeAlso, "See_Also": SeeAlso, "replaces": Supersedes, "contradicts": Contradicts} {
+		if got, err := ParseRelation(in); err != nil || got != want {
+			t.Errorf("ParseRelation(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseRelation("blocks"); err == nil {
+		t.Error("ParseRelation(blocks) succeeded")
+	}
+}
+
+func TestWriters(t *testing.T) {
+	g := sample()
+	if len(g.Nodes) != 4 || len(g.Edges) != 2 {
+		t.Fatalf("graph has %d nodes, %d edges; want 4, 2", len(g.Nodes), len(g.Edges))
+	}
+
+	tests := map[string][]string{
+		"dot": {
+			`"d1" [label="ADR-001 Use Traefik", shape=hexagon];`,
+			"subgraph cluster_1 {\n    label=\"orion\";\n    \"m1\" [label=\"Traefik \\\"labels\\\"\", shape=note];",
+			`"m2" -> "m1" [label="supersedes"];`,
+		},
+		"mermaid": {
+			"flowchart LR\n  n4{{\"ADR-001 Use Traefik\"}}\n  subgraph g1[\"orion\"]\n    n1[\"Traefik #quot;labels#quot;\"]",
+			`n3(["Configure ingress"])`,
+			`n2 -->|"supersedes"| n1`,
+		},
+		"graphml": {
+			`<graph id="ramorie" edgedefault="directed">`,
+			`<node id="t1">`,
+			`<data key="kind">task</data>`,
+			`<edge source="m2" target="m1">`,
+		},
+	}
+	for format, wants := range tests {
+		var b strings.Builder
+		if err := g.Write(&b, format); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%s output missing %q:\n%s", format, want, b.String())
+			}
+		}
+	}
+	if err := g.Write(&strings.Builder{}, "svg"); err == nil {
+		t.Error("Write(svg) succeeded")
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	a, b, c := uuid.UUID{1}, uuid.UUID{2}, uuid.UUID{3}
+	links := []models.MemoryLink{
+		{MemoryID: b, TargetMemoryID: a, RelationType: Supersedes},
+		{MemoryID: a, TargetMemoryID: c, RelationType: Elaborates},
+		{MemoryID: b, TargetMemoryID: c, RelationType: SeeAlso},
+	}
+	got := Neighbours(links, a)
+	if len(got) != 2 ||
+		got[0].ID != b || got[0].Relation != "superseded by" || got[0].Outgoing ||
+		got[1].ID != c || got[1].Relation != Elaborates || !got[1].Outgoing {
+		t.Errorf("Neighbours = %+v", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package ical writes tasks and work sessions as an iCalendar (RFC 5545)
+// feed: a VTODO per task and a VEVENT per work session.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Todo is a task as a VTODO
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	Status      string // ramorie status: TODO, IN_PROGRESS, IN_REVIEW, COMPLETED
+	Priority    string // H, M or L
+	Categories  []string
+	Created     time.Time
+	Modified    time.Time
+	Due         *time.Time
+	Completed   *time.Time
+}
+
+// Event is a work session as a VEVENT
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	Start, End  time.Time
+}
+
+// Calendar is a feed of todos and events
+type Calendar struct {
+	Name   string
+	Todos  []Todo
+	Events []Event
+}
+
+// Priority maps H/M/L onto iCalendar PRIORITY, where 1 is highest and 9 lowest
+func Priority(p string) int {
+	switch strings.ToUpper(p) {
+	case "H", "HIGH":
+		return 1
+	case "L", "LOW":
+		return 9
+	}
+	return 5
+}
+
+// Status maps a task status onto the VTODO STATUS values
+func Status(s string) string {
+	switch s {
+	case "COMPLETED":
+		return "COMPLETED"
+	case "IN_PROGRESS", "IN_REVIEW":
+		return "IN-PROCESS"
+	}
+	return "NEEDS-ACTION"
+}
+
+// Encode writes the calendar; stamp is the DTSTAMP of every component
+func (c Calendar) Encode(w io.Writer, stamp time.Time) error {
+	e := &encoder{w: w}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//ramorie//ramorie CLI//EN")
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		e.prop("X-WR-CALNAME", c.Name)
+	}
+	for _, t := range c.Todos {
+		e.line("BEGIN:VTODO")
+		e.line("UID:" + escape(t.UID))
+		e.line("DTSTAMP:" + utc(stamp))
+		e.prop("SUMMARY", t.Summary)
+		if strings.TrimSpace(t.Description) != "" {
+			e.prop("DESCRIPTION", t.Description)
+		}
+		if !t.Created.IsZero() {
+			e.line("CREATED:" + utc(t.Created))
+		}
+		if !t.Modified.IsZero() {
+			e.line("LAST-MODIFIED:" + utc(t.Modified))
+		}
+		if t.Due != nil {
+			e.line("DUE:" + utc(*t.Due))
+		}
+		e.line(fmt.Sprintf("PRIORITY:%d", Priority(t.Priority)))
+		e.line("STATUS:" + Status(t.Status))
+		if t.Status == "COMPLETED" {
+			e.line("PERCENT-COMPLETE:100")
+			if t.Completed != nil {
+				e.line("COMPLETED:" + utc(*t.Completed))
+			}
+		}
+		e.categories(t.Categories)
+		e.line("END:VTODO")
+	}
+	for _, ev := range c.Events {
+		e.line("BEGIN:VEVENT")
+		e.line("UID:" + escape(ev.UID))
+		e.line("DTSTAMP:" + utc(stamp))
+		e.line("DTSTART:" + utc(ev.Start))
+		e.line("DTEND:" + utc(ev.End))
+		e.prop("SUMMARY", ev.Summary)
+		if strings.TrimSpace(ev.Description) != "" {
+			e.prop("DESCRIPTION", ev.Description)
+		}
+		e.line("TRANSP:OPAQUE")
+		e.categories(ev.Categories)
+		e.line("END:VEVENT")
+	}
+	e.line("END:VCALENDAR")
+	return e.err
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line folded at 75 octets, as RFC 5545 requires
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := utf8.RuneLen(r)
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	b.WriteString("\r\n")
+	_, e.err = io.WriteString(e.w, b.String())
+}
+
+func (e *encoder) prop(name, value string) {
+	e.line(name + ":" + escape(value))
+}
+
+func (e *encoder) categories(values []string) {
+	if len(values) == 0 {
+		return
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escape(v)
+	}
+	e.line("CATEGORIES:" + strings.Join(escaped, ","))
+}
+
+// escape quotes TEXT values
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
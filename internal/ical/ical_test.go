@@ -0,0 +1,57 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC)
+	done := time.Date(2025, 3, 8, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	cal := Calendar{
+		Name: "ramorie",
+		Todos: []Todo{
+			{UID: "a@ramorie", Summary: "Ship v2, finally; really", Description: "line one\nline two", Status: "IN_PROGRESS", Priority: "H", Due: &due, Categories: []string{"orion", "release"}},
+			{UID: "b@ramorie", Summary: strings.Repeat("ü", 60), Status: "COMPLETED", Priority: "L", Completed: &done},
+		},
+		Events: []Event{{UID: "s1@ramorie", Summary: "Ship v2", Start: due.Add(-2 * time.Hour), End: due.Add(-time.Hour)}},
+	}
+	var b bytes.Buffer
+	if err := cal.Encode(&b, due); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"SUMMARY:Ship v2\\, finally\\; really\r\n",
+		"DESCRIPTION:line one\\nline two\r\n",
+		"DUE:20250310T170000Z\r\n", "PRIORITY:1\r\n", "STATUS:IN-PROCESS\r\n",
+		"CATEGORIES:orion,release\r\n",
+		"PRIORITY:9\r\nSTATUS:COMPLETED\r\nPERCENT-COMPLETE:100\r\nCOMPLETED:20250308T083000Z\r\n",
+		"BEGIN:VEVENT\r\nUID:s1@ramorie\r\nDTSTAMP:20250310T170000Z\r\nDTSTART:20250310T150000Z\r\nDTEND:20250310T160000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(out, "\r\n ü") {
+		t.Error("long summary should be folded between characters")
+	}
+}
+
+func TestMappings(t *testing.T) {
+	if Priority("M") != 5 || Priority("") != 5 || Priority("high") != 1 {
+		t.Error("Priority")
+	}
+	if Status("TODO") != "NEEDS-ACTION" || Status("IN_REVIEW") != "IN-PROCESS" {
+		t.Error("Status")
+	}
+}
@@ -0,0 +1,372 @@
+// Package issues reads issue tracker exports (GitHub issues JSON, GitLab and
+// Jira CSV) so they can be imported as tasks without network access.
+package issues
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Sources are the supported export formats
+var Sources = []string{"github", "gitlab", "jira"}
+
+// Comment is a comment on an issue
+type Comment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// Issue is a tracker issue mapped onto task fields
+type Issue struct {
+	Ref       string // URL, or the issue key where the export has no URL
+	Title     string
+	Body      string
+	Status    string // TODO, IN_PROGRESS, IN_REVIEW or COMPLETED
+	Priority  string // H, M or L
+	Labels    []string
+	Comments  []Comment
+	CreatedAt time.Time
+}
+
+// Parse reads an export of the given source
+func Parse(source string, r io.Reader) ([]Issue, error) {
+	switch strings.ToLower(source) {
+	case "github":
+		return parseGitHub(r)
+	case "gitlab":
+		return parseGitLab(r)
+	case "jira":
+		return parseJira(r)
+	}
+	return nil, fmt.Errorf("unknown source '%s' (use %s)", source, strings.Join(Sources, ", "))
+}
+
+// sourcePrefix marks the description line that records where a task came from
+const sourcePrefix = "Source: "
+
+var sourceLine = regexp.MustCompile(`(?m)^` + sourcePrefix + `(\S+)\s*$`)
+
+// Description is the task description for an issue: the body followed by a
+// Source line that later imports use to find the task again
+func (i Issue) Description() string {
+	body := strings.TrimSpace(i.Body)
+	if body == "" {
+		return sourcePrefix + i.Ref
+	}
+	return body + "\n\n" + sourcePrefix + i.Ref
+}
+
+// SourceRef is the issue a task description was imported from, or ""
+func SourceRef(description string) string {
+	m := sourceLine.FindAllStringSubmatch(description, -1)
+	if len(m) == 0 {
+		return ""
+	}
+	return m[len(m)-1][1]
+}
+
+// Annotation is the text a comment is stored as on the task
+func (c Comment) Annotation() string {
+	body := strings.TrimSpace(c.Body)
+	switch {
+	case c.Author != "" && !c.CreatedAt.IsZero():
+		return fmt.Sprintf("@%s (%s): %s", c.Author, c.CreatedAt.UTC().Format("2006-01-02"), body)
+	case c.Author != "":
+		return fmt.Sprintf("@%s: %s", c.Author, body)
+	}
+	return body
+}
+
+// --- GitHub ---
+
+// githubIssue covers both `gh issue list --json ...` and the REST API
+type githubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Comments  json.RawMessage `json:"comments"` // a list from gh, a count from the REST API
+	CreatedAt time.Time       `json:"createdAt"`
+	Created   time.Time       `json:"created_at"`
+	// The REST API lists pull requests among issues
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type githubComment struct {
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	Created   time.Time `json:"created_at"`
+}
+
+func parseGitHub(r io.Reader) ([]Issue, error) {
+	var raw []githubIssue
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array as written by `gh issue list --json`: %w", err)
+	}
+	var out []Issue
+	for _, g := range raw {
+		if len(g.PullRequest) > 0 && string(g.PullRequest) != "null" {
+			continue
+		}
+		issue := Issue{
+			Ref:       firstNonEmpty(g.URL, g.HTMLURL),
+			Title:     strings.TrimSpace(g.Title),
+			Body:      g.Body,
+			Status:    "TODO",
+			CreatedAt: firstTime(g.CreatedAt, g.Created),
+		}
+		if issue.Ref == "" && g.Number > 0 {
+			issue.Ref = fmt.Sprintf("#%d", g.Number)
+		}
+		if strings.EqualFold(g.State, "closed") {
+			issue.Status = "COMPLETED"
+		}
+		for _, l := range g.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+		}
+		issue.Priority = labelPriority(issue.Labels)
+		var comments []githubComment
+		if len(g.Comments) > 0 && g.Comments[0] == '[' {
+			if err := json.Unmarshal(g.Comments, &comments); err != nil {
+				return nil, fmt.Errorf("issue %s: %w", issue.Ref, err)
+			}
+		}
+		for _, c := range comments {
+			author := c.Author.Login
+			if c.User != nil && author == "" {
+				author = c.User.Login
+			}
+			issue.Comments = append(issue.Comments, Comment{Author: author, Body: c.Body, CreatedAt: firstTime(c.CreatedAt, c.Created)})
+		}
+		out = append(out, issue)
+	}
+	return out, nil
+}
+
+var priorityLabel = regexp.MustCompile(`(?i)^(?:priority|prio|p)?[\s:/_-]*(p?[0-4]|critical|urgent|highest|high|medium|normal|low|lowest|minor)$`)
+
+// labelPriority reads priority labels such as "priority: high" or "P1"
+func labelPriority(labels []string) string {
+	for _, l := range labels {
+		if m := priorityLabel.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+			return namedPriority(strings.TrimPrefix(strings.ToLower(m[1]), "p"))
+		}
+	}
+	return "M"
+}
+
+// namedPriority maps tracker priority names and levels to H, M or L
+func namedPriority(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "0", "1", "critical", "urgent", "highest", "high", "blocker", "major":
+		return "H"
+	case "3", "4", "low", "lowest", "minor", "trivial":
+		return "L"
+	}
+	return "M"
+}
+
+// --- GitLab ---
+
+func parseGitLab(r io.Reader) ([]Issue, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	var out []Issue
+	for _, row := range rows {
+		issue := Issue{
+			Ref:       firstNonEmpty(row.get("url"), row.get("issue id")),
+			Title:     strings.TrimSpace(row.get("title")),
+			Body:      row.get("description"),
+			Status:    "TODO",
+			CreatedAt: parseTime(row.get("created at (utc)")),
+		}
+		if strings.EqualFold(row.get("state"), "closed") {
+			issue.Status = "COMPLETED"
+		}
+		issue.Labels = splitList(row.get("labels"))
+		issue.Priority = labelPriority(issue.Labels)
+		out = append(out, issue)
+	}
+	return out, nil
+}
+
+// --- Jira ---
+
+func parseJira(r io.Reader) ([]Issue, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	var out []Issue
+	for _, row := range rows {
+		issue := Issue{
+			Ref:       row.get("issue key"),
+			Title:     strings.TrimSpace(row.get("summary")),
+			Body:      row.get("description"),
+			Priority:  namedPriority(row.get("priority")),
+			CreatedAt: parseTime(row.get("created")),
+		}
+		// Workflows name statuses freely; fall back to the status category
+		if issue.Status = jiraStatus(row.get("status")); issue.Status == "TODO" {
+			issue.Status = jiraStatus(row.get("status category"))
+		}
+		for _, l := range row.all("labels") {
+			issue.Labels = append(issue.Labels, strings.Fields(l)...)
+		}
+		for _, c := range row.all("comment") {
+			issue.Comments = append(issue.Comments, jiraComment(c))
+		}
+		out = append(out, issue)
+	}
+	return out, nil
+}
+
+func jiraStatus(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "done", "closed", "resolved", "complete", "completed":
+		return "COMPLETED"
+	case "in progress", "in development", "doing":
+		return "IN_PROGRESS"
+	case "in review", "review", "code review", "in qa", "testing":
+		return "IN_REVIEW"
+	}
+	return "TODO"
+}
+
+// jiraComment splits the "date;author;body" cells of a Jira CSV export
+func jiraComment(cell string) Comment {
+	parts := strings.SplitN(cell, ";", 3)
+	if len(parts) == 3 {
+		if at := parseTime(parts[0]); !at.IsZero() {
+			return Comment{CreatedAt: at, Author: strings.TrimSpace(parts[1]), Body: parts[2]}
+		}
+	}
+	return Comment{Body: cell}
+}
+
+// --- CSV helpers ---
+
+// csvRow holds every cell of a row by lowercased header; Jira repeats headers
+// such as Labels and Comment once per value
+type csvRow map[string][]string
+
+func (r csvRow) get(name string) string {
+	for _, v := range r[name] {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func (r csvRow) all(name string) []string {
+	var out []string
+	for _, v := range r[name] {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func readCSV(r io.Reader) ([]csvRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	var rows []csvRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := csvRow{}
+		for i, v := range record {
+			if i < len(header) {
+				row[header[i]] = append(row[header[i]], v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// timeLayouts are the dates found in tracker exports
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"2006-01-02",
+}
+
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
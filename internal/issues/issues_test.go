@@ -0,0 +1,82 @@
+package issues
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGitHub(t *testing.T) {
+	data := `[
+	{"number":12,"title":"Login fails","body":"Steps:\n1. open","state":"OPEN","url":"https://github.com/acme/app/issues/12",
+	 "labels":[{"name":"bug"},{"name":"priority: high"}],"createdAt":"2025-03-01T09:00:00Z",
+	 "comments":[{"author":{"login":"ana"},"body":"Seen on iOS too","createdAt":"2025-03-02T10:00:00Z"}]},
+	{"number":13,"title":"Old","body":"","state":"closed","html_url":"https://github.com/acme/app/issues/13","labels":[],"comments":0},
+	{"number":14,"title":"A PR","state":"open","html_url":"https://github.com/acme/app/pull/14","pull_request":{"url":"x"}}
+	]`
+	got, err := Parse("github", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d issues, pull requests should be skipped", len(got))
+	}
+	login := got[0]
+	if login.Ref != "https://github.com/acme/app/issues/12" || login.Priority != "H" || login.Status != "TODO" ||
+		strings.Join(login.Labels, ",") != "bug,priority: high" || len(login.Comments) != 1 {
+		t.Errorf("login = %+v", login)
+	}
+	if a := login.Comments[0].Annotation(); a != "@ana (2025-03-02): Seen on iOS too" {
+		t.Errorf("annotation = %q", a)
+	}
+	if got[1].Status != "COMPLETED" || got[1].Ref != "https://github.com/acme/app/issues/13" || got[1].Priority != "M" {
+		t.Errorf("old = %+v", got[1])
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	data := "Issue ID,URL,Title,State,Description,Author,Labels,Created At (UTC)\n" +
+		"7,https://gitlab.com/acme/app/-/issues/7,Crash on save,Closed,\"It crashes, badly\",bo,\"bug, P1\",2025-03-01 09:00:00\n"
+	got, err := Parse("gitlab", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Status != "COMPLETED" || got[0].Priority != "H" || got[0].Body != "It crashes, badly" ||
+		strings.Join(got[0].Labels, "|") != "bug|P1" || got[0].CreatedAt.IsZero() {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestParseJira(t *testing.T) {
+	data := "Summary,Issue key,Status,Status Category,Priority,Description,Labels,Labels,Comment,Comment,Created\n" +
+		"Add SSO,OPS-42,QA Ready,In Progress,Highest,Use SAML,auth,security,\"01/Mar/25 9:00 AM;557058:ana;Started\",,02/Mar/25 10:15 AM\n" +
+		"Tidy docs,OPS-43,Code Review,In Progress,Low,,,,,,\n"
+	got, err := Parse("jira", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	sso := got[0]
+	if sso.Ref != "OPS-42" || sso.Status != "IN_PROGRESS" || sso.Priority != "H" || strings.Join(sso.Labels, ",") != "auth,security" ||
+		len(sso.Comments) != 1 || sso.Comments[0].Author != "557058:ana" || sso.Comments[0].Body != "Started" || sso.CreatedAt.IsZero() {
+		t.Errorf("sso = %+v", sso)
+	}
+	if got[1].Status != "IN_REVIEW" || got[1].Priority != "L" {
+		t.Errorf("docs = %+v", got[1])
+	}
+	if _, err := Parse("trello", strings.NewReader("")); err == nil {
+		t.Error("unknown source should fail")
+	}
+}
+
+func TestSourceRef(t *testing.T) {
+	i := Issue{Ref: "OPS-42", Body: "Use SAML\nSource: not-this"}
+	d := i.Description()
+	if d != "Use SAML\nSource: not-this\n\nSource: OPS-42" || SourceRef(d) != "OPS-42" {
+		t.Errorf("description %q -> %q", d, SourceRef(d))
+	}
+	if SourceRef("no source here") != "" || SourceRef((Issue{Ref: "x"}).Description()) != "x" {
+		t.Error("SourceRef")
+	}
+}
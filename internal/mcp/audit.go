@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/terzigolu/josepshbrain-go/internal/audit"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
+)
+
+// auditMiddleware records every tools/call request in the audit log.
+// Other methods (initialize, tools/list, ...) pass through untouched. Inputs
+// and errors are redacted with scanner (the default rules when nil).
+func auditMiddleware(logger *audit.Logger, scanner *secrets.Scanner) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+
+			rec := audit.Record{
+				Time:      start.UTC(),
+				Tool:      callReq.Params.Name,
+				Input:     audit.Redact(callReq.Params.Arguments, scanner),
+				Outcome:   "ok",
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if callReq.Session != nil {
+				rec.SessionID = callReq.Session.ID()
+				if params := callReq.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+					rec.Client = audit.Client{Name: params.ClientInfo.Name, Version: params.ClientInfo.Version}
+				}
+			}
+
+			var output interface{}
+			if err != nil {
+				rec.Outcome = "error"
+				rec.Error = audit.RedactText(err.Error(), scanner)
+			} else if res, ok := result.(*mcp.CallToolResult); ok && res != nil {
+				output = res.StructuredContent
+				if res.IsError {
+					rec.Outcome = "error"
+					rec.Error = audit.RedactText(toolErrorText(res), scanner)
+				}
+			}
+			rec.EntityIDs = audit.EntityIDs(callReq.Params.Arguments, output)
+
+			// Auditing must never break a tool call
+			_ = logger.Append(rec)
+
+			return result, err
+		}
+	}
+}
+
+// toolErrorText extracts the error message the SDK placed in the result content
+func toolErrorText(res *mcp.CallToolResult) string {
+	for _, c := range res.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			return t.Text
+		}
+	}
+	return "tool error"
+}
@@ -0,0 +1,395 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// journal keeps a per-session record of tool calls and the entities they touched,
+// so end_session can write a summary even when the agent forgets to.
+type journal struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionJournal
+
+	// watched holds the sessions whose close is already being awaited
+	watched map[string]bool
+}
+
+// sessionJournal is the activity of one MCP session since its last end_session
+type sessionJournal struct {
+	Client  string
+	Started time.Time
+
+	Calls  int
+	Errors int
+
+	// Tasks in the order they were first touched
+	Tasks     []*taskActivity
+	Memories  []memoryActivity
+	Decisions []string
+}
+
+type taskActivity struct {
+	ID      string
+	Title   string
+	Actions []string
+
+	// Active time is measured from start_task to complete_task/stop_task
+	startedAt time.Time
+	Active    time.Duration
+}
+
+type memoryActivity struct {
+	ID      string
+	Preview string
+}
+
+func newJournal() *journal {
+	return &journal{sessions: map[string]*sessionJournal{}, watched: map[string]bool{}}
+}
+
+func sessionID(ss *mcp.ServerSession) string {
+	if ss == nil {
+		return ""
+	}
+	return ss.ID()
+}
+
+// session returns the journal for a session, creating it on first use
+func (j *journal) session(ss *mcp.ServerSession) *sessionJournal {
+	id := sessionID(ss)
+	sj, ok := j.sessions[id]
+	if !ok {
+		sj = &sessionJournal{Client: clientName(ss), Started: time.Now()}
+		j.sessions[id] = sj
+	}
+	return sj
+}
+
+// take removes and returns a session's journal, so the next call starts a fresh one
+func (j *journal) take(ss *mcp.ServerSession) *sessionJournal {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	sj := j.session(ss)
+	delete(j.sessions, sessionID(ss))
+	return sj
+}
+
+// clientName attributes a session to the MCP client that opened it
+func clientName(ss *mcp.ServerSession) string {
+	if ss != nil {
+		if params := ss.InitializeParams(); params != nil && params.ClientInfo != nil {
+			name := params.ClientInfo.Name
+			if params.ClientInfo.Version != "" {
+				name += " " + params.ClientInfo.Version
+			}
+			return name
+		}
+	}
+	return "unknown client"
+}
+
+// record adds a completed tool call to the session journal
+func (j *journal) record(ss *mcp.ServerSession, tool string, args json.RawMessage, output interface{}, failed bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sj := j.session(ss)
+	sj.Calls++
+	if failed {
+		sj.Errors++
+		return
+	}
+
+	in := map[string]interface{}{}
+	_ = json.Unmarshal(args, &in)
+	out := map[string]interface{}{}
+	if b, err := json.Marshal(output); err == nil {
+		_ = json.Unmarshal(b, &out)
+	}
+
+	now := time.Now()
+	switch tool {
+	case "create_task":
+		t := sj.task(stringField(out, "id"))
+		t.Title = stringField(out, "title")
+		t.addAction("created")
+	case "start_task":
+		t := sj.task(stringField(in, "taskId"))
+		t.addAction("started")
+		t.startedAt = now
+	case "complete_task", "stop_task":
+		t := sj.task(stringField(in, "taskId"))
+		t.addAction(map[string]string{"complete_task": "completed", "stop_task": "stopped"}[tool])
+		if !t.startedAt.IsZero() {
+			t.Active += now.Sub(t.startedAt)
+			t.startedAt = time.Time{}
+		}
+	case "add_task_note":
+		sj.task(stringField(in, "taskId")).addAction("noted")
+	case "update_progress":
+		sj.task(stringField(in, "taskId")).addAction("progress updated")
+	case "add_memory":
+		// A duplicate check that found an existing memory stored nothing
+		if stringField(out, "id") == "" {
+			return
+		}
+		sj.Memories = append(sj.Memories, memoryActivity{
+			ID:      stringField(out, "id"),
+			Preview: truncate(stringField(out, "content"), 80),
+		})
+	case "create_decision":
+		title := stringField(out, "title")
+		if adr := stringField(out, "adr_number"); adr != "" {
+			title = adr + " " + title
+		}
+		sj.Decisions = append(sj.Decisions, title)
+	}
+}
+
+// task returns the activity entry for a task ID, matching short IDs by
+// prefix. An empty ID gets a detached entry that is never summarised, since
+// it would otherwise prefix-match the first task.
+func (sj *sessionJournal) task(id string) *taskActivity {
+	if id == "" {
+		return &taskActivity{}
+	}
+	for _, t := range sj.Tasks {
+		if strings.HasPrefix(t.ID, id) || strings.HasPrefix(id, t.ID) {
+			if len(id) > len(t.ID) {
+				t.ID = id
+			}
+			return t
+		}
+	}
+	t := &taskActivity{ID: id}
+	sj.Tasks = append(sj.Tasks, t)
+	return t
+}
+
+func (t *taskActivity) addAction(action string) {
+	for _, a := range t.Actions {
+		if a == action {
+			return
+		}
+	}
+	t.Actions = append(t.Actions, action)
+}
+
+// empty reports whether nothing worth summarizing happened
+func (sj *sessionJournal) empty() bool {
+	return len(sj.Tasks) == 0 && len(sj.Memories) == 0 && len(sj.Decisions) == 0
+}
+
+// markdown renders the journal as the annotation/memory body
+func (sj *sessionJournal) markdown(summary string, end time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🤖 Session summary — %s, %s\n", sj.Client, formatDuration(end.Sub(sj.Started))))
+	if summary != "" {
+		sb.WriteString("\n" + summary + "\n")
+	}
+
+	if len(sj.Tasks) > 0 {
+		sb.WriteString(fmt.Sprintf("\nTasks touched (%d):\n", len(sj.Tasks)))
+		for _, t := range sj.Tasks {
+			label := t.Title
+			if label == "" {
+				label = "(untitled)"
+			}
+			line := fmt.Sprintf("- %s [%s] — %s", label, shortID(t.ID), strings.Join(t.Actions, ", "))
+			active := t.Active
+			if !t.startedAt.IsZero() {
+				active += end.Sub(t.startedAt)
+			}
+			if active > 0 {
+				line += fmt.Sprintf(", %s active", formatDuration(active))
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	if len(sj.Memories) > 0 {
+		sb.WriteString(fmt.Sprintf("\nMemories added (%d):\n", len(sj.Memories)))
+		for _, m := range sj.Memories {
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", shortID(m.ID), m.Preview))
+		}
+	}
+
+	if len(sj.Decisions) > 0 {
+		sb.WriteString(fmt.Sprintf("\nDecisions (%d):\n", len(sj.Decisions)))
+		for _, d := range sj.Decisions {
+			sb.WriteString("- " + d + "\n")
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\nTool calls: %d", sj.Calls))
+	if sj.Errors > 0 {
+		sb.WriteString(fmt.Sprintf(" (%d failed)", sj.Errors))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// journalMiddleware records tool calls in the session journal and, on a
+// session's first call, arranges for end_session to run when it closes.
+func journalMiddleware(s *toolServer) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			result, err := next(ctx, method, req)
+
+			// end_session resets the journal itself
+			if callReq.Params.Name == "end_session" {
+				return result, err
+			}
+
+			var output interface{}
+			failed := err != nil
+			if res, ok := result.(*mcp.CallToolResult); ok && res != nil {
+				output = res.StructuredContent
+				failed = failed || res.IsError
+			}
+			s.journal.record(callReq.Session, callReq.Params.Name, callReq.Params.Arguments, output, failed)
+			s.watchSession(callReq.Session)
+
+			return result, err
+		}
+	}
+}
+
+// watchSession ends the session automatically once its transport closes
+func (s *toolServer) watchSession(ss *mcp.ServerSession) {
+	if ss == nil {
+		return
+	}
+	s.journal.mu.Lock()
+	start := !s.journal.watched[ss.ID()]
+	s.journal.watched[ss.ID()] = true
+	s.journal.mu.Unlock()
+
+	if start {
+		go func() {
+			_ = ss.Wait()
+			_, _ = s.endSession(context.Background(), ss, "", false)
+			s.journal.mu.Lock()
+			delete(s.journal.watched, ss.ID())
+			s.journal.mu.Unlock()
+		}()
+	}
+}
+
+// endSession writes the journal as an annotation on the active task (and
+// optionally a memory), then starts a fresh journal for the session.
+func (s *toolServer) endSession(ctx context.Context, ss *mcp.ServerSession, summary string, saveMemory bool) (map[string]interface{}, error) {
+	sj := s.journal.take(ss)
+	result := map[string]interface{}{
+		"client":     sj.Client,
+		"tool_calls": sj.Calls,
+		"tasks":      len(sj.Tasks),
+		"memories":   len(sj.Memories),
+		"decisions":  len(sj.Decisions),
+	}
+	if sj.empty() && summary == "" {
+		result["message"] = "Nothing to record for this session"
+		return result, nil
+	}
+
+	s = s.withContext(ctx)
+	client := s.client
+
+	// Fill in titles for tasks we only know by ID
+	for _, t := range sj.Tasks {
+		if t.Title == "" && t.ID != "" {
+			if task, err := client.GetTask(t.ID); err == nil {
+				t.ID = task.ID.String()
+				t.Title = task.Title
+			}
+		}
+	}
+
+	body := sj.markdown(summary, time.Now())
+	result["summary"] = body
+
+	activeTask, err := client.GetActiveTask()
+	if err == nil && activeTask != nil {
+		if _, err := client.CreateAnnotation(activeTask.ID.String(), body); err != nil {
+			return nil, err
+		}
+		result["annotated_task_id"] = activeTask.ID.String()
+	}
+
+	if saveMemory {
+		projectID, err := s.resolveProjectID("")
+		if err != nil {
+			return nil, err
+		}
+		memory, err := client.CreateMemory(projectID, body, "session-summary")
+		if err != nil {
+			return nil, err
+		}
+		result["memory_id"] = memory.ID.String()
+	}
+
+	if result["annotated_task_id"] == nil && result["memory_id"] == nil {
+		result["message"] = "No active task - summary was not stored. Pass save_memory=true to keep it as a memory."
+	} else {
+		result["message"] = "Session summary recorded"
+	}
+	return result, nil
+}
+
+type EndSessionInput struct {
+	Summary    string `json:"summary,omitempty" jsonschema:"Short description of what was accomplished, added on top of the generated summary"`
+	SaveMemory bool   `json:"save_memory,omitempty" jsonschema:"Also store the summary as a memory in the active project"`
+}
+
+func (s *toolServer) handleEndSession(ctx context.Context, req *mcp.CallToolRequest, input EndSessionInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+	result, err := s.endSession(ctx, req.Session, strings.TrimSpace(input.Summary), input.SaveMemory)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return d.Round(time.Second).String()
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progress reports the steps of a long-running tool call to the client.
+// Notifications are only sent when the request carries a progress token.
+type progress struct {
+	ctx   context.Context
+	req   *mcp.CallToolRequest
+	total int
+	done  int
+}
+
+func newProgress(ctx context.Context, req *mcp.CallToolRequest, total int) *progress {
+	return &progress{ctx: ctx, req: req, total: total}
+}
+
+// step marks one unit of work as done and notifies the client, e.g. "fetched tasks (3/5)".
+// It returns the context error if the request was cancelled, so handlers can stop early.
+func (p *progress) step(message string) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	p.done++
+	if p.req != nil && p.req.Session != nil && p.req.Params != nil {
+		if token := p.req.Params.GetProgressToken(); token != nil {
+			_ = p.req.Session.NotifyProgress(p.ctx, &mcp.ProgressNotificationParams{
+				ProgressToken: token,
+				Progress:      float64(p.done),
+				Total:         float64(p.total),
+				Message:       fmt.Sprintf("%s (%d/%d)", message, p.done, p.total),
+			})
+		}
+	}
+	return p.ctx.Err()
+}
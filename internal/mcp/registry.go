@@ -0,0 +1,239 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tool tiers, shown as a prefix of every tool description
+const (
+	tierEssential = "🔴 ESSENTIAL"
+	tierCommon    = "🟡 COMMON"
+	tierAdvanced  = "🟢 ADVANCED"
+)
+
+// tierOrder lists tiers from most to least important
+var tierOrder = []string{tierEssential, tierCommon, tierAdvanced}
+
+// toolSpec declares a single MCP tool. The server registration, the
+// `ramorie mcp tools` listing and the generated docs are all derived from it.
+type toolSpec struct {
+	Name        string
+	Tier        string
+	Category    string
+	Description string
+
+	inputSchema *jsonschema.Schema
+	add         func(server *mcp.Server, tool *mcp.Tool)
+}
+
+// newTool declares a tool whose input schema is reflected from the handler's input struct
+func newTool[In, Out any](name, tier, category, description string, handler mcp.ToolHandlerFor[In, Out]) toolSpec {
+	schema, err := jsonschema.For[In](nil)
+	if err != nil {
+		panic(fmt.Sprintf("mcp: cannot reflect input schema for tool %q: %v", name, err))
+	}
+	return toolSpec{
+		Name:        name,
+		Tier:        tier,
+		Category:    category,
+		Description: description,
+		inputSchema: schema,
+		add: func(server *mcp.Server, tool *mcp.Tool) {
+			mcp.AddTool(server, tool, handler)
+		},
+	}
+}
+
+// fullDescription is the description sent to clients, prefixed with the tier
+func (t toolSpec) fullDescription() string {
+	return t.Tier + " | " + t.Description
+}
+
+// tool builds the SDK tool definition for registration
+func (t toolSpec) tool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        t.Name,
+		Description: t.fullDescription(),
+		InputSchema: t.inputSchema,
+	}
+}
+
+// toolRegistry is the single list of all tools exposed by the MCP server
+func toolRegistry(s *toolServer) []toolSpec {
+	return []toolSpec{
+		// Agent onboarding
+		newTool("get_ramorie_info", tierEssential, "agent", "🧠 CALL THIS FIRST! Get comprehensive information about Ramorie - what it is, how to use it, and agent guidelines.", s.handleGetRamorieInfo),
+		newTool("setup_agent", tierEssential, "agent", "Initialize agent session. Returns current context, active project, pending tasks, and recommended actions.", s.handleSetupAgent),
+		newTool("end_session", tierCommon, "agent", "End the agent session. Writes a summary of tasks touched, memories added and decisions made as a note on the active task (runs automatically when the connection closes).", s.handleEndSession),
+
+		// Project management
+		newTool("list_projects", tierEssential, "project", "List all projects. Check this to see available projects and which one is active.", s.handleListProjects),
+		newTool("set_active_project", tierEssential, "project", "Set the active project. All new tasks and memories will be created in this project.", s.handleSetActiveProject),
+		newTool("create_project", tierAdvanced, "project", "Create a new project. ⚠️ Check list_projects first - don't create duplicates!", s.handleCreateProject),
+
+		// Task management
+		newTool("list_tasks", tierEssential, "task", "List tasks with filtering. 💡 Call before create_task to check for duplicates.", s.handleListTasks),
+		newTool("create_task", tierEssential, "task", "Create a new task. ⚠️ Always check list_tasks first to avoid duplicates!", s.handleCreateTask),
+		newTool("get_task", tierEssential, "task", "Get task details including notes and metadata.", s.handleGetTask),
+		newTool("start_task", tierEssential, "task", "Start working on a task. Sets status to IN_PROGRESS and enables memory auto-linking.", s.handleStartTask),
+		newTool("complete_task", tierEssential, "task", "Mark task as completed. Use when work is finished.", s.handleCompleteTask),
+		newTool("stop_task", tierAdvanced, "task", "Pause a task. Clears active task, keeps IN_PROGRESS status.", s.handleStopTask),
+		newTool("get_next_tasks", tierEssential, "task", "Get prioritized TODO tasks. 💡 Use at session start to see what needs attention.", s.handleGetNextTasks),
+		newTool("add_task_note", tierCommon, "task", "Add a note/annotation to a task. Use for progress updates or context.", s.handleAddTaskNote),
+		newTool("update_progress", tierCommon, "task", "Update task progress percentage (0-100).", s.handleUpdateProgress),
+		newTool("search_tasks", tierCommon, "task", "Search tasks by keyword. Use to find specific tasks.", s.handleSearchTasks),
+		newTool("get_active_task", tierCommon, "task", "Get the currently active task. Memories auto-link to this task.", s.handleGetActiveTask),
+
+		// Memory management
+		newTool("add_memory", tierEssential, "memory", "Store important information to knowledge base. Auto-links to active task. Set check_duplicates to get the ID of a near-identical memory instead of a copy. Content with secrets (API keys, tokens, private keys) is refused or redacted; flagged items come back in scan_warnings. 💡 If it matters later, add it here!", s.handleAddMemory),
+		newTool("list_memories", tierEssential, "memory", "List memories with optional filtering by project or term.", s.handleListMemories),
+		newTool("get_memory", tierCommon, "memory", "Get memory details by ID.", s.handleGetMemory),
+		newTool("recall", tierCommon, "memory", "Advanced memory search with multi-word support, filters, and relations. Supports: OR search (space-separated), AND search (comma-separated), project/tag filtering. Older memories rank lower unless evergreen; expired and archived ones are hidden unless include_expired. Set expand to also get memories related to the results. Text attachments (logs, traces) matching the terms are listed under attachments. Paginated with limit/offset.", s.handleRecall),
+		newTool("link_memories", tierAdvanced, "memory", "Relate two memories: supersedes (a newer note replaces an older one), contradicts, elaborates or see-also. Related memories show up in recall with expand.", s.handleLinkMemories),
+
+		// Focus management
+		newTool("get_focus", tierEssential, "focus", "Get user's current focus (active workspace). Returns the active context pack and its details.", s.handleGetFocus),
+		newTool("set_focus", tierEssential, "focus", "Set user's active focus (workspace). Switch to a different context pack.", s.handleSetFocus),
+		newTool("clear_focus", tierEssential, "focus", "Clear user's active focus. Deactivates the current context pack.", s.handleClearFocus),
+
+		// Decisions (ADRs)
+		newTool("create_decision", tierCommon, "decision", "Record an architectural decision (ADR). Use for important technical choices.", s.handleCreateDecision),
+		newTool("list_decisions", tierCommon, "decision", "List architectural decisions. Review past decisions before making new ones.", s.handleListDecisions),
+
+		// Reports
+		newTool("get_stats", tierCommon, "reports", "Get task statistics and completion rates.", s.handleGetStats),
+		newTool("export_project", tierAdvanced, "reports", "Export project report in markdown format. Large projects are paginated: pass next_offset to fetch the next page.", s.handleExportProject),
+		newTool("get_cursor_rules", tierAdvanced, "agent", "Get Cursor IDE rules for Ramorie. Returns markdown for .cursorrules file.", s.handleGetCursorRules),
+	}
+}
+
+// registerTools registers every tool in the registry with the server
+func registerTools(server *mcp.Server, s *toolServer) {
+	for _, t := range toolRegistry(s) {
+		t.add(server, t.tool())
+	}
+}
+
+type toolDef struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Tier        string             `json:"tier"`
+	Category    string             `json:"category"`
+	InputSchema *jsonschema.Schema `json:"inputSchema"`
+}
+
+// ToolDefinitions returns the metadata of all registered tools, in registration order
+func ToolDefinitions() []toolDef {
+	registry := toolRegistry(&toolServer{})
+	defs := make([]toolDef, 0, len(registry))
+	for _, t := range registry {
+		defs = append(defs, toolDef{
+			Name:        t.Name,
+			Description: t.fullDescription(),
+			Tier:        t.Tier,
+			Category:    t.Category,
+			InputSchema: t.inputSchema,
+		})
+	}
+	return defs
+}
+
+// toolsByTier groups tool names by tier, preserving registration order
+func toolsByTier() map[string][]string {
+	out := map[string][]string{}
+	for _, t := range toolRegistry(&toolServer{}) {
+		out[t.Tier] = append(out[t.Tier], t.Name)
+	}
+	return out
+}
+
+// toolsByCategory groups tool names by "<tier emoji> <category>" for get_ramorie_info
+func toolsByCategory() map[string][]string {
+	out := map[string][]string{}
+	for _, t := range toolRegistry(&toolServer{}) {
+		emoji := strings.Fields(t.Tier)[0]
+		key := emoji + " " + t.Category
+		out[key] = append(out[key], t.Name)
+	}
+	return out
+}
+
+// ToolsMarkdown renders reference documentation for all tools from the registry
+func ToolsMarkdown() string {
+	registry := toolRegistry(&toolServer{})
+	byTier := map[string][]toolSpec{}
+	for _, t := range registry {
+		byTier[t.Tier] = append(byTier[t.Tier], t)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Ramorie MCP Tools\n\n")
+	sb.WriteString("<!-- Generated by `ramorie mcp docs`. Do not edit by hand. -->\n\n")
+	sb.WriteString(fmt.Sprintf("%d tools are available.\n", len(registry)))
+
+	for _, tier := range tierOrder {
+		tools := byTier[tier]
+		if len(tools) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s (%d)\n", tier, len(tools)))
+		for _, t := range tools {
+			sb.WriteString(fmt.Sprintf("\n### `%s`\n\n", t.Name))
+			sb.WriteString(fmt.Sprintf("*Category: %s*\n\n", t.Category))
+			sb.WriteString(t.Description + "\n")
+			writeSchemaTable(&sb, t.inputSchema)
+		}
+	}
+	return sb.String()
+}
+
+func writeSchemaTable(sb *strings.Builder, schema *jsonschema.Schema) {
+	if schema == nil || len(schema.Properties) == 0 {
+		sb.WriteString("\n_No parameters._\n")
+		return
+	}
+
+	required := map[string]bool{}
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	sb.WriteString("\n| Parameter | Type | Required | Description |\n")
+	sb.WriteString("|-----------|------|----------|-------------|\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		req := ""
+		if required[name] {
+			req = "yes"
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", name, schemaType(prop), req, prop.Description))
+	}
+}
+
+func schemaType(s *jsonschema.Schema) string {
+	if s.Type != "" {
+		if s.Type == "array" && s.Items != nil {
+			return schemaType(s.Items) + "[]"
+		}
+		return s.Type
+	}
+	if len(s.Types) > 0 {
+		return strings.Join(s.Types, " \\| ")
+	}
+	return "any"
+}
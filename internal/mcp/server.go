@@ -4,20 +4,77 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/audit"
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/secrets"
 )
 
-// apiClient holds the API client for tool handlers
-var apiClient *api.Client
+// Deps holds the dependencies shared by all tool handlers
+type Deps struct {
+	Client *api.Client
 
-// ServeStdio starts the MCP server using the official go-sdk over stdio
-func ServeStdio(client *api.Client) error {
-	if client == nil {
-		return errors.New("api client is required")
+	// LoadConfig and SaveConfig access the CLI config (active project).
+	// They default to the ~/.ramorie/config.json helpers.
+	LoadConfig func() (*config.Config, error)
+	SaveConfig func(*config.Config) error
+
+	// Audit records every tool call; nil disables auditing
+	Audit *audit.Logger
+
+	// Now is the clock for memory expiry and recall decay; defaults to time.Now
+	Now func() time.Time
+
+	// Scanner checks memories, notes and tasks for secrets before they are
+	// stored; nil disables scanning
+	Scanner *secrets.Scanner
+}
+
+// toolServer carries the dependencies into the tool handlers
+type toolServer struct {
+	client     *api.Client
+	loadConfig func() (*config.Config, error)
+	saveConfig func(*config.Config) error
+	scanner    *secrets.Scanner
+	now        func() time.Time
+
+	// journal tracks per-session activity for end_session
+	journal *journal
+}
+
+// withContext returns a copy of s whose backend calls are cancelled with ctx
+func (s *toolServer) withContext(ctx context.Context) *toolServer {
+	cp := *s
+	cp.client = s.client.WithContext(ctx)
+	return &cp
+}
+
+// NewServer creates an MCP server with all tools registered against the given dependencies
+func NewServer(deps Deps) (*mcp.Server, error) {
+	if deps.Client == nil {
+		return nil, errors.New("api client is required")
+	}
+	s := &toolServer{
+		client:     deps.Client,
+		loadConfig: deps.LoadConfig,
+		saveConfig: deps.SaveConfig,
+		scanner:    deps.Scanner,
+		now:        deps.Now,
+		journal:    newJournal(),
+	}
+	if s.loadConfig == nil {
+		s.loadConfig = config.LoadConfig
+	}
+	if s.saveConfig == nil {
+		s.saveConfig = config.SaveConfig
+	}
+	if s.now == nil {
+		s.now = time.Now
 	}
-	apiClient = client
 
 	// Create server with implementation info
 	server := mcp.NewServer(
@@ -29,12 +86,62 @@ func ServeStdio(client *api.Client) error {
 	)
 
 	// Register all tools
-	registerTools(server)
+	registerTools(server, s)
+
+	// Journal tool calls so sessions can be summarized on end_session or close
+	server.AddReceivingMiddleware(journalMiddleware(s))
+
+	if deps.Audit != nil {
+		server.AddReceivingMiddleware(auditMiddleware(deps.Audit, deps.Scanner))
+	}
+
+	return server, nil
+}
+
+// ServeStdio starts the MCP server using the official go-sdk over stdio
+func ServeStdio(client *api.Client) error {
+	deps := Deps{Client: client}
+
+	// Record every tool call in the local audit log (~/.ramorie/audit)
+	if dir, err := audit.DefaultDir(); err == nil {
+		deps.Audit = audit.NewLogger(dir)
+	}
+
+	// Apply the secret scan policy and allowlist from ~/.ramorie
+	scanner, err := secrets.LoadDefault()
+	if err != nil {
+		return fmt.Errorf("could not load scan settings: %w", err)
+	}
+	deps.Scanner = scanner
+
+	server, err := NewServer(deps)
+	if err != nil {
+		return err
+	}
 
 	// Run server over stdio
 	return server.Run(context.Background(), &mcp.StdioTransport{})
 }
 
+// guard applies the secret scan policy to text a tool is about to store. It
+// returns the text to store and the findings to report back to the client.
+func (s *toolServer) guard(text string) (string, []secrets.Finding, error) {
+	if s.scanner == nil || text == "" {
+		return text, nil, nil
+	}
+	return s.scanner.Check(text)
+}
+
+// withScanWarnings adds redacted or flagged findings to a tool result
+func withScanWarnings(result interface{}, findings []secrets.Finding) interface{} {
+	if len(findings) == 0 {
+		return result
+	}
+	out := wrapResultAsObject(result)
+	out["scan_warnings"] = findings
+	return out
+}
+
 // wrapResultAsObject ensures the result is always an object (not array or null)
 func wrapResultAsObject(result interface{}) map[string]interface{} {
 	if result == nil {
@@ -5,12 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/terzigolu/josepshbrain-go/internal/api"
 	"github.com/terzigolu/josepshbrain-go/internal/config"
+	"github.com/terzigolu/josepshbrain-go/internal/dedupe"
+	"github.com/terzigolu/josepshbrain-go/internal/freshness"
+	"github.com/terzigolu/josepshbrain-go/internal/graph"
+	"github.com/terzigolu/josepshbrain-go/internal/memtype"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
 )
 
 // ToolInput is a generic input struct for tools that use map[string]interface{}
@@ -18,171 +26,6 @@ type ToolInput struct {
 	Args map[string]interface{} `json:"-"`
 }
 
-// registerTools registers all MCP tools with the server using go-sdk
-// The SDK automatically infers InputSchema from the handler's input struct type
-func registerTools(server *mcp.Server) {
-	// ============================================================================
-	// 🔴 ESSENTIAL - Agent Onboarding
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_ramorie_info",
-		Description: "🔴 ESSENTIAL | 🧠 CALL THIS FIRST! Get comprehensive information about Ramorie - what it is, how to use it, and agent guidelines.",
-	}, handleGetRamorieInfo)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "setup_agent",
-		Description: "🔴 ESSENTIAL | Initialize agent session. Returns current context, active project, pending tasks, and recommended actions.",
-	}, handleSetupAgent)
-
-	// ============================================================================
-	// 🔴 ESSENTIAL - Project Management
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_projects",
-		Description: "🔴 ESSENTIAL | List all projects. Check this to see available projects and which one is active.",
-	}, handleListProjects)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "set_active_project",
-		Description: "🔴 ESSENTIAL | Set the active project. All new tasks and memories will be created in this project.",
-	}, handleSetActiveProject)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_project",
-		Description: "🟢 ADVANCED | Create a new project. ⚠️ Check list_projects first - don't create duplicates!",
-	}, handleCreateProject)
-
-	// ============================================================================
-	// 🔴 ESSENTIAL - Task Management
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_tasks",
-		Description: "🔴 ESSENTIAL | List tasks with filtering. 💡 Call before create_task to check for duplicates.",
-	}, handleListTasks)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_task",
-		Description: "🔴 ESSENTIAL | Create a new task. ⚠️ Always check list_tasks first to avoid duplicates!",
-	}, handleCreateTask)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_task",
-		Description: "🔴 ESSENTIAL | Get task details including notes and metadata.",
-	}, handleGetTask)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "start_task",
-		Description: "🔴 ESSENTIAL | Start working on a task. Sets status to IN_PROGRESS and enables memory auto-linking.",
-	}, handleStartTask)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "complete_task",
-		Description: "🔴 ESSENTIAL | Mark task as completed. Use when work is finished.",
-	}, handleCompleteTask)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "stop_task",
-		Description: "🟢 ADVANCED | Pause a task. Clears active task, keeps IN_PROGRESS status.",
-	}, handleStopTask)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_next_tasks",
-		Description: "🔴 ESSENTIAL | Get prioritized TODO tasks. 💡 Use at session start to see what needs attention.",
-	}, handleGetNextTasks)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "add_task_note",
-		Description: "🟡 COMMON | Add a note/annotation to a task. Use for progress updates or context.",
-	}, handleAddTaskNote)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "update_progress",
-		Description: "🟡 COMMON | Update task progress percentage (0-100).",
-	}, handleUpdateProgress)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "search_tasks",
-		Description: "🟡 COMMON | Search tasks by keyword. Use to find specific tasks.",
-	}, handleSearchTasks)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_active_task",
-		Description: "🟡 COMMON | Get the currently active task. Memories auto-link to this task.",
-	}, handleGetActiveTask)
-
-	// ============================================================================
-	// 🔴 ESSENTIAL - Memory Management
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "add_memory",
-		Description: "🔴 ESSENTIAL | Store important information to knowledge base. Auto-links to active task. 💡 If it matters later, add it here!",
-	}, handleAddMemory)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_memories",
-		Description: "🔴 ESSENTIAL | List memories with optional filtering by project or term.",
-	}, handleListMemories)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_memory",
-		Description: "🟡 COMMON | Get memory details by ID.",
-	}, handleGetMemory)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "recall",
-		Description: "🟡 COMMON | Advanced memory search with multi-word support, filters, and relations. Supports: OR search (space-separated), AND search (comma-separated), project/tag filtering.",
-	}, handleRecall)
-
-	// ============================================================================
-	// 🔴 ESSENTIAL - Focus Management
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_focus",
-		Description: "🔴 ESSENTIAL | Get user's current focus (active workspace). Returns the active context pack and its details.",
-	}, handleGetFocus)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "set_focus",
-		Description: "🔴 ESSENTIAL | Set user's active focus (workspace). Switch to a different context pack.",
-	}, handleSetFocus)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "clear_focus",
-		Description: "🔴 ESSENTIAL | Clear user's active focus. Deactivates the current context pack.",
-	}, handleClearFocus)
-
-	// ============================================================================
-	// 🟡 COMMON - Decisions (ADRs)
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "create_decision",
-		Description: "🟡 COMMON | Record an architectural decision (ADR). Use for important technical choices.",
-	}, handleCreateDecision)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_decisions",
-		Description: "🟡 COMMON | List architectural decisions. Review past decisions before making new ones.",
-	}, handleListDecisions)
-
-	// ============================================================================
-	// 🟡 COMMON - Reports
-	// ============================================================================
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_stats",
-		Description: "🟡 COMMON | Get task statistics and completion rates.",
-	}, handleGetStats)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "export_project",
-		Description: "🟢 ADVANCED | Export project report in markdown format.",
-	}, handleExportProject)
-
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "get_cursor_rules",
-		Description: "🟢 ADVANCED | Get Cursor IDE rules for Ramorie. Returns markdown for .cursorrules file.",
-	}, handleGetCursorRules)
-}
-
 // ============================================================================
 // TOOL HANDLER FUNCTIONS
 // ============================================================================
@@ -194,20 +37,20 @@ type TextOutput struct {
 	Text string `json:"text"`
 }
 
-func handleGetRamorieInfo(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleGetRamorieInfo(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	return nil, getRamorieInfo(), nil
 }
 
-func handleSetupAgent(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
-	result, err := setupAgent(apiClient)
+func (s *toolServer) handleSetupAgent(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+	result, err := s.withContext(ctx).setupAgent(newProgress(ctx, req, 6))
 	if err != nil {
 		return nil, nil, err
 	}
 	return nil, result, nil
 }
 
-func handleListProjects(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, interface{}, error) {
-	projects, err := apiClient.ListProjects()
+func (s *toolServer) handleListProjects(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, interface{}, error) {
+	projects, err := s.client.ListProjects()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -215,29 +58,29 @@ func handleListProjects(ctx context.Context, req *mcp.CallToolRequest, input Emp
 }
 
 type SetActiveProjectInput struct {
-	ProjectName string `json:"projectName"`
+	ProjectName string `json:"projectName" jsonschema:"Project name or ID"`
 }
 
-func handleSetActiveProject(ctx context.Context, req *mcp.CallToolRequest, input SetActiveProjectInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleSetActiveProject(ctx context.Context, req *mcp.CallToolRequest, input SetActiveProjectInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	projectName := strings.TrimSpace(input.ProjectName)
 	if projectName == "" {
 		return nil, nil, errors.New("projectName is required")
 	}
-	projects, err := apiClient.ListProjects()
+	projects, err := s.client.ListProjects()
 	if err != nil {
 		return nil, nil, err
 	}
 	for _, p := range projects {
 		if p.Name == projectName || strings.HasPrefix(p.ID.String(), projectName) {
-			if err := apiClient.SetProjectActive(p.ID.String()); err != nil {
+			if err := s.client.SetProjectActive(p.ID.String()); err != nil {
 				return nil, nil, err
 			}
-			cfg, _ := config.LoadConfig()
+			cfg, _ := s.loadConfig()
 			if cfg == nil {
 				cfg = &config.Config{}
 			}
 			cfg.ActiveProjectID = p.ID.String()
-			_ = config.SaveConfig(cfg)
+			_ = s.saveConfig(cfg)
 			return nil, map[string]interface{}{"ok": true, "project_id": p.ID.String(), "name": p.Name}, nil
 		}
 	}
@@ -245,16 +88,16 @@ func handleSetActiveProject(ctx context.Context, req *mcp.CallToolRequest, input
 }
 
 type CreateProjectInput struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string `json:"name" jsonschema:"Project name - must be unique"`
+	Description string `json:"description,omitempty" jsonschema:"Project description"`
 }
 
-func handleCreateProject(ctx context.Context, req *mcp.CallToolRequest, input CreateProjectInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleCreateProject(ctx context.Context, req *mcp.CallToolRequest, input CreateProjectInput) (*mcp.CallToolResult, interface{}, error) {
 	name := strings.TrimSpace(input.Name)
 	if name == "" {
 		return nil, nil, errors.New("name is required")
 	}
-	project, err := apiClient.CreateProject(name, strings.TrimSpace(input.Description))
+	project, err := s.client.CreateProject(name, strings.TrimSpace(input.Description))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -262,21 +105,21 @@ func handleCreateProject(ctx context.Context, req *mcp.CallToolRequest, input Cr
 }
 
 type ListTasksInput struct {
-	Status  string  `json:"status,omitempty"`
-	Project string  `json:"project,omitempty"`
-	Limit   float64 `json:"limit,omitempty"`
+	Status  string  `json:"status,omitempty" jsonschema:"Filter: TODO, IN_PROGRESS, COMPLETED"`
+	Project string  `json:"project,omitempty" jsonschema:"Project name or ID"`
+	Limit   float64 `json:"limit,omitempty" jsonschema:"Max results"`
 }
 
-func handleListTasks(ctx context.Context, req *mcp.CallToolRequest, input ListTasksInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleListTasks(ctx context.Context, req *mcp.CallToolRequest, input ListTasksInput) (*mcp.CallToolResult, interface{}, error) {
 	projectID := ""
 	if strings.TrimSpace(input.Project) != "" {
-		pid, err := resolveProjectID(apiClient, input.Project)
+		pid, err := s.resolveProjectID(input.Project)
 		if err != nil {
 			return nil, nil, err
 		}
 		projectID = pid
 	}
-	tasks, err := apiClient.ListTasks(projectID, strings.TrimSpace(input.Status))
+	tasks, err := s.client.ListTasks(projectID, strings.TrimSpace(input.Status))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -288,96 +131,100 @@ func handleListTasks(ctx context.Context, req *mcp.CallToolRequest, input ListTa
 }
 
 type CreateTaskInput struct {
-	Description string `json:"description"`
-	Priority    string `json:"priority,omitempty"`
-	Project     string `json:"project,omitempty"`
+	Description string `json:"description" jsonschema:"Task description - clear and actionable"`
+	Priority    string `json:"priority,omitempty" jsonschema:"Priority: H=High, M=Medium, L=Low"`
+	Project     string `json:"project,omitempty" jsonschema:"Project name or ID (uses active if not specified)"`
 }
 
-func handleCreateTask(ctx context.Context, req *mcp.CallToolRequest, input CreateTaskInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleCreateTask(ctx context.Context, req *mcp.CallToolRequest, input CreateTaskInput) (*mcp.CallToolResult, interface{}, error) {
 	description := strings.TrimSpace(input.Description)
 	if description == "" {
 		return nil, nil, errors.New("description is required")
 	}
 	priority := normalizePriority(input.Priority)
-	projectID, err := resolveProjectID(apiClient, input.Project)
+	projectID, err := s.resolveProjectID(input.Project)
 	if err != nil {
 		return nil, nil, err
 	}
-	task, err := apiClient.CreateTask(projectID, description, "", priority)
+	description, findings, err := s.guard(description)
 	if err != nil {
 		return nil, nil, err
 	}
-	return nil, task, nil
+	task, err := s.client.CreateTask(projectID, description, "", priority)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, withScanWarnings(task, findings), nil
 }
 
 type TaskIDInput struct {
-	TaskID string `json:"taskId"`
+	TaskID string `json:"taskId" jsonschema:"Task ID"`
 }
 
-func handleGetTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleGetTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	if taskID == "" {
 		return nil, nil, errors.New("taskId is required")
 	}
-	task, err := apiClient.GetTask(taskID)
+	task, err := s.client.GetTask(taskID)
 	if err != nil {
 		return nil, nil, err
 	}
 	return nil, task, nil
 }
 
-func handleStartTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleStartTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	if taskID == "" {
 		return nil, nil, errors.New("taskId is required")
 	}
-	if err := apiClient.StartTask(taskID); err != nil {
+	if _, err := s.client.StartTask(taskID); err != nil {
 		return nil, nil, err
 	}
 	return nil, map[string]interface{}{"ok": true, "message": "Task started. Memories will now auto-link to this task."}, nil
 }
 
-func handleCompleteTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleCompleteTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	if taskID == "" {
 		return nil, nil, errors.New("taskId is required")
 	}
-	if err := apiClient.CompleteTask(taskID); err != nil {
+	if err := s.client.CompleteTask(taskID); err != nil {
 		return nil, nil, err
 	}
 	return nil, map[string]interface{}{"ok": true}, nil
 }
 
-func handleStopTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleStopTask(ctx context.Context, req *mcp.CallToolRequest, input TaskIDInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	if taskID == "" {
 		return nil, nil, errors.New("taskId is required")
 	}
-	if err := apiClient.StopTask(taskID); err != nil {
+	if err := s.client.StopTask(taskID); err != nil {
 		return nil, nil, err
 	}
 	return nil, map[string]interface{}{"ok": true}, nil
 }
 
 type GetNextTasksInput struct {
-	Count   float64 `json:"count,omitempty"`
-	Project string  `json:"project,omitempty"`
+	Count   float64 `json:"count,omitempty" jsonschema:"Number of tasks (default: 5)"`
+	Project string  `json:"project,omitempty" jsonschema:"Project name or ID"`
 }
 
-func handleGetNextTasks(ctx context.Context, req *mcp.CallToolRequest, input GetNextTasksInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleGetNextTasks(ctx context.Context, req *mcp.CallToolRequest, input GetNextTasksInput) (*mcp.CallToolResult, interface{}, error) {
 	count := int(input.Count)
 	if count <= 0 {
 		count = 5
 	}
 	projectID := ""
 	if strings.TrimSpace(input.Project) != "" {
-		pid, err := resolveProjectID(apiClient, input.Project)
+		pid, err := s.resolveProjectID(input.Project)
 		if err != nil {
 			return nil, nil, err
 		}
 		projectID = pid
 	}
-	tasks, err := apiClient.ListTasksQuery(projectID, "TODO", "", nil, nil)
+	tasks, err := s.client.ListTasksQuery(projectID, "TODO", "", nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -396,29 +243,33 @@ func handleGetNextTasks(ctx context.Context, req *mcp.CallToolRequest, input Get
 }
 
 type AddTaskNoteInput struct {
-	TaskID string `json:"taskId"`
-	Note   string `json:"note"`
+	TaskID string `json:"taskId" jsonschema:"Task ID"`
+	Note   string `json:"note" jsonschema:"Note content"`
 }
 
-func handleAddTaskNote(ctx context.Context, req *mcp.CallToolRequest, input AddTaskNoteInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleAddTaskNote(ctx context.Context, req *mcp.CallToolRequest, input AddTaskNoteInput) (*mcp.CallToolResult, interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	note := strings.TrimSpace(input.Note)
 	if taskID == "" || note == "" {
 		return nil, nil, errors.New("taskId and note are required")
 	}
-	annotation, err := apiClient.CreateAnnotation(taskID, note)
+	note, findings, err := s.guard(note)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotation, err := s.client.CreateAnnotation(taskID, note)
 	if err != nil {
 		return nil, nil, err
 	}
-	return nil, annotation, nil
+	return nil, withScanWarnings(annotation, findings), nil
 }
 
 type UpdateProgressInput struct {
-	TaskID   string  `json:"taskId"`
-	Progress float64 `json:"progress"`
+	TaskID   string  `json:"taskId" jsonschema:"Task ID"`
+	Progress float64 `json:"progress" jsonschema:"Progress percentage (0-100)"`
 }
 
-func handleUpdateProgress(ctx context.Context, req *mcp.CallToolRequest, input UpdateProgressInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleUpdateProgress(ctx context.Context, req *mcp.CallToolRequest, input UpdateProgressInput) (*mcp.CallToolResult, interface{}, error) {
 	taskID := strings.TrimSpace(input.TaskID)
 	progress := int(input.Progress)
 	if taskID == "" {
@@ -427,7 +278,7 @@ func handleUpdateProgress(ctx context.Context, req *mcp.CallToolRequest, input U
 	if progress < 0 || progress > 100 {
 		return nil, nil, errors.New("progress must be between 0 and 100")
 	}
-	result, err := apiClient.UpdateTask(taskID, map[string]interface{}{"progress": progress})
+	result, err := s.client.UpdateTask(taskID, map[string]interface{}{"progress": progress})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -435,26 +286,26 @@ func handleUpdateProgress(ctx context.Context, req *mcp.CallToolRequest, input U
 }
 
 type SearchTasksInput struct {
-	Query   string  `json:"query"`
-	Status  string  `json:"status,omitempty"`
-	Project string  `json:"project,omitempty"`
-	Limit   float64 `json:"limit,omitempty"`
+	Query   string  `json:"query" jsonschema:"Search query"`
+	Status  string  `json:"status,omitempty" jsonschema:"Filter: TODO, IN_PROGRESS, COMPLETED"`
+	Project string  `json:"project,omitempty" jsonschema:"Project name or ID"`
+	Limit   float64 `json:"limit,omitempty" jsonschema:"Max results"`
 }
 
-func handleSearchTasks(ctx context.Context, req *mcp.CallToolRequest, input SearchTasksInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleSearchTasks(ctx context.Context, req *mcp.CallToolRequest, input SearchTasksInput) (*mcp.CallToolResult, interface{}, error) {
 	query := strings.TrimSpace(input.Query)
 	if query == "" {
 		return nil, nil, errors.New("query is required")
 	}
 	projectID := ""
 	if strings.TrimSpace(input.Project) != "" {
-		pid, err := resolveProjectID(apiClient, input.Project)
+		pid, err := s.resolveProjectID(input.Project)
 		if err != nil {
 			return nil, nil, err
 		}
 		projectID = pid
 	}
-	tasks, err := apiClient.ListTasksQuery(projectID, strings.TrimSpace(input.Status), query, nil, nil)
+	tasks, err := s.client.ListTasksQuery(projectID, strings.TrimSpace(input.Status), query, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -465,8 +316,8 @@ func handleSearchTasks(ctx context.Context, req *mcp.CallToolRequest, input Sear
 	return nil, tasks, nil
 }
 
-func handleGetActiveTask(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, interface{}, error) {
-	task, err := apiClient.GetActiveTask()
+func (s *toolServer) handleGetActiveTask(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, interface{}, error) {
+	task, err := s.client.GetActiveTask()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -474,42 +325,140 @@ func handleGetActiveTask(ctx context.Context, req *mcp.CallToolRequest, input Em
 }
 
 type AddMemoryInput struct {
-	Content string `json:"content"`
-	Project string `json:"project,omitempty"`
+	Content         string `json:"content,omitempty" jsonschema:"Memory content - be descriptive. Optional for typed memories, which are described by their fields"`
+	Project         string `json:"project,omitempty" jsonschema:"Project name or ID"`
+	CheckDuplicates bool   `json:"check_duplicates,omitempty" jsonschema:"Look for a near-identical memory in the project first and return its ID instead of storing a duplicate"`
+
+	Type   string            `json:"type,omitempty" jsonschema:"Structured memory type: snippet (language, code), gotcha (symptom, cause, fix), how-to (goal, steps) or reference (url, title)"`
+	Fields map[string]string `json:"fields,omitempty" jsonschema:"Fields of the memory type, e.g. {\"symptom\": \"...\", \"fix\": \"...\"}"`
+
+	ExpiresAt   string `json:"expires_at,omitempty" jsonschema:"Date (YYYY-MM-DD or RFC3339) after which the memory is no longer true and is hidden from recall, e.g. for versions or temporary setups"`
+	ReviewAfter string `json:"review_after,omitempty" jsonschema:"Date (YYYY-MM-DD or RFC3339) when the memory should be checked again"`
+	Evergreen   bool   `json:"evergreen,omitempty" jsonschema:"The memory does not go stale; recall ranking does not decay with its age"`
 }
 
-func handleAddMemory(ctx context.Context, req *mcp.CallToolRequest, input AddMemoryInput) (*mcp.CallToolResult, interface{}, error) {
+// duplicateThreshold is the similarity at which add_memory reports an existing memory
+const duplicateThreshold = 0.8
+
+func (s *toolServer) handleAddMemory(ctx context.Context, req *mcp.CallToolRequest, input AddMemoryInput) (*mcp.CallToolResult, interface{}, error) {
 	content := strings.TrimSpace(input.Content)
+	memoryType := strings.TrimSpace(input.Type)
+	if t, ok := memtype.Lookup(memoryType); ok {
+		memoryType = t.Name
+	}
+	if err := memtype.Validate(memoryType, input.Fields); err != nil {
+		return nil, nil, err
+	}
+	if content == "" {
+		content = memtype.Content(memoryType, input.Fields)
+	}
 	if content == "" {
 		return nil, nil, errors.New("content is required")
 	}
-	projectID, err := resolveProjectID(apiClient, input.Project)
+	content, findings, err := s.guard(content)
 	if err != nil {
 		return nil, nil, err
 	}
-	memory, err := apiClient.CreateMemory(projectID, content)
+	fields := make(map[string]string, len(input.Fields))
+	for name, value := range input.Fields {
+		checked, found, err := s.guard(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		fields[name] = checked
+		findings = append(findings, found...)
+	}
+	lifecycle, err := lifecycleUpdates(input.ExpiresAt, input.ReviewAfter, input.Evergreen)
 	if err != nil {
 		return nil, nil, err
 	}
-	return nil, memory, nil
+	projectID, err := s.resolveProjectID(input.Project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if input.CheckDuplicates {
+		existing, err := s.client.ListMemories(projectID, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		texts := make([]string, len(existing))
+		for i, m := range existing {
+			texts[i] = m.Content
+		}
+		if i, sim := dedupe.Closest(content, texts); i >= 0 && sim >= duplicateThreshold {
+			return nil, map[string]interface{}{
+				"duplicate":        true,
+				"existing_id":      existing[i].ID.String(),
+				"existing_content": truncate(existing[i].Content, 300),
+				"similarity":       math.Round(sim*100) / 100,
+				"message":          fmt.Sprintf("Not stored: memory %s is %.0f%% similar. Add to it instead, or call add_memory without check_duplicates to store anyway.", shortID(existing[i].ID.String()), sim*100),
+			}, nil
+		}
+	}
+
+	options := map[string]interface{}{}
+	for key, value := range lifecycle {
+		options[key] = value
+	}
+	if memoryType != "" {
+		options["type"] = memoryType
+		options["fields"] = fields
+	}
+	memory, err := s.client.CreateMemoryWithOptions(projectID, content, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A backend that ignores the lifecycle fields on create gets them in an
+	// update. The memory is stored either way, so a failed update is a warning.
+	if missing := freshness.Unapplied(*memory, lifecycle); len(missing) > 0 {
+		updated, err := s.client.UpdateMemory(memory.ID.String(), missing)
+		if err != nil {
+			out := wrapResultAsObject(withScanWarnings(memory, findings))
+			out["warning"] = fmt.Sprintf("Memory %s was stored, but setting its expiry and review dates failed: %v", shortID(memory.ID.String()), err)
+			return nil, out, nil
+		}
+		memory = updated
+	}
+	return nil, withScanWarnings(memory, findings), nil
+}
+
+// lifecycleUpdates turns add_memory's expiry and review inputs into memory update fields
+func lifecycleUpdates(expiresAt, reviewAfter string, evergreen bool) (map[string]interface{}, error) {
+	updates := map[string]interface{}{}
+	for key, value := range map[string]string{"expires_at": expiresAt, "review_after": reviewAfter} {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		t, err := freshness.ParseDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		updates[key] = t.UTC().Format(time.RFC3339)
+	}
+	if evergreen {
+		updates["evergreen"] = true
+	}
+	return updates, nil
 }
 
 type ListMemoriesInput struct {
-	Project string  `json:"project,omitempty"`
-	Term    string  `json:"term,omitempty"`
-	Limit   float64 `json:"limit,omitempty"`
+	Project string  `json:"project,omitempty" jsonschema:"Project name or ID"`
+	Term    string  `json:"term,omitempty" jsonschema:"Filter by keyword"`
+	Type    string  `json:"type,omitempty" jsonschema:"Filter by memory type (snippet, gotcha, how-to, reference)"`
+	Limit   float64 `json:"limit,omitempty" jsonschema:"Max results"`
 }
 
-func handleListMemories(ctx context.Context, req *mcp.CallToolRequest, input ListMemoriesInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleListMemories(ctx context.Context, req *mcp.CallToolRequest, input ListMemoriesInput) (*mcp.CallToolResult, interface{}, error) {
 	projectID := ""
 	if strings.TrimSpace(input.Project) != "" {
-		pid, err := resolveProjectID(apiClient, input.Project)
+		pid, err := s.resolveProjectID(input.Project)
 		if err != nil {
 			return nil, nil, err
 		}
 		projectID = pid
 	}
-	memories, err := apiClient.ListMemories(projectID, "")
+	memories, err := s.client.ListMemoriesQuery(projectID, "", strings.TrimSpace(input.Type))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -531,15 +480,15 @@ func handleListMemories(ctx context.Context, req *mcp.CallToolRequest, input Lis
 }
 
 type GetMemoryInput struct {
-	MemoryID string `json:"memoryId"`
+	MemoryID string `json:"memoryId" jsonschema:"Memory ID"`
 }
 
-func handleGetMemory(ctx context.Context, req *mcp.CallToolRequest, input GetMemoryInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleGetMemory(ctx context.Context, req *mcp.CallToolRequest, input GetMemoryInput) (*mcp.CallToolResult, interface{}, error) {
 	memoryID := strings.TrimSpace(input.MemoryID)
 	if memoryID == "" {
 		return nil, nil, errors.New("memoryId is required")
 	}
-	memory, err := apiClient.GetMemory(memoryID)
+	memory, err := s.client.GetMemory(memoryID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -547,16 +496,20 @@ func handleGetMemory(ctx context.Context, req *mcp.CallToolRequest, input GetMem
 }
 
 type RecallInput struct {
-	Term             string  `json:"term"`
-	Project          string  `json:"project,omitempty"`
-	Tag              string  `json:"tag,omitempty"`
-	LinkedTask       bool    `json:"linked_task,omitempty"`
-	IncludeRelations bool    `json:"include_relations,omitempty"`
-	Limit            float64 `json:"limit,omitempty"`
-	MinScore         float64 `json:"min_score,omitempty"`
+	Term             string  `json:"term" jsonschema:"Search terms. Space = OR (any match), comma = AND (all must match). Example: 'traefik docker' finds either, 'traefik,docker' finds both."`
+	Project          string  `json:"project,omitempty" jsonschema:"Filter by project name or ID"`
+	Tag              string  `json:"tag,omitempty" jsonschema:"Filter by tag name"`
+	Type             string  `json:"type,omitempty" jsonschema:"Filter by memory type (snippet, gotcha, how-to, reference)"`
+	LinkedTask       bool    `json:"linked_task,omitempty" jsonschema:"If true, only return memories linked to a task"`
+	IncludeRelations bool    `json:"include_relations,omitempty" jsonschema:"If true, include full project and task details (default: true)"`
+	Limit            float64 `json:"limit,omitempty" jsonschema:"Max results per page (default: 20)"`
+	Offset           float64 `json:"offset,omitempty" jsonschema:"Skip this many ranked results; pass next_offset from the previous page to continue"`
+	MinScore         float64 `json:"min_score,omitempty" jsonschema:"Minimum relevance score 0-100 (default: 0)"`
+	IncludeExpired   bool    `json:"include_expired,omitempty" jsonschema:"If true, also return expired and archived memories"`
+	Expand           bool    `json:"expand,omitempty" jsonschema:"If true, also return memories one relation away from the results (supersedes, contradicts, elaborates, see-also) as related"`
 }
 
-func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	term := strings.TrimSpace(input.Term)
 	if term == "" {
 		return nil, nil, errors.New("term is required")
@@ -572,47 +525,55 @@ func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInp
 		includeRelations = input.IncludeRelations
 	}
 
+	offset := int(input.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+
+	s = s.withContext(ctx)
+	p := newProgress(ctx, req, 3)
+
 	projectID := ""
 	if strings.TrimSpace(input.Project) != "" {
-		pid, err := resolveProjectID(apiClient, input.Project)
+		pid, err := s.resolveProjectID(input.Project)
 		if err == nil {
 			projectID = pid
 		}
 	}
+	if err := p.step("resolved project"); err != nil {
+		return nil, nil, err
+	}
 
-	memories, err := apiClient.ListMemories(projectID, "")
+	memories, err := s.client.ListMemoriesQuery(projectID, "", strings.TrimSpace(input.Type))
 	if err != nil {
 		return nil, nil, err
 	}
-
-	isAndSearch := strings.Contains(term, ",")
-	var searchTerms []string
-	if isAndSearch {
-		for _, t := range strings.Split(term, ",") {
-			t = strings.TrimSpace(strings.ToLower(t))
-			if t != "" {
-				searchTerms = append(searchTerms, t)
-			}
-		}
-	} else {
-		for _, t := range strings.Fields(term) {
-			t = strings.TrimSpace(strings.ToLower(t))
-			if t != "" {
-				searchTerms = append(searchTerms, t)
-			}
-		}
+	if err := p.step(fmt.Sprintf("fetched %d memories", len(memories))); err != nil {
+		return nil, nil, err
 	}
 
+	searchTerms, isAndSearch := api.SearchTerms(term)
+
 	type scoredMemory struct {
 		memory interface{}
 		score  int
 	}
 	var scored []scoredMemory
 
-	for _, m := range memories {
+	now := s.now()
+	for i, m := range memories {
+		// Large memory sets take a while to score; stop if the client gave up
+		if i%500 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+		}
 		if input.LinkedTask && m.LinkedTaskID == nil {
 			continue
 		}
+		if !input.IncludeExpired && freshness.Hidden(m, now) {
+			continue
+		}
 
 		if input.Tag != "" {
 			hasTag := false
@@ -632,6 +593,9 @@ func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInp
 		}
 
 		contentLower := strings.ToLower(m.Content)
+		if m.Type != "" {
+			contentLower = strings.ToLower(m.Content + "\n" + memtype.Content(m.Type, m.Fields))
+		}
 		score := 0
 		matchCount := 0
 
@@ -666,6 +630,10 @@ func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInp
 			score += 5
 		}
 
+		// Older memories rank lower unless they are evergreen
+		weight := freshness.Weight(m, now)
+		score = int(math.Round(float64(score) * weight))
+
 		if score < minScore {
 			continue
 		}
@@ -676,6 +644,26 @@ func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInp
 			"score":      score,
 			"created_at": m.CreatedAt,
 		}
+		if m.Type != "" {
+			result["type"] = m.Type
+			result["fields"] = m.Fields
+			result["content"] = memtype.Markdown(m.Type, m.Fields, m.Content)
+		}
+		if weight < 1 {
+			result["freshness"] = math.Round(weight*100) / 100
+		}
+		if m.ExpiresAt != nil {
+			result["expires_at"] = m.ExpiresAt
+			if freshness.Expired(m, now) {
+				result["expired"] = true
+			}
+		}
+		if m.ArchivedAt != nil {
+			result["archived"] = true
+		}
+		if freshness.Due(m, now) {
+			result["needs_review"] = true
+		}
 
 		if includeRelations {
 			if m.Project != nil {
@@ -695,29 +683,154 @@ func handleRecall(ctx context.Context, req *mcp.CallToolRequest, input RecallInp
 		scored = append(scored, scoredMemory{memory: result, score: score})
 	}
 
-	sort.Slice(scored, func(i, j int) bool {
+	// Stable sort keeps backend order (newest first) for equal scores
+	sort.SliceStable(scored, func(i, j int) bool {
 		return scored[i].score > scored[j].score
 	})
 
+	if err := p.step(fmt.Sprintf("ranked %d matches", len(scored))); err != nil {
+		return nil, nil, err
+	}
+
 	var results []interface{}
-	for i, s := range scored {
-		if i >= limit {
-			break
-		}
-		results = append(results, s.memory)
+	end := min(offset+limit, len(scored))
+	for i := offset; i < end; i++ {
+		results = append(results, scored[i].memory)
 	}
 
-	return nil, map[string]interface{}{
+	out := map[string]interface{}{
 		"term":        term,
 		"search_mode": map[bool]string{true: "AND", false: "OR"}[isAndSearch],
 		"count":       len(results),
 		"total_found": len(scored),
+		"offset":      offset,
+		"has_more":    end < len(scored),
 		"results":     results,
+	}
+	if end < len(scored) {
+		out["next_offset"] = end
+	}
+	if input.Expand {
+		out["related"] = s.relatedMemories(results, memories, now, input.IncludeExpired)
+	}
+	if offset == 0 {
+		if attachments := s.searchAttachments(searchTerms, isAndSearch, projectID); len(attachments) > 0 {
+			out["attachments"] = attachments
+		}
+	}
+	return nil, out, nil
+}
+
+// searchAttachments finds text attachments matching the recall terms
+func (s *toolServer) searchAttachments(terms []string, all bool, projectID string) []interface{} {
+	attachments, err := s.client.SearchAttachments(projectID, terms, all)
+	if err != nil {
+		return nil
+	}
+
+	var results []interface{}
+	for _, a := range attachments {
+		results = append(results, map[string]interface{}{
+			"id":         a.ID.String(),
+			"name":       a.Name,
+			"owner_type": a.OwnerType,
+			"owner_id":   a.OwnerID.String(),
+			"snippet":    a.Snippet,
+		})
+	}
+	return results
+}
+
+// relatedMemories follows the memory relations of recall results one hop.
+// Neighbours come from the fetched memories when possible, so only those
+// outside the searched project cost an extra request.
+func (s *toolServer) relatedMemories(results []interface{}, memories []models.Memory, now time.Time, includeExpired bool) []interface{} {
+	byID := map[uuid.UUID]models.Memory{}
+	for _, m := range memories {
+		byID[m.ID] = m
+	}
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, r := range results {
+		id, err := uuid.Parse(r.(map[string]interface{})["id"].(string))
+		if err == nil {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	related := []interface{}{}
+	for _, id := range ids {
+		links, err := s.client.ListMemoryLinks(id.String(), "")
+		if err != nil {
+			continue
+		}
+		for _, n := range graph.Neighbours(links, id) {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			m, ok := byID[n.ID]
+			if !ok {
+				fetched, err := s.client.GetMemory(n.ID.String())
+				if err != nil {
+					continue
+				}
+				m = *fetched
+			}
+			if !includeExpired && freshness.Hidden(m, now) {
+				continue
+			}
+			content := m.Content
+			if m.Type != "" {
+				content = memtype.Markdown(m.Type, m.Fields, m.Content)
+			}
+			related = append(related, map[string]interface{}{
+				"id":       m.ID.String(),
+				"content":  content,
+				"relation": n.Relation,
+				"via":      id.String(),
+			})
+		}
+	}
+	return related
+}
+
+type LinkMemoriesInput struct {
+	MemoryID       string `json:"memory_id" jsonschema:"Memory the relation starts from, e.g. the newer note"`
+	TargetMemoryID string `json:"target_memory_id" jsonschema:"Memory the relation points to"`
+	Relation       string `json:"relation,omitempty" jsonschema:"supersedes, contradicts, elaborates or see-also (default: see-also)"`
+}
+
+func (s *toolServer) handleLinkMemories(ctx context.Context, req *mcp.CallToolRequest, input LinkMemoriesInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+	relation, err := graph.ParseRelation(input.Relation)
+	if err != nil {
+		return nil, nil, err
+	}
+	from, err := s.client.GetMemory(strings.TrimSpace(input.MemoryID))
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := s.client.GetMemory(strings.TrimSpace(input.TargetMemoryID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if from.ID == to.ID {
+		return nil, nil, errors.New("a memory cannot be linked to itself")
+	}
+	if _, err := s.client.CreateMemoryLink(from.ID.String(), to.ID.String(), relation); err != nil {
+		return nil, nil, err
+	}
+	return nil, map[string]interface{}{
+		"memory_id":        from.ID.String(),
+		"target_memory_id": to.ID.String(),
+		"relation":         relation,
+		"message":          fmt.Sprintf("%s %s %s", from.ID.String()[:8], relation, to.ID.String()[:8]),
 	}, nil
 }
 
-func handleGetFocus(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
-	focus, err := apiClient.GetFocus()
+func (s *toolServer) handleGetFocus(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+	focus, err := s.client.GetFocus()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -745,15 +858,15 @@ func handleGetFocus(ctx context.Context, req *mcp.CallToolRequest, input EmptyIn
 }
 
 type SetFocusInput struct {
-	PackID string `json:"packId"`
+	PackID string `json:"packId" jsonschema:"Context pack ID to activate"`
 }
 
-func handleSetFocus(ctx context.Context, req *mcp.CallToolRequest, input SetFocusInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleSetFocus(ctx context.Context, req *mcp.CallToolRequest, input SetFocusInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	packID := strings.TrimSpace(input.PackID)
 	if packID == "" {
 		return nil, nil, errors.New("packId is required")
 	}
-	focus, err := apiClient.SetFocus(packID)
+	focus, err := s.client.SetFocus(packID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -773,8 +886,8 @@ func handleSetFocus(ctx context.Context, req *mcp.CallToolRequest, input SetFocu
 	return nil, result, nil
 }
 
-func handleClearFocus(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
-	if err := apiClient.ClearFocus(); err != nil {
+func (s *toolServer) handleClearFocus(ctx context.Context, req *mcp.CallToolRequest, input EmptyInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+	if err := s.client.ClearFocus(); err != nil {
 		return nil, nil, err
 	}
 	return nil, map[string]interface{}{
@@ -784,20 +897,20 @@ func handleClearFocus(ctx context.Context, req *mcp.CallToolRequest, input Empty
 }
 
 type CreateDecisionInput struct {
-	Title        string `json:"title"`
-	Description  string `json:"description,omitempty"`
-	Status       string `json:"status,omitempty"`
-	Area         string `json:"area,omitempty"`
-	Context      string `json:"context,omitempty"`
-	Consequences string `json:"consequences,omitempty"`
+	Title        string `json:"title" jsonschema:"Decision title"`
+	Description  string `json:"description,omitempty" jsonschema:"Decision description"`
+	Status       string `json:"status,omitempty" jsonschema:"draft, proposed, approved, deprecated"`
+	Area         string `json:"area,omitempty" jsonschema:"Frontend, Backend, Architecture, etc."`
+	Context      string `json:"context,omitempty" jsonschema:"Why this decision?"`
+	Consequences string `json:"consequences,omitempty" jsonschema:"What are the impacts?"`
 }
 
-func handleCreateDecision(ctx context.Context, req *mcp.CallToolRequest, input CreateDecisionInput) (*mcp.CallToolResult, interface{}, error) {
+func (s *toolServer) handleCreateDecision(ctx context.Context, req *mcp.CallToolRequest, input CreateDecisionInput) (*mcp.CallToolResult, interface{}, error) {
 	title := strings.TrimSpace(input.Title)
 	if title == "" {
 		return nil, nil, errors.New("title is required")
 	}
-	decision, err := apiClient.CreateDecision(
+	decision, err := s.client.CreateDecision(
 		title,
 		strings.TrimSpace(input.Description),
 		strings.TrimSpace(input.Status),
@@ -812,13 +925,13 @@ func handleCreateDecision(ctx context.Context, req *mcp.CallToolRequest, input C
 }
 
 type ListDecisionsInput struct {
-	Status string  `json:"status,omitempty"`
-	Area   string  `json:"area,omitempty"`
-	Limit  float64 `json:"limit,omitempty"`
+	Status string  `json:"status,omitempty" jsonschema:"draft, proposed, approved, deprecated"`
+	Area   string  `json:"area,omitempty" jsonschema:"Frontend, Backend, Architecture, etc."`
+	Limit  float64 `json:"limit,omitempty" jsonschema:"Max results"`
 }
 
-func handleListDecisions(ctx context.Context, req *mcp.CallToolRequest, input ListDecisionsInput) (*mcp.CallToolResult, interface{}, error) {
-	decisions, err := apiClient.ListDecisions(strings.TrimSpace(input.Status), strings.TrimSpace(input.Area), int(input.Limit))
+func (s *toolServer) handleListDecisions(ctx context.Context, req *mcp.CallToolRequest, input ListDecisionsInput) (*mcp.CallToolResult, interface{}, error) {
+	decisions, err := s.client.ListDecisions(strings.TrimSpace(input.Status), strings.TrimSpace(input.Area), int(input.Limit))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -826,11 +939,11 @@ func handleListDecisions(ctx context.Context, req *mcp.CallToolRequest, input Li
 }
 
 type GetStatsInput struct {
-	Project string `json:"project,omitempty"`
+	Project string `json:"project,omitempty" jsonschema:"Project name or ID"`
 }
 
-func handleGetStats(ctx context.Context, req *mcp.CallToolRequest, input GetStatsInput) (*mcp.CallToolResult, interface{}, error) {
-	b, err := apiClient.Request("GET", "/reports/stats", nil)
+func (s *toolServer) handleGetStats(ctx context.Context, req *mcp.CallToolRequest, input GetStatsInput) (*mcp.CallToolResult, interface{}, error) {
+	b, err := s.client.Request("GET", "/reports/stats", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -842,22 +955,41 @@ func handleGetStats(ctx context.Context, req *mcp.CallToolRequest, input GetStat
 }
 
 type ExportProjectInput struct {
-	Project string `json:"project"`
-	Format  string `json:"format,omitempty"`
+	Project string  `json:"project,omitempty" jsonschema:"Project name or ID (uses active if not specified)"`
+	Format  string  `json:"format,omitempty" jsonschema:"Export format (default: markdown)"`
+	Limit   float64 `json:"limit,omitempty" jsonschema:"Max tasks per page (default: 100)"`
+	Offset  float64 `json:"offset,omitempty" jsonschema:"Skip this many tasks; pass next_offset from the previous page to continue"`
 }
 
-func handleExportProject(ctx context.Context, req *mcp.CallToolRequest, input ExportProjectInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+// exportPageSize is the default number of tasks per export_project page
+const exportPageSize = 100
+
+func (s *toolServer) handleExportProject(ctx context.Context, req *mcp.CallToolRequest, input ExportProjectInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	format := input.Format
 	if format == "" {
 		format = "markdown"
 	}
+	limit := int(input.Limit)
+	if limit <= 0 {
+		limit = exportPageSize
+	}
+	offset := int(input.Offset)
+	if offset < 0 {
+		offset = 0
+	}
 
-	projectID, err := resolveProjectID(apiClient, input.Project)
+	s = s.withContext(ctx)
+	p := newProgress(ctx, req, 4)
+
+	projectID, err := s.resolveProjectID(input.Project)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := p.step("resolved project"); err != nil {
+		return nil, nil, err
+	}
 
-	projects, err := apiClient.ListProjects()
+	projects, err := s.client.ListProjects()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -866,12 +998,12 @@ func handleExportProject(ctx context.Context, req *mcp.CallToolRequest, input Ex
 		Name        string
 		Description string
 	}
-	for _, p := range projects {
-		if p.ID.String() == projectID {
+	for _, proj := range projects {
+		if proj.ID.String() == projectID {
 			project = &struct {
 				Name        string
 				Description string
-			}{p.Name, p.Description}
+			}{proj.Name, proj.Description}
 			break
 		}
 	}
@@ -879,41 +1011,54 @@ func handleExportProject(ctx context.Context, req *mcp.CallToolRequest, input Ex
 	if project == nil {
 		return nil, nil, errors.New("project not found")
 	}
+	if err := p.step("fetched project"); err != nil {
+		return nil, nil, err
+	}
 
-	tasks, err := apiClient.ListTasks(projectID, "")
+	tasks, err := s.client.ListTasks(projectID, "")
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := p.step(fmt.Sprintf("fetched %d tasks", len(tasks))); err != nil {
+		return nil, nil, err
+	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# %s\n\n", project.Name))
-	if project.Description != "" {
-		sb.WriteString(fmt.Sprintf("%s\n\n", project.Description))
-	}
-
-	total := len(tasks)
-	completed := 0
-	inProgress := 0
-	pending := 0
-	for _, t := range tasks {
-		switch t.Status {
-		case "COMPLETED":
-			completed++
-		case "IN_PROGRESS":
-			inProgress++
-		default:
-			pending++
+
+	// The header and statistics are only part of the first page
+	if offset == 0 {
+		sb.WriteString(fmt.Sprintf("# %s\n\n", project.Name))
+		if project.Description != "" {
+			sb.WriteString(fmt.Sprintf("%s\n\n", project.Description))
 		}
-	}
 
-	sb.WriteString("## Statistics\n\n")
-	sb.WriteString(fmt.Sprintf("- **Total:** %d\n", total))
-	sb.WriteString(fmt.Sprintf("- **Completed:** %d\n", completed))
-	sb.WriteString(fmt.Sprintf("- **In Progress:** %d\n", inProgress))
-	sb.WriteString(fmt.Sprintf("- **Pending:** %d\n\n", pending))
+		total := len(tasks)
+		completed := 0
+		inProgress := 0
+		pending := 0
+		for _, t := range tasks {
+			switch t.Status {
+			case "COMPLETED":
+				completed++
+			case "IN_PROGRESS":
+				inProgress++
+			default:
+				pending++
+			}
+		}
+
+		sb.WriteString("## Statistics\n\n")
+		sb.WriteString(fmt.Sprintf("- **Total:** %d\n", total))
+		sb.WriteString(fmt.Sprintf("- **Completed:** %d\n", completed))
+		sb.WriteString(fmt.Sprintf("- **In Progress:** %d\n", inProgress))
+		sb.WriteString(fmt.Sprintf("- **Pending:** %d\n\n", pending))
+
+		sb.WriteString("## Tasks\n\n")
+	}
 
-	sb.WriteString("## Tasks\n\n")
-	for _, t := range tasks {
+	end := min(offset+limit, len(tasks))
+	for i := offset; i < end; i++ {
+		t := tasks[i]
 		status := "⏳"
 		if t.Status == "COMPLETED" {
 			status = "✅"
@@ -922,19 +1067,29 @@ func handleExportProject(ctx context.Context, req *mcp.CallToolRequest, input Ex
 		}
 		sb.WriteString(fmt.Sprintf("- %s **%s** [%s]\n", status, t.Title, t.Priority))
 	}
+	if err := p.step("rendered markdown"); err != nil {
+		return nil, nil, err
+	}
 
-	return nil, map[string]interface{}{
-		"project":  project.Name,
-		"format":   format,
-		"markdown": sb.String(),
-	}, nil
+	out := map[string]interface{}{
+		"project":     project.Name,
+		"format":      format,
+		"markdown":    sb.String(),
+		"total_tasks": len(tasks),
+		"offset":      offset,
+		"has_more":    end < len(tasks),
+	}
+	if end < len(tasks) {
+		out["next_offset"] = end
+	}
+	return nil, out, nil
 }
 
 type GetCursorRulesInput struct {
-	Format string `json:"format"`
+	Format string `json:"format,omitempty" jsonschema:"markdown (default) or json"`
 }
 
-func handleGetCursorRules(ctx context.Context, req *mcp.CallToolRequest, input GetCursorRulesInput) (*mcp.CallToolResult, map[string]interface{}, error) {
+func (s *toolServer) handleGetCursorRules(ctx context.Context, req *mcp.CallToolRequest, input GetCursorRulesInput) (*mcp.CallToolResult, map[string]interface{}, error) {
 	format := input.Format
 	if format == "" {
 		format = "markdown"
@@ -942,233 +1097,6 @@ func handleGetCursorRules(ctx context.Context, req *mcp.CallToolRequest, input G
 	return nil, getCursorRules(format), nil
 }
 
-// ============================================================================
-// LEGACY SUPPORT - ToolDefinitions for CLI tools command
-// ============================================================================
-
-type toolDef struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"inputSchema"`
-}
-
-func ToolDefinitions() []toolDef {
-	return []toolDef{
-		// ============================================================================
-		// 🔴 ESSENTIAL - Agent Onboarding (CALL THESE FIRST!)
-		// ============================================================================
-		{
-			Name:        "get_ramorie_info",
-			Description: "🔴 ESSENTIAL | 🧠 CALL THIS FIRST! Get comprehensive information about Ramorie - what it is, how to use it, and agent guidelines.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-		{
-			Name:        "setup_agent",
-			Description: "🔴 ESSENTIAL | Initialize agent session. Returns current context, active project, pending tasks, and recommended actions.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-
-		// ============================================================================
-		// 🔴 ESSENTIAL - Project Management
-		// ============================================================================
-		{
-			Name:        "list_projects",
-			Description: "🔴 ESSENTIAL | List all projects. Check this to see available projects and which one is active.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-		{
-			Name:        "set_active_project",
-			Description: "🔴 ESSENTIAL | Set the active project. All new tasks and memories will be created in this project.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"projectName": map[string]interface{}{"type": "string", "description": "Project name or ID"}}, "required": []string{"projectName"}},
-		},
-
-		// ============================================================================
-		// 🔴 ESSENTIAL - Task Management (Core)
-		// ============================================================================
-		{
-			Name:        "list_tasks",
-			Description: "🔴 ESSENTIAL | List tasks with filtering. 💡 Call before create_task to check for duplicates.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"status": map[string]interface{}{"type": "string", "description": "Filter: TODO, IN_PROGRESS, COMPLETED"}, "project": map[string]interface{}{"type": "string", "description": "Project name or ID"}, "limit": map[string]interface{}{"type": "number", "description": "Max results"}}},
-		},
-		{
-			Name:        "create_task",
-			Description: "🔴 ESSENTIAL | Create a new task. ⚠️ Always check list_tasks first to avoid duplicates!",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"description": map[string]interface{}{"type": "string", "description": "Task description - clear and actionable"}, "priority": map[string]interface{}{"type": "string", "description": "Priority: H=High, M=Medium, L=Low"}, "project": map[string]interface{}{"type": "string", "description": "Project name or ID (uses active if not specified)"}}, "required": []string{"description"}},
-		},
-		{
-			Name:        "get_task",
-			Description: "🔴 ESSENTIAL | Get task details including notes and metadata.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}}, "required": []string{"taskId"}},
-		},
-		{
-			Name:        "start_task",
-			Description: "🔴 ESSENTIAL | Start working on a task. Sets status to IN_PROGRESS and enables memory auto-linking.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}}, "required": []string{"taskId"}},
-		},
-		{
-			Name:        "complete_task",
-			Description: "🔴 ESSENTIAL | Mark task as completed. Use when work is finished.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}}, "required": []string{"taskId"}},
-		},
-		{
-			Name:        "get_next_tasks",
-			Description: "🔴 ESSENTIAL | Get prioritized TODO tasks. 💡 Use at session start to see what needs attention.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"count": map[string]interface{}{"type": "number", "description": "Number of tasks (default: 5)"}, "project": map[string]interface{}{"type": "string"}}},
-		},
-
-		// ============================================================================
-		// 🔴 ESSENTIAL - Memory Management (Core)
-		// ============================================================================
-		{
-			Name:        "add_memory",
-			Description: "🔴 ESSENTIAL | Store important information to knowledge base. Auto-links to active task. 💡 If it matters later, add it here!",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"content": map[string]interface{}{"type": "string", "description": "Memory content - be descriptive"}, "project": map[string]interface{}{"type": "string", "description": "Project name or ID"}}, "required": []string{"content"}},
-		},
-		{
-			Name:        "list_memories",
-			Description: "🔴 ESSENTIAL | List memories with optional filtering by project or term.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"project": map[string]interface{}{"type": "string"}, "term": map[string]interface{}{"type": "string", "description": "Filter by keyword"}, "limit": map[string]interface{}{"type": "number"}}},
-		},
-
-		// ============================================================================
-		// 🔴 ESSENTIAL - Focus Management (SINGLE SOURCE OF TRUTH for active workspace)
-		// ============================================================================
-		{
-			Name:        "get_focus",
-			Description: "🔴 ESSENTIAL | Get user's current focus (active workspace). Returns the active context pack and its details.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-		{
-			Name:        "set_focus",
-			Description: "🔴 ESSENTIAL | Set user's active focus (workspace). Switch to a different context pack.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"packId": map[string]interface{}{"type": "string", "description": "Context pack ID to activate"}}, "required": []string{"packId"}},
-		},
-		{
-			Name:        "clear_focus",
-			Description: "🔴 ESSENTIAL | Clear user's active focus. Deactivates the current context pack.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-
-		// ============================================================================
-		// 🟡 COMMON - Task Management (Extended)
-		// ============================================================================
-		{
-			Name:        "add_task_note",
-			Description: "🟡 COMMON | Add a note/annotation to a task. Use for progress updates or context.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}, "note": map[string]interface{}{"type": "string"}}, "required": []string{"taskId", "note"}},
-		},
-		{
-			Name:        "update_progress",
-			Description: "🟡 COMMON | Update task progress percentage (0-100).",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}, "progress": map[string]interface{}{"type": "number"}}, "required": []string{"taskId", "progress"}},
-		},
-		{
-			Name:        "search_tasks",
-			Description: "🟡 COMMON | Search tasks by keyword. Use to find specific tasks.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"query": map[string]interface{}{"type": "string", "description": "Search query"}, "status": map[string]interface{}{"type": "string"}, "project": map[string]interface{}{"type": "string"}, "limit": map[string]interface{}{"type": "number"}}, "required": []string{"query"}},
-		},
-		{
-			Name:        "get_active_task",
-			Description: "🟡 COMMON | Get the currently active task. Memories auto-link to this task.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-		},
-
-		// ============================================================================
-		// 🟡 COMMON - Memory Management (Extended)
-		// ============================================================================
-		{
-			Name:        "get_memory",
-			Description: "🟡 COMMON | Get memory details by ID.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"memoryId": map[string]interface{}{"type": "string"}}, "required": []string{"memoryId"}},
-		},
-		{
-			Name:        "recall",
-			Description: "🟡 COMMON | Advanced memory search with multi-word support, filters, and relations. Supports: OR search (space-separated), AND search (comma-separated), project/tag filtering.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"term": map[string]interface{}{
-						"type":        "string",
-						"description": "Search terms. Space = OR (any match), comma = AND (all must match). Example: 'traefik docker' finds either, 'traefik,docker' finds both.",
-					},
-					"project": map[string]interface{}{
-						"type":        "string",
-						"description": "Filter by project name or ID",
-					},
-					"tag": map[string]interface{}{
-						"type":        "string",
-						"description": "Filter by tag name",
-					},
-					"linked_task": map[string]interface{}{
-						"type":        "boolean",
-						"description": "If true, only return memories linked to a task",
-					},
-					"include_relations": map[string]interface{}{
-						"type":        "boolean",
-						"description": "If true, include full project and task details (default: true)",
-					},
-					"limit": map[string]interface{}{
-						"type":        "number",
-						"description": "Max results (default: 20)",
-					},
-					"min_score": map[string]interface{}{
-						"type":        "number",
-						"description": "Minimum relevance score 0-100 (default: 0)",
-					},
-				},
-				"required": []string{"term"},
-			},
-		},
-
-		// ============================================================================
-		// 🟡 COMMON - Decisions (ADRs)
-		// ============================================================================
-		{
-			Name:        "create_decision",
-			Description: "🟡 COMMON | Record an architectural decision (ADR). Use for important technical choices.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"title": map[string]interface{}{"type": "string", "description": "Decision title"}, "description": map[string]interface{}{"type": "string"}, "status": map[string]interface{}{"type": "string", "description": "draft, proposed, approved, deprecated"}, "area": map[string]interface{}{"type": "string", "description": "Frontend, Backend, Architecture, etc."}, "context": map[string]interface{}{"type": "string", "description": "Why this decision?"}, "consequences": map[string]interface{}{"type": "string", "description": "What are the impacts?"}}, "required": []string{"title"}},
-		},
-		{
-			Name:        "list_decisions",
-			Description: "🟡 COMMON | List architectural decisions. Review past decisions before making new ones.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"status": map[string]interface{}{"type": "string", "description": "draft, proposed, approved, deprecated"}, "area": map[string]interface{}{"type": "string"}, "limit": map[string]interface{}{"type": "number"}}},
-		},
-
-		// ============================================================================
-		// 🟡 COMMON - Reports
-		// ============================================================================
-		{
-			Name:        "get_stats",
-			Description: "🟡 COMMON | Get task statistics and completion rates.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"project": map[string]interface{}{"type": "string"}}},
-		},
-
-		// ============================================================================
-		// 🟢 ADVANCED - Less frequently used
-		// ============================================================================
-		{
-			Name:        "create_project",
-			Description: "🟢 ADVANCED | Create a new project. ⚠️ Check list_projects first - don't create duplicates!",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string", "description": "Project name - must be unique"}, "description": map[string]interface{}{"type": "string"}}, "required": []string{"name"}},
-		},
-		{
-			Name:        "get_cursor_rules",
-			Description: "🟢 ADVANCED | Get Cursor IDE rules for Ramorie. Returns markdown for .cursorrules file.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"format": map[string]interface{}{"type": "string", "description": "markdown (default) or json"}}},
-		},
-		{
-			Name:        "export_project",
-			Description: "🟢 ADVANCED | Export project report in markdown format.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"project": map[string]interface{}{"type": "string"}, "format": map[string]interface{}{"type": "string"}}, "required": []string{"project"}},
-		},
-		{
-			Name:        "stop_task",
-			Description: "🟢 ADVANCED | Pause a task. Clears active task, keeps IN_PROGRESS status.",
-			InputSchema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"taskId": map[string]interface{}{"type": "string"}}, "required": []string{"taskId"}},
-		},
-	}
-}
-
 // ============================================================================
 // HELPER FUNCTIONS
 // ============================================================================
@@ -1186,14 +1114,14 @@ func priorityRank(p string) int {
 	}
 }
 
-func resolveProjectID(client *api.Client, projectIdentifier string) (string, error) {
+func (s *toolServer) resolveProjectID(projectIdentifier string) (string, error) {
 	projectIdentifier = strings.TrimSpace(projectIdentifier)
 	if projectIdentifier == "" {
-		cfg, err := config.LoadConfig()
+		cfg, err := s.loadConfig()
 		if err == nil && cfg.ActiveProjectID != "" {
 			return cfg.ActiveProjectID, nil
 		}
-		projects, err := client.ListProjects()
+		projects, err := s.client.ListProjects()
 		if err != nil {
 			return "", err
 		}
@@ -1205,7 +1133,7 @@ func resolveProjectID(client *api.Client, projectIdentifier string) (string, err
 		return "", errors.New("no active project - use set_active_project first")
 	}
 
-	projects, err := client.ListProjects()
+	projects, err := s.client.ListProjects()
 	if err != nil {
 		return "", err
 	}
@@ -1219,20 +1147,10 @@ func resolveProjectID(client *api.Client, projectIdentifier string) (string, err
 }
 
 func normalizePriority(s string) string {
-	s = strings.TrimSpace(strings.ToUpper(s))
-	if s == "" {
-		return "M"
-	}
-	switch s {
-	case "H", "HIGH":
-		return "H"
-	case "M", "MEDIUM":
-		return "M"
-	case "L", "LOW":
-		return "L"
-	default:
-		return "M"
+	if p, err := models.ParsePriority(s); err == nil {
+		return p
 	}
+	return "M"
 }
 
 func toInt(v interface{}) int {
@@ -1264,7 +1182,7 @@ func getRamorieInfo() map[string]interface{} {
 		"description": `Ramorie is a persistent memory and task management system for AI agents.
 It enables context preservation across sessions, task tracking, and knowledge storage.`,
 
-		"tool_count": 28,
+		"tool_count": len(toolRegistry(&toolServer{})),
 		"tool_priority_guide": map[string]string{
 			"🔴 ESSENTIAL": "Core functionality - use these regularly",
 			"🟡 COMMON":    "Frequently used - call when needed",
@@ -1280,6 +1198,7 @@ It enables context preservation across sessions, task tracking, and knowledge st
 			"6. start_task → Begin working (enables memory auto-link)",
 			"7. add_memory → Store important discoveries",
 			"8. complete_task → Mark work as done",
+			"9. end_session → Record a summary of the session on the active task",
 		},
 
 		"core_rules": []string{
@@ -1292,21 +1211,7 @@ It enables context preservation across sessions, task tracking, and knowledge st
 			"❌ Never create duplicate projects",
 		},
 
-		"tools_by_category": map[string][]string{
-			"🔴 agent":    {"get_ramorie_info", "setup_agent"},
-			"🔴 focus":    {"get_focus", "set_focus", "clear_focus"},
-			"🔴 project":  {"list_projects", "set_active_project"},
-			"🔴 task":     {"list_tasks", "create_task", "get_task", "start_task", "complete_task", "get_next_tasks"},
-			"🔴 memory":   {"add_memory", "list_memories"},
-			"🟡 task":     {"add_task_note", "update_progress", "search_tasks", "get_active_task"},
-			"🟡 memory":   {"get_memory", "recall"},
-			"🟡 decision": {"create_decision", "list_decisions"},
-			"🟡 reports":  {"get_stats"},
-			"🟢 project":  {"create_project"},
-			"🟢 agent":    {"get_cursor_rules"},
-			"🟢 reports":  {"export_project"},
-			"🟢 task":     {"stop_task"},
-		},
+		"tools_by_category": toolsByCategory(),
 	}
 }
 
@@ -1335,6 +1240,9 @@ func getCursorRules(format string) map[string]interface{} {
 3. ` + "`add_task_note`" + ` - Add progress notes
 4. ` + "`complete_task`" + ` - Mark as done
 
+### End of Session
+1. ` + "`end_session`" + ` - Record a summary of the session on the active task
+
 ### Key Rules
 - ✅ Check ` + "`list_tasks`" + ` before creating new tasks
 - ✅ Use ` + "`add_memory`" + ` for important information
@@ -1343,24 +1251,7 @@ func getCursorRules(format string) map[string]interface{} {
 - ❌ Never delete without user approval
 - ❌ Never create duplicate projects
 
-## Available Tools (28 total)
-
-### 🔴 ESSENTIAL (15)
-- get_ramorie_info, setup_agent
-- get_focus, set_focus, clear_focus
-- list_projects, set_active_project
-- list_tasks, create_task, get_task, start_task, complete_task, get_next_tasks
-- add_memory, list_memories
-
-### 🟡 COMMON (9)
-- add_task_note, update_progress, search_tasks, get_active_task
-- get_memory, recall
-- create_decision, list_decisions
-- get_stats
-
-### 🟢 ADVANCED (4)
-- create_project, get_cursor_rules, export_project, stop_task
-`
+` + availableToolsMarkdown()
 
 	result := map[string]interface{}{
 		"format": format,
@@ -1371,7 +1262,30 @@ func getCursorRules(format string) map[string]interface{} {
 	return result
 }
 
-func setupAgent(client *api.Client) (map[string]interface{}, error) {
+// availableToolsMarkdown lists tool names per tier for the cursor rules
+func availableToolsMarkdown() string {
+	byTier := toolsByTier()
+	total := 0
+	for _, names := range byTier {
+		total += len(names)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Available Tools (%d total)\n", total))
+	for _, tier := range tierOrder {
+		names := byTier[tier]
+		if len(names) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s (%d)\n", tier, len(names)))
+		sb.WriteString("- " + strings.Join(names, ", ") + "\n")
+	}
+	return sb.String()
+}
+
+// setupAgent gathers the session context with one backend call per step,
+// reporting progress and stopping early if the request is cancelled
+func (s *toolServer) setupAgent(p *progress) (map[string]interface{}, error) {
 	result := map[string]interface{}{
 		"status":  "ready",
 		"message": "🧠 Ramorie agent session initialized",
@@ -1379,13 +1293,16 @@ func setupAgent(client *api.Client) (map[string]interface{}, error) {
 	}
 
 	// Get active project
-	cfg, _ := config.LoadConfig()
+	cfg, _ := s.loadConfig()
 	if cfg != nil && cfg.ActiveProjectID != "" {
 		result["active_project_id"] = cfg.ActiveProjectID
 	}
+	if err := p.step("loaded config"); err != nil {
+		return nil, err
+	}
 
 	// Get current focus (active workspace)
-	focus, err := client.GetFocus()
+	focus, err := s.client.GetFocus()
 	if err == nil && focus != nil && focus.ActivePack != nil {
 		result["active_focus"] = map[string]interface{}{
 			"pack_id":        focus.ActiveContextPackID,
@@ -1395,9 +1312,12 @@ func setupAgent(client *api.Client) (map[string]interface{}, error) {
 			"tasks_count":    focus.ActivePack.TasksCount,
 		}
 	}
+	if err := p.step("fetched focus"); err != nil {
+		return nil, err
+	}
 
 	// List projects
-	projects, err := client.ListProjects()
+	projects, err := s.client.ListProjects()
 	if err == nil {
 		for _, p := range projects {
 			if p.IsActive {
@@ -1410,9 +1330,12 @@ func setupAgent(client *api.Client) (map[string]interface{}, error) {
 		}
 		result["projects_count"] = len(projects)
 	}
+	if err := p.step("fetched projects"); err != nil {
+		return nil, err
+	}
 
 	// Get active task
-	activeTask, err := client.GetActiveTask()
+	activeTask, err := s.client.GetActiveTask()
 	if err == nil && activeTask != nil {
 		result["active_task"] = map[string]interface{}{
 			"id":     activeTask.ID.String(),
@@ -1420,23 +1343,32 @@ func setupAgent(client *api.Client) (map[string]interface{}, error) {
 			"status": activeTask.Status,
 		}
 	}
+	if err := p.step("fetched active task"); err != nil {
+		return nil, err
+	}
 
 	// Get TODO tasks count
 	if cfg != nil && cfg.ActiveProjectID != "" {
-		tasks, err := client.ListTasks(cfg.ActiveProjectID, "TODO")
+		tasks, err := s.client.ListTasks(cfg.ActiveProjectID, "TODO")
 		if err == nil {
 			result["pending_tasks_count"] = len(tasks)
 		}
 	}
+	if err := p.step("fetched pending tasks"); err != nil {
+		return nil, err
+	}
 
 	// Get stats
-	statsBytes, err := client.Request("GET", "/reports/stats", nil)
+	statsBytes, err := s.client.Request("GET", "/reports/stats", nil)
 	if err == nil {
 		var stats map[string]interface{}
 		if json.Unmarshal(statsBytes, &stats) == nil {
 			result["stats"] = stats
 		}
 	}
+	if err := p.step("fetched stats"); err != nil {
+		return nil, err
+	}
 
 	// Recommendations
 	recommendations := []string{}
@@ -0,0 +1,203 @@
+// Package memtype defines the structured memory types (snippet, gotcha,
+// how-to, reference) and renders typed memories for people and agents.
+package memtype
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field is one structured field of a memory type
+type Field struct {
+	Name     string
+	Usage    string
+	Required bool
+}
+
+// Type describes a memory type and its fields, in display order
+type Type struct {
+	Name        string
+	Description string
+	Fields      []Field
+}
+
+// Types are the supported memory types; untyped memories are plain notes
+var Types = []Type{
+	{
+		Name:        "snippet",
+		Description: "A reusable piece of code",
+		Fields: []Field{
+			{Name: "language", Usage: "Language of the code, e.g. go or bash"},
+			{Name: "code", Usage: "The code itself", Required: true},
+		},
+	},
+	{
+		Name:        "gotcha",
+		Description: "A pitfall: what goes wrong, why, and how to fix it",
+		Fields: []Field{
+			{Name: "symptom", Usage: "What you observe", Required: true},
+			{Name: "cause", Usage: "Why it happens"},
+			{Name: "fix", Usage: "How to fix or avoid it", Required: true},
+		},
+	},
+	{
+		Name:        "how-to",
+		Description: "Steps to get something done",
+		Fields: []Field{
+			{Name: "goal", Usage: "What the steps achieve", Required: true},
+			{Name: "steps", Usage: "The steps, one per line", Required: true},
+		},
+	},
+	{
+		Name:        "reference",
+		Description: "A link worth keeping",
+		Fields: []Field{
+			{Name: "url", Usage: "Address of the resource", Required: true},
+			{Name: "title", Usage: "Title of the resource"},
+		},
+	},
+}
+
+// Lookup returns the type with the given name (case-insensitive)
+func Lookup(name string) (Type, bool) {
+	for _, t := range Types {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// Names returns the names of all memory types
+func Names() []string {
+	names := make([]string, len(Types))
+	for i, t := range Types {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// FieldNames returns every field name used by any type, sorted
+func FieldNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, t := range Types {
+		for _, f := range t.Fields {
+			if !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that memoryType exists and fields has its required fields
+// and nothing else. An empty type must come without fields.
+func Validate(memoryType string, fields map[string]string) error {
+	if memoryType == "" {
+		if len(fields) > 0 {
+			return fmt.Errorf("fields require a memory type (%s)", strings.Join(Names(), ", "))
+		}
+		return nil
+	}
+	t, ok := Lookup(memoryType)
+	if !ok {
+		return fmt.Errorf("unknown memory type '%s' (use %s)", memoryType, strings.Join(Names(), ", "))
+	}
+	for name := range fields {
+		if !t.has(name) {
+			return fmt.Errorf("%s memories have no '%s' field (fields: %s)", t.Name, name, strings.Join(t.fieldNames(), ", "))
+		}
+	}
+	for _, f := range t.Fields {
+		if strings.TrimSpace(fields[f.Name]) == "" && f.Required {
+			return fmt.Errorf("%s memories need a %s", t.Name, f.Name)
+		}
+	}
+	return nil
+}
+
+func (t Type) has(name string) bool {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Type) fieldNames() []string {
+	names := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Content renders the fields as searchable plain-text content, used when a
+// typed memory is created without content of its own.
+func Content(memoryType string, fields map[string]string) string {
+	t, ok := Lookup(memoryType)
+	if !ok {
+		return ""
+	}
+	var lines []string
+	for _, f := range t.Fields {
+		if v := strings.TrimSpace(fields[f.Name]); v != "" {
+			lines = append(lines, strings.ToUpper(f.Name[:1])+f.Name[1:]+": "+v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown renders a typed memory; summary is the memory content, shown
+// above the fields unless it only repeats them.
+func Markdown(memoryType string, fields map[string]string, summary string) string {
+	summary = strings.TrimSpace(summary)
+	if summary == Content(memoryType, fields) {
+		summary = ""
+	}
+
+	var sb strings.Builder
+	if summary != "" {
+		sb.WriteString(summary + "\n\n")
+	}
+	get := func(name string) string { return strings.TrimSpace(fields[name]) }
+
+	switch strings.ToLower(memoryType) {
+	case "snippet":
+		sb.WriteString("```" + get("language") + "\n" + get("code") + "\n```\n")
+	case "gotcha":
+		for _, part := range []struct{ label, name string }{
+			{"Symptom", "symptom"}, {"Cause", "cause"}, {"Fix", "fix"},
+		} {
+			if v := get(part.name); v != "" {
+				sb.WriteString("**" + part.label + ":** " + v + "\n")
+			}
+		}
+	case "how-to":
+		if goal := get("goal"); goal != "" {
+			sb.WriteString("**Goal:** " + goal + "\n\n")
+		}
+		n := 0
+		for _, step := range strings.Split(get("steps"), "\n") {
+			step = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(step), "-*0123456789.)"))
+			if step != "" {
+				n++
+				sb.WriteString(fmt.Sprintf("%d. %s\n", n, step))
+			}
+		}
+	case "reference":
+		title := get("title")
+		if title == "" {
+			title = get("url")
+		}
+		sb.WriteString("[" + title + "](" + get("url") + ")\n")
+	default:
+		return summary
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
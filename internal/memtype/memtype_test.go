@@ -0,0 +1,48 @@
+package memtype
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		fields  map[string]string
+		wantErr bool
+	}{
+		{"plain note", "", nil, false},
+		{"fields without type", "", map[string]string{"code": "x"}, true},
+		{"unknown type", "recipe", nil, true},
+		{"complete gotcha", "Gotcha", map[string]string{"symptom": "a", "fix": "b"}, false},
+		{"missing required", "gotcha", map[string]string{"symptom": "a"}, true},
+		{"foreign field", "reference", map[string]string{"url": "https://x", "code": "y"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.typ, tt.fields); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		typ     string
+		fields  map[string]string
+		summary string
+		want    string
+	}{
+		{"snippet", map[string]string{"language": "go", "code": "fmt.Println(1)"}, "Print a number",
+			"Print a number\n\n```go\nfmt.Println(1)\n```"},
+		{"how-to", map[string]string{"goal": "Release", "steps": "1. tag\n- push\n\n3) announce"}, "",
+			"**Goal:** Release\n\n1. tag\n2. push\n3. announce"},
+		{"reference", map[string]string{"url": "https://go.dev"}, "Url: https://go.dev",
+			"[https://go.dev](https://go.dev)"},
+		{"", nil, "plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := Markdown(tt.typ, tt.fields, tt.summary); got != tt.want {
+			t.Errorf("Markdown(%s) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
@@ -42,6 +42,20 @@ type Task struct {
 	Project     *Project     `json:"project,omitempty"`
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`   // first moved to IN_PROGRESS
+	CompletedAt *time.Time   `json:"completed_at,omitempty"` // moved to COMPLETED
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+
+	// BlockingTasks are the dependencies that must finish before this task
+	BlockingTasks []Dependency `json:"blocking_tasks,omitempty"`
+}
+
+// Dependency records that BlockingTaskID blocks BlockedTaskID
+type Dependency struct {
+	ID             uuid.UUID `json:"id"`
+	BlockingTaskID uuid.UUID `json:"blocking_task_id"`
+	BlockedTaskID  uuid.UUID `json:"blocked_task_id"`
+	BlockingTask   *Task     `json:"blocking_task,omitempty"`
 }
 
 // Memory represents a memory/knowledge item
@@ -54,6 +68,42 @@ type Memory struct {
 	Project      *Project    `json:"project,omitempty"`
 	CreatedAt    time.Time   `json:"created_at"`
 	UpdatedAt    time.Time   `json:"updated_at"`
+
+	// Type is empty for plain notes, or snippet, gotcha, how-to or reference with per-type Fields
+	Type   string            `json:"type,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// Lifecycle (see internal/freshness): expired and archived memories are
+	// hidden from recall, ReviewAfter queues a memory for review
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ReviewAfter *time.Time `json:"review_after,omitempty"`
+	Evergreen   bool       `json:"evergreen,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// MemoryLink is a typed relation from one memory to another
+// (supersedes, contradicts, elaborates, see-also; see internal/graph)
+type MemoryLink struct {
+	ID             uuid.UUID `json:"id"`
+	MemoryID       uuid.UUID `json:"memory_id"`
+	TargetMemoryID uuid.UUID `json:"target_memory_id"`
+	RelationType   string    `json:"relation_type"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Attachment is a file attached to a task or memory. Content is stored by
+// its SHA-256; text attachments are searchable by recall.
+type Attachment struct {
+	ID        uuid.UUID  `json:"id"`
+	OwnerType string     `json:"owner_type"` // task or memory
+	OwnerID   uuid.UUID  `json:"owner_id"`
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
+	Name      string     `json:"name"`
+	MimeType  string     `json:"mime_type"`
+	Size      int64      `json:"size"`
+	SHA256    string     `json:"sha256"`
+	Snippet   string     `json:"snippet,omitempty"` // matching text, in search results
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 type Tag struct {
@@ -90,6 +140,45 @@ type Context struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// TaskStats is the /reports/stats response
+type TaskStats struct {
+	TotalTasks     int            `json:"total_tasks"`
+	CompletedTasks int            `json:"completed_tasks"`
+	CompletionRate float64        `json:"completion_rate"`
+	ByStatus       map[string]int `json:"by_status"`
+	ByPriority     map[string]int `json:"by_priority"`
+}
+
+// BurndownReport is the /reports/burndown response: open work at the end of
+// each day or week, and how much was completed during it
+type BurndownReport struct {
+	Interval string          `json:"interval"`
+	Points   []BurndownPoint `json:"points"`
+}
+
+type BurndownPoint struct {
+	Date      string   `json:"date"` // 2006-01-02, the end of the interval
+	Remaining int      `json:"remaining"`
+	Completed int      `json:"completed"`
+	Ideal     *float64 `json:"ideal,omitempty"`
+}
+
+// HistoryReport is the /reports/history response. ByDay counts all activity
+// in the period; History holds the most recent entries up to the limit.
+type HistoryReport struct {
+	History []Activity     `json:"history"`
+	ByDay   map[string]int `json:"by_day"`
+}
+
+// Activity is one change to a task or memory
+type Activity struct {
+	Action     string    `json:"action"`      // created, updated, completed
+	EntityType string    `json:"entity_type"` // task, memory
+	EntityID   uuid.UUID `json:"entity_id"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 // API Response structures
 type APIResponse struct {
 	Success bool        `json:"success"`
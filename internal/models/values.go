@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePriority maps the spellings people use for a task priority (h, High,
+// MEDIUM, ...) to the stored H, M or L. An empty value is M.
+func ParsePriority(s string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "H", "HIGH":
+		return "H", nil
+	case "M", "MEDIUM", "":
+		return "M", nil
+	case "L", "LOW":
+		return "L", nil
+	}
+	return "", fmt.Errorf("invalid priority %q (use H, M or L)", s)
+}
+
+// ParseStatus maps the spellings people use for a task status (todo,
+// "in progress", done, ...) to the stored status. An empty value is TODO.
+func ParseStatus(s string) (string, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	v = strings.NewReplacer(" ", "_", "-", "_").Replace(v)
+	switch v {
+	case "TODO", "OPEN", "PENDING", "":
+		return "TODO", nil
+	case "IN_PROGRESS", "INPROGRESS", "STARTED", "DOING", "ACTIVE":
+		return "IN_PROGRESS", nil
+	case "IN_REVIEW", "REVIEW":
+		return "IN_REVIEW", nil
+	case "COMPLETED", "COMPLETE", "DONE", "CLOSED", "RESOLVED":
+		return "COMPLETED", nil
+	}
+	return "", fmt.Errorf("invalid status %q (use TODO, IN_PROGRESS, IN_REVIEW or COMPLETED)", s)
+}
+
+// MemoryTitle is the first non-empty line of a memory without heading
+// markers, or "" for an empty memory
+func MemoryTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// TagNames returns the tag names of a task or memory; the backend sends tags
+// as a list of strings, anything else has none
+func TagNames(tags interface{}) []string {
+	switch v := tags.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var names []string
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+// Package secrets detects credentials and personal data in text before it
+// leaves the machine, using pattern rules and an entropy check for random-
+// looking tokens, and applies the configured policy (block, redact or warn).
+package secrets
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+)
+
+// Kind separates credentials from personal data, which get separate policies
+type Kind string
+
+const (
+	KindSecret Kind = "secret"
+	KindPII    Kind = "pii"
+)
+
+// Policy is what happens to text with findings
+type Policy string
+
+const (
+	PolicyBlock  Policy = "block"
+	PolicyRedact Policy = "redact"
+	PolicyWarn   Policy = "warn"
+)
+
+// Default policies: credentials never leave the machine, personal data is flagged
+const (
+	DefaultSecretPolicy = PolicyBlock
+	DefaultPIIPolicy    = PolicyWarn
+)
+
+// AllowlistFile is the allowlist's file name in the config directory
+const AllowlistFile = "scan-allowlist"
+
+// ParsePolicy validates a policy name; empty means def
+func ParsePolicy(s string, def Policy) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return def, nil
+	case PolicyBlock, PolicyRedact, PolicyWarn:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid scan policy '%s' (use block, redact or warn)", s)
+	}
+}
+
+// Rule is a single detector
+type Rule struct {
+	ID          string
+	Description string
+	Kind        Kind
+	pattern     *regexp.Regexp
+
+	// group is the submatch holding the sensitive value (0 = whole match)
+	group int
+	// minEntropy rejects low-entropy values such as "password = changeme"
+	minEntropy float64
+}
+
+// Rules are the built-in detectors, most specific first
+var Rules = []Rule{
+	{ID: "private-key", Description: "private key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----[\s\S]*?(?:-----END (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----|$)`)},
+	{ID: "aws-access-key", Description: "AWS access key ID", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+	{ID: "aws-secret-key", Description: "AWS secret access key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`(?i)aws.{0,20}?secret.{0,20}?[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`), group: 1},
+	{ID: "gcp-service-account", Description: "GCP service account key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`"private_key_id"\s*:\s*"([0-9a-f]{40})"`), group: 1},
+	{ID: "google-api-key", Description: "Google / Gemini API key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{ID: "github-token", Description: "GitHub token", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{ID: "slack-token", Description: "Slack token", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}\b`)},
+	{ID: "stripe-key", Description: "Stripe secret key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{16,}\b`)},
+	{ID: "llm-api-key", Description: "OpenAI / Anthropic API key", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_\-]{32,}\b`)},
+	{ID: "jwt", Description: "JSON Web Token", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{ID: "connection-string", Description: "connection string with password", Kind: KindSecret,
+		pattern: regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^\s:/@]+:([^\s@/]{4,})@[^\s]+`), group: 1},
+	{ID: "secret-assignment", Description: "password or secret assignment", Kind: KindSecret,
+		pattern:    regexp.MustCompile(`(?i)\b[A-Z0-9_]*(?:password|passwd|pwd|secret|token|api_?key|access_?key)[A-Z0-9_]*\s*[:=]\s*["']?([^\s"']{8,})`),
+		group:      1,
+		minEntropy: 3.0},
+	{ID: "high-entropy", Description: "high-entropy string", Kind: KindSecret,
+		pattern:    regexp.MustCompile(`[A-Za-z0-9+/_\-]{32,}={0,2}`),
+		minEntropy: 4.5},
+	{ID: "email", Description: "email address", Kind: KindPII,
+		pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`)},
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Finding is one detected value
+type Finding struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Kind        Kind   `json:"kind"`
+	Line        int    `json:"line"`
+	Masked      string `json:"match"`
+
+	// Action is the policy applied by Check (redact or warn)
+	Action Policy `json:"action,omitempty"`
+
+	start, end int
+	value      string
+}
+
+// Scanner applies the rules, the allowlist and the policies
+type Scanner struct {
+	SecretPolicy Policy
+	PIIPolicy    Policy
+
+	allowValues  map[string]bool
+	allowRules   map[string]bool
+	allowPattern []*regexp.Regexp
+}
+
+// New returns a scanner with the default policies and an empty allowlist
+func New() *Scanner {
+	return &Scanner{
+		SecretPolicy: DefaultSecretPolicy,
+		PIIPolicy:    DefaultPIIPolicy,
+		allowValues:  map[string]bool{},
+		allowRules:   map[string]bool{},
+	}
+}
+
+// Load returns a scanner with the allowlist from dir/scan-allowlist, if present.
+// Each line is a literal value, "rule:<id>" to disable a rule, or "re:<regexp>";
+// blank lines and lines starting with # are ignored.
+func Load(dir string) (*Scanner, error) {
+	s := New()
+	f, err := os.Open(filepath.Join(dir, AllowlistFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if err := s.Allow(sc.Text()); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", AllowlistFile, n, err)
+		}
+	}
+	return s, sc.Err()
+}
+
+// LoadDefault returns the scanner configured in ~/.ramorie: its allowlist
+// and the scan_policy and pii_policy settings of config.json
+func LoadDefault() (*Scanner, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	s, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if s.SecretPolicy, err = ParsePolicy(cfg.ScanPolicy, DefaultSecretPolicy); err != nil {
+		return nil, err
+	}
+	if s.PIIPolicy, err = ParsePolicy(cfg.PIIPolicy, DefaultPIIPolicy); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Allow adds one allowlist entry (see Load for the format)
+func (s *Scanner) Allow(entry string) error {
+	entry = strings.TrimSpace(entry)
+	switch {
+	case entry == "" || strings.HasPrefix(entry, "#"):
+	case strings.HasPrefix(entry, "rule:"):
+		s.allowRules[strings.TrimSpace(strings.TrimPrefix(entry, "rule:"))] = true
+	case strings.HasPrefix(entry, "re:"):
+		re, err := regexp.Compile(strings.TrimSpace(strings.TrimPrefix(entry, "re:")))
+		if err != nil {
+			return err
+		}
+		s.allowPattern = append(s.allowPattern, re)
+	default:
+		s.allowValues[entry] = true
+	}
+	return nil
+}
+
+// Scan returns the findings in text, in order of position. Overlapping
+// matches are reported once, for the most specific rule.
+func (s *Scanner) Scan(text string) []Finding {
+	var findings []Finding
+	taken := func(start, end int) bool {
+		for _, f := range findings {
+			if start < f.end && end > f.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rule := range Rules {
+		if s.allowRules[rule.ID] {
+			continue
+		}
+		for _, m := range rule.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2*rule.group], m[2*rule.group+1]
+			if start < 0 {
+				continue
+			}
+			value := text[start:end]
+			if rule.minEntropy > 0 && !looksRandom(value, rule.minEntropy) {
+				continue
+			}
+			if taken(m[0], m[1]) || s.allowed(value) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:        rule.ID,
+				Description: rule.Description,
+				Kind:        rule.Kind,
+				Line:        strings.Count(text[:start], "\n") + 1,
+				Masked:      mask(value),
+				start:       start,
+				end:         end,
+				value:       value,
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].start < findings[j].start })
+	return findings
+}
+
+func (s *Scanner) allowed(value string) bool {
+	if s.allowValues[value] {
+		return true
+	}
+	for _, re := range s.allowPattern {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedError reports findings that the policy does not let through
+type BlockedError struct {
+	Findings []Finding
+}
+
+func (e *BlockedError) Error() string {
+	return "refusing to store content containing " + Summary(e.Findings) +
+		" (remove it, or allowlist it in ~/.ramorie/" + AllowlistFile + ")"
+}
+
+// Check scans text and applies the policies. It returns the text to store
+// (redacted if the policy says so) and the findings to warn about, or a
+// *BlockedError when a finding's policy is block.
+func (s *Scanner) Check(text string) (string, []Finding, error) {
+	findings := s.Scan(text)
+	if len(findings) == 0 {
+		return text, nil, nil
+	}
+
+	var blocked, redact, reported []Finding
+	for _, f := range findings {
+		f.Action = s.policy(f.Kind)
+		switch f.Action {
+		case PolicyBlock:
+			blocked = append(blocked, f)
+			continue
+		case PolicyRedact:
+			redact = append(redact, f)
+		}
+		reported = append(reported, f)
+	}
+	if len(blocked) > 0 {
+		return "", nil, &BlockedError{Findings: blocked}
+	}
+	return Redact(text, redact), reported, nil
+}
+
+func (s *Scanner) policy(kind Kind) Policy {
+	p := s.SecretPolicy
+	if kind == KindPII {
+		p = s.PIIPolicy
+	}
+	if p == "" {
+		return PolicyBlock
+	}
+	return p
+}
+
+// Redact replaces each finding's value with [REDACTED:<rule>]
+func Redact(text string, findings []Finding) string {
+	if len(findings) == 0 {
+		return text
+	}
+	sorted := append([]Finding{}, findings...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start > sorted[j].start })
+	for _, f := range sorted {
+		text = text[:f.start] + "[REDACTED:" + f.Rule + "]" + text[f.end:]
+	}
+	return text
+}
+
+// Summary describes findings briefly, e.g. "an AWS access key ID (line 2), an email address (line 5)"
+func Summary(findings []Finding) string {
+	parts := make([]string, len(findings))
+	for i, f := range findings {
+		parts[i] = fmt.Sprintf("%s %s (line %d)", article(f.Description), f.Description, f.Line)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func article(s string) string {
+	if strings.ContainsRune("AEIOUaeiou", rune(s[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// mask keeps just enough of a value to recognise it
+func mask(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexByte(value, '\n'); i >= 0 {
+		value = value[:i]
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + "…" + value[len(value)-2:]
+}
+
+// looksRandom reports whether value has the character mix and entropy of a
+// generated token rather than a word, identifier, path or UUID.
+func looksRandom(value string, minEntropy float64) bool {
+	if uuidPattern.MatchString(value) {
+		return false
+	}
+	var digits, letters int
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			letters++
+		}
+	}
+	if digits == 0 || letters == 0 {
+		return false
+	}
+	return entropy(value) >= minEntropy
+}
+
+// entropy is the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,25 @@
+// Filters the memory index as you type: every word must appear in a memory's
+// content, type or tags. Runs from file:// as well, no server needed.
+(function () {
+  var input = document.getElementById("search");
+  var count = document.getElementById("search-count");
+  var items = Array.prototype.slice.call(document.querySelectorAll("#memories > li"));
+
+  function filter() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var shown = 0;
+    items.forEach(function (li) {
+      var text = li.getAttribute("data-search");
+      var match = words.every(function (w) { return text.indexOf(w) !== -1; });
+      li.hidden = !match;
+      if (match) shown++;
+    });
+    count.textContent = words.length ? shown + " of " + items.length + " memories" : "";
+  }
+
+  input.addEventListener("input", filter);
+  // memories.html?q=docker opens with a search filled in
+  var q = new URLSearchParams(location.search).get("q");
+  if (q) input.value = q;
+  filter();
+})();
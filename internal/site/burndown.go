@@ -0,0 +1,88 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Burndown counts the open tasks at the end of each of the last days days,
+// and the tasks completed on each day, from the task timestamps
+func Burndown(tasks []models.Task, days int, now time.Time) []models.BurndownPoint {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	points := make([]models.BurndownPoint, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		start := today.AddDate(0, 0, -i)
+		end := start.AddDate(0, 0, 1)
+		p := models.BurndownPoint{Date: start.Format("2006-01-02")}
+		for _, t := range tasks {
+			if !t.CreatedAt.Before(end) {
+				continue
+			}
+			done := t.Status == "COMPLETED"
+			at := completedAt(t)
+			switch {
+			case !done || !at.Before(end):
+				p.Remaining++
+			case !at.Before(start):
+				p.Completed++
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+const (
+	svgWidth, svgHeight = 640, 240
+	padLeft, padRight   = 40, 16
+	padTop, padBottom   = 16, 32
+)
+
+// BurndownSVG draws the open tasks of the burndown period against a straight
+// ideal line to zero, as an inline SVG
+func (s *Site) BurndownSVG() template.HTML {
+	var tasks []models.Task
+	for _, t := range s.Tasks {
+		tasks = append(tasks, t.Task)
+	}
+	points := Burndown(tasks, s.BurndownDays, s.Generated)
+	if len(points) < 2 {
+		return ""
+	}
+	top := 1.0
+	for _, p := range points {
+		top = math.Max(top, float64(p.Remaining))
+	}
+	top = math.Ceil(top)
+	plotW := float64(svgWidth - padLeft - padRight)
+	plotH := float64(svgHeight - padTop - padBottom)
+	x := func(i int) float64 { return padLeft + float64(i)*plotW/float64(len(points)-1) }
+	y := func(v float64) float64 { return padTop + plotH - v/top*plotH }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="burndown" viewBox="0 0 %d %d" role="img" aria-label="Burndown: open tasks over the last %d days">`, svgWidth, svgHeight, len(points))
+	for _, v := range []float64{0, math.Round(top / 2), top} {
+		fmt.Fprintf(&b, `<line class="grid" x1="%d" x2="%d" y1="%.1f" y2="%.1f"/>`, padLeft, svgWidth-padRight, y(v), y(v))
+		fmt.Fprintf(&b, `<text class="axis" x="%d" y="%.1f" text-anchor="end">%.0f</text>`, padLeft-6, y(v)+4, v)
+	}
+	first := float64(points[0].Remaining)
+	fmt.Fprintf(&b, `<line class="ideal" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`, x(0), y(first), x(len(points)-1), y(0))
+	var coords []string
+	for i, p := range points {
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x(i), y(float64(p.Remaining))))
+	}
+	fmt.Fprintf(&b, `<polyline class="remaining" points="%s"/>`, strings.Join(coords, " "))
+	last := points[len(points)-1]
+	fmt.Fprintf(&b, `<circle class="remaining" cx="%.1f" cy="%.1f" r="3"><title>%s: %d open</title></circle>`, x(len(points)-1), y(float64(last.Remaining)), last.Date, last.Remaining)
+	fmt.Fprintf(&b, `<text class="axis" x="%d" y="%d">%s</text>`, padLeft, svgHeight-10, points[0].Date)
+	fmt.Fprintf(&b, `<text class="axis" x="%d" y="%d" text-anchor="end">%s</text>`, svgWidth-padRight, svgHeight-10, last.Date)
+	b.WriteString(`</svg>`)
+	// Only numbers and dates are interpolated above
+	return template.HTML(b.String())
+}
@@ -0,0 +1,277 @@
+// Package site renders a project as a self-contained static HTML site: a
+// dashboard with stats and a burndown chart, a page per task, a searchable
+// memory index and the ADR log. Templates, CSS and the search script are
+// embedded, and pages link to each other relatively, so the output can be
+// opened from disk or any file share without a server.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+//go:embed templates/*.html assets/*
+var files embed.FS
+
+// Task is a task with the details shown on its page
+type Task struct {
+	models.Task
+	Annotations []models.Annotation
+	Subtasks    []models.Subtask
+	Memories    []models.Memory
+}
+
+// Site is everything exported for one project
+type Site struct {
+	Project   models.Project
+	Tasks     []Task
+	Memories  []models.Memory
+	Decisions []api.Decision
+	Generated time.Time
+	// BurndownDays is how many days the dashboard burndown covers
+	BurndownDays int
+}
+
+// Count is one row of a breakdown, e.g. tasks with a status
+type Count struct {
+	Label   string
+	N       int
+	Percent float64
+}
+
+// page is the data every template gets
+type page struct {
+	*Site
+	Title string
+	Nav   string // active navigation entry
+	Root  string // relative path to the site root, "" or "../"
+	Task  *Task
+}
+
+// Write renders the site into dir and returns the number of files written
+func Write(dir string, s *Site) (int, error) {
+	if s.Generated.IsZero() {
+		s.Generated = time.Now()
+	}
+	if s.BurndownDays <= 0 {
+		s.BurndownDays = 30
+	}
+	sort.SliceStable(s.Tasks, func(i, j int) bool { return s.Tasks[i].CreatedAt.After(s.Tasks[j].CreatedAt) })
+	sort.SliceStable(s.Memories, func(i, j int) bool { return s.Memories[i].CreatedAt.After(s.Memories[j].CreatedAt) })
+	sort.SliceStable(s.Decisions, func(i, j int) bool { return s.Decisions[i].ADRNumber < s.Decisions[j].ADRNumber })
+
+	out := map[string][]byte{}
+	// Each page template is parsed once with the layout, however many pages use it
+	parsed := map[string]*template.Template{}
+	render := func(name, file string, p page) error {
+		tmpl := parsed[name]
+		if tmpl == nil {
+			var err error
+			if tmpl, err = template.New("").Funcs(funcs).ParseFS(files, "templates/layout.html", "templates/"+name); err != nil {
+				return err
+			}
+			parsed[name] = tmpl
+		}
+		var b bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&b, "layout", p); err != nil {
+			return fmt.Errorf("render %s: %w", file, err)
+		}
+		out[file] = b.Bytes()
+		return nil
+	}
+
+	pages := []struct {
+		tmpl, file, title, nav string
+	}{
+		{"index.html", "index.html", "Dashboard", "dashboard"},
+		{"tasks.html", "tasks.html", "Tasks", "tasks"},
+		{"memories.html", "memories.html", "Memories", "memories"},
+		{"decisions.html", "decisions.html", "Decisions", "decisions"},
+	}
+	for _, pg := range pages {
+		if err := render(pg.tmpl, pg.file, page{Site: s, Title: pg.title, Nav: pg.nav}); err != nil {
+			return 0, err
+		}
+	}
+	for i := range s.Tasks {
+		t := &s.Tasks[i]
+		if err := render("task.html", TaskPath(t.Task), page{Site: s, Title: t.Title, Nav: "tasks", Root: "../", Task: t}); err != nil {
+			return 0, err
+		}
+	}
+	assets, err := fs.Sub(files, "assets")
+	if err != nil {
+		return 0, err
+	}
+	if err := fs.WalkDir(assets, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(assets, path)
+		out[filepath.Join("assets", path)] = data
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	for rel, data := range out {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(out), nil
+}
+
+// TaskPath is the page of a task relative to the site root
+func TaskPath(t models.Task) string {
+	return "tasks/" + t.ID.String() + ".html"
+}
+
+var funcs = template.FuncMap{
+	"short": func(id interface{}) string {
+		s := fmt.Sprint(id)
+		if len(s) > 8 {
+			return s[:8]
+		}
+		return s
+	},
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("2006-01-02")
+	},
+	"datetime":  func(t time.Time) string { return t.Local().Format("2006-01-02 15:04") },
+	"taskPath":  TaskPath,
+	"completed": completedAt,
+	"title": func(content string) string {
+		title := models.MemoryTitle(content)
+		if title == "" {
+			return "Untitled"
+		}
+		if r := []rune(title); len(r) > 100 {
+			return string(r[:97]) + "..."
+		}
+		return title
+	},
+	"lower": strings.ToLower,
+	"tags":  models.TagNames,
+	"deref": func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	},
+	"statusClass": func(status string) string {
+		return strings.ToLower(strings.ReplaceAll(status, "_", "-"))
+	},
+	"searchText": func(m models.Memory) string {
+		return strings.ToLower(m.Content + " " + m.Type + " " + strings.Join(models.TagNames(m.Tags), " "))
+	},
+}
+
+// Stats are the headline numbers of the dashboard
+type Stats struct {
+	Total, Completed, InProgress, Open int
+	Rate                               float64
+	ByStatus, ByPriority               []Count
+}
+
+// Stats counts the project's tasks by status and priority
+func (s *Site) Stats() Stats {
+	var st Stats
+	status, priority := map[string]int{}, map[string]int{}
+	for _, t := range s.Tasks {
+		st.Total++
+		status[t.Status]++
+		priority[priorityName(t.Priority)]++
+		switch t.Status {
+		case "COMPLETED":
+			st.Completed++
+		case "IN_PROGRESS", "IN_REVIEW":
+			st.InProgress++
+		}
+	}
+	st.Open = st.Total - st.Completed
+	if st.Total > 0 {
+		st.Rate = float64(st.Completed) / float64(st.Total) * 100
+	}
+	st.ByStatus = counts(status, []string{"TODO", "IN_PROGRESS", "IN_REVIEW", "COMPLETED"}, st.Total)
+	st.ByPriority = counts(priority, []string{"High", "Medium", "Low"}, st.Total)
+	return st
+}
+
+func counts(m map[string]int, order []string, total int) []Count {
+	var out []Count
+	seen := map[string]bool{}
+	add := func(k string) {
+		if seen[k] || m[k] == 0 {
+			return
+		}
+		seen[k] = true
+		out = append(out, Count{Label: k, N: m[k], Percent: float64(m[k]) / float64(total) * 100})
+	}
+	for _, k := range order {
+		add(k)
+	}
+	var rest []string
+	for k := range m {
+		rest = append(rest, k)
+	}
+	sort.Strings(rest)
+	for _, k := range rest {
+		add(k)
+	}
+	return out
+}
+
+func priorityName(p string) string {
+	switch strings.ToUpper(p) {
+	case "H", "HIGH":
+		return "High"
+	case "M", "MEDIUM":
+		return "Medium"
+	case "L", "LOW":
+		return "Low"
+	case "":
+		return "None"
+	}
+	return p
+}
+
+// RecentlyCompleted lists up to ten of the latest completed tasks
+func (s *Site) RecentlyCompleted() []Task {
+	var done []Task
+	for _, t := range s.Tasks {
+		if t.Status == "COMPLETED" {
+			done = append(done, t)
+		}
+	}
+	sort.SliceStable(done, func(i, j int) bool { return completedAt(done[i].Task).After(completedAt(done[j].Task)) })
+	if len(done) > 10 {
+		done = done[:10]
+	}
+	return done
+}
+
+func completedAt(t models.Task) time.Time {
+	if t.CompletedAt != nil {
+		return *t.CompletedAt
+	}
+	return t.UpdatedAt
+}
@@ -0,0 +1,81 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/api"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+func TestBurndown(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	done := now.AddDate(0, 0, -1)
+	tasks := []models.Task{
+		{Status: "TODO", CreatedAt: now.AddDate(0, 0, -5)},
+		{Status: "COMPLETED", CreatedAt: now.AddDate(0, 0, -5), CompletedAt: &done},
+		{Status: "TODO", CreatedAt: now},
+	}
+	points := Burndown(tasks, 3, now)
+	if len(points) != 3 || points[0].Date != "2025-03-08" || points[2].Date != "2025-03-10" {
+		t.Fatalf("points = %+v", points)
+	}
+	want := []struct{ remaining, completed int }{{2, 0}, {1, 1}, {2, 0}}
+	for i, w := range want {
+		if points[i].Remaining != w.remaining || points[i].Completed != w.completed {
+			t.Errorf("day %d = %+v, want %+v", i, points[i], w)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	task := models.Task{ID: uuid.New(), Title: "Fix <script>alert(1)</script>", Status: "IN_PROGRESS", Priority: "H", CreatedAt: time.Now()}
+	s := &Site{
+		Project:   models.Project{ID: uuid.New(), Name: "orion"},
+		Tasks:     []Task{{Task: task, Annotations: []models.Annotation{{Content: "looked into it"}}}},
+		Memories:  []models.Memory{{ID: uuid.New(), Content: "# Tokens expire\nafter 1h", Type: "gotcha"}},
+		Decisions: []api.Decision{{ADRNumber: "ADR-001", Title: "Use Postgres", Status: "ACCEPTED"}},
+	}
+	n, err := Write(dir, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if TaskPath(task) != "tasks/"+task.ID.String()+".html" {
+		t.Errorf("TaskPath = %q, want the full task ID", TaskPath(task))
+	}
+	files := []string{"index.html", "tasks.html", "memories.html", "decisions.html", TaskPath(task), "assets/style.css", "assets/search.js"}
+	if n != len(files) {
+		t.Errorf("wrote %d files, want %d", n, len(files))
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	for _, f := range files {
+		read(f)
+	}
+	page := read(TaskPath(task))
+	if strings.Contains(page, "<script>alert") || !strings.Contains(page, "looked into it") {
+		t.Errorf("task page not escaped or missing notes:\n%s", page)
+	}
+	if !strings.Contains(page, `href="../assets/style.css"`) {
+		t.Error("task page should link assets relative to the site root")
+	}
+	if !strings.Contains(read("memories.html"), "<strong>Tokens expire</strong>") {
+		t.Error("memory title missing")
+	}
+	if !strings.Contains(read("decisions.html"), "Use Postgres") {
+		t.Error("decision missing")
+	}
+	if !strings.Contains(read("index.html"), `<svg class="burndown"`) {
+		t.Error("dashboard has no burndown")
+	}
+}
@@ -0,0 +1,199 @@
+// Package taskwarrior reads and writes the JSON of Taskwarrior's `task export`
+// and `task import`, and maps it onto ramorie tasks.
+package taskwarrior
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// timeLayout is Taskwarrior's UTC date format, e.g. 20250301T120000Z
+const timeLayout = "20060102T150405Z"
+
+// Time is a Taskwarrior date
+type Time struct{ time.Time }
+
+// MarshalJSON writes the date in Taskwarrior's format
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(timeLayout))
+}
+
+// UnmarshalJSON reads Taskwarrior dates and, for hand-edited files, RFC 3339
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for _, layout := range []string{timeLayout, time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q", s)
+}
+
+// Annotation is a timestamped note on a task
+type Annotation struct {
+	Entry       Time   `json:"entry"`
+	Description string `json:"description"`
+}
+
+// Depends is the list of task UUIDs a task waits on. Taskwarrior 2.6+ writes
+// an array; older versions a comma-separated string.
+type Depends []string
+
+// UnmarshalJSON accepts both forms
+func (d *Depends) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*d = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*d = nil
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			*d = append(*d, id)
+		}
+	}
+	return nil
+}
+
+// Task is one task of a Taskwarrior export
+type Task struct {
+	UUID        string       `json:"uuid"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"` // pending, waiting, completed, deleted, recurring
+	Entry       Time         `json:"entry"`
+	Modified    *Time        `json:"modified,omitempty"`
+	Start       *Time        `json:"start,omitempty"`
+	End         *Time        `json:"end,omitempty"`
+	Due         *Time        `json:"due,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Priority    string       `json:"priority,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Depends     Depends      `json:"depends,omitempty"`
+}
+
+// Parse reads a `task export` JSON array, or one JSON object per line as
+// written with rc.json.array=off
+func Parse(r io.Reader) ([]Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] == '[' {
+		var tasks []Task
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, err
+		}
+		return tasks, nil
+	}
+	var tasks []Task
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(strings.TrimSpace(scanner.Text()), ",")
+		if text == "" {
+			continue
+		}
+		var t Task
+		if err := json.Unmarshal([]byte(text), &t); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, scanner.Err()
+}
+
+// Importable reports whether a task becomes a ramorie task; deleted tasks and
+// recurrence templates do not
+func (t Task) Importable() bool {
+	return t.Status != "deleted" && t.Status != "recurring" && strings.TrimSpace(t.Description) != ""
+}
+
+// RamorieStatus maps the Taskwarrior status; started pending tasks are in progress
+func (t Task) RamorieStatus() string {
+	switch {
+	case t.Status == "completed":
+		return "COMPLETED"
+	case t.Start != nil:
+		return "IN_PROGRESS"
+	}
+	return "TODO"
+}
+
+// RamoriePriority maps the priority; tasks without one are medium
+func (t Task) RamoriePriority() string {
+	if p, err := models.ParsePriority(t.Priority); err == nil {
+		return p
+	}
+	return "M"
+}
+
+// FromRamorie converts a task for `task import`. The ramorie ID becomes the
+// UUID, so a task exported and imported again is recognised. Taskwarrior has
+// no task body, so the description is kept as the first annotation.
+func FromRamorie(t models.Task, project string, tags []string) Task {
+	out := Task{
+		UUID:        t.ID.String(),
+		Description: t.Title,
+		Status:      "pending",
+		Entry:       Time{t.CreatedAt},
+		Project:     project,
+		Priority:    t.Priority,
+	}
+	if !t.UpdatedAt.IsZero() {
+		out.Modified = &Time{t.UpdatedAt}
+	}
+	switch t.Status {
+	case "COMPLETED":
+		out.Status = "completed"
+		end := t.UpdatedAt
+		if t.CompletedAt != nil {
+			end = *t.CompletedAt
+		}
+		out.End = &Time{end}
+	case "IN_PROGRESS", "IN_REVIEW":
+		start := t.UpdatedAt
+		if t.StartedAt != nil {
+			start = *t.StartedAt
+		}
+		out.Start = &Time{start}
+	}
+	for _, tag := range tags {
+		// Taskwarrior tags are single words
+		out.Tags = append(out.Tags, strings.Join(strings.Fields(tag), "-"))
+	}
+	if t.DueDate != nil {
+		out.Due = &Time{*t.DueDate}
+	}
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		out.Annotations = append(out.Annotations, Annotation{Entry: Time{t.CreatedAt}, Description: desc})
+	}
+	for _, a := range t.Annotations {
+		out.Annotations = append(out.Annotations, Annotation{Entry: Time{a.CreatedAt}, Description: a.Content})
+	}
+	for _, d := range t.BlockingTasks {
+		out.Depends = append(out.Depends, d.BlockingTaskID.String())
+	}
+	return out
+}
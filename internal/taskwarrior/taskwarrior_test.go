@@ -0,0 +1,80 @@
+package taskwarrior
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+const export = `[
+{"id":1,"description":"Fix login","entry":"20250301T090000Z","modified":"20250302T090000Z","start":"20250302T080000Z","status":"pending","project":"home.web","priority":"H","tags":["auth","bug"],"annotations":[{"entry":"20250301T100000Z","description":"seen on mobile"}],"due":"20250310T000000Z","uuid":"6f1c0e3a-0000-4000-8000-000000000001","urgency":12.3},
+{"id":2,"description":"Write docs","entry":"20250301T090000Z","status":"pending","depends":"6f1c0e3a-0000-4000-8000-000000000001","uuid":"6f1c0e3a-0000-4000-8000-000000000002"},
+{"id":0,"description":"Old","entry":"20250101T090000Z","end":"20250102T090000Z","status":"completed","uuid":"6f1c0e3a-0000-4000-8000-000000000003","depends":["a","b"]},
+{"id":0,"description":"Gone","entry":"20250101T090000Z","status":"deleted","uuid":"6f1c0e3a-0000-4000-8000-000000000004"}
+]`
+
+func TestParse(t *testing.T) {
+	tasks, err := Parse(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("got %d tasks", len(tasks))
+	}
+	fix := tasks[0]
+	if fix.RamorieStatus() != "IN_PROGRESS" || fix.RamoriePriority() != "H" || fix.Project != "home.web" ||
+		len(fix.Annotations) != 1 || fix.Due == nil || !fix.Due.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("fix = %+v", fix)
+	}
+	if d := tasks[1].Depends; len(d) != 1 || d[0] != fix.UUID {
+		t.Errorf("string depends = %v", d)
+	}
+	if d := tasks[2].Depends; len(d) != 2 || tasks[2].RamorieStatus() != "COMPLETED" {
+		t.Errorf("completed = %+v", tasks[2])
+	}
+	if tasks[1].RamoriePriority() != "M" || tasks[3].Importable() {
+		t.Error("missing priority should be M and deleted tasks are not imported")
+	}
+
+	lines, err := Parse(strings.NewReader(`{"description":"a","entry":"20250301T090000Z","status":"pending","uuid":"x"}` + "\n" +
+		`{"description":"b","entry":"20250301T090000Z","status":"waiting","uuid":"y"}`))
+	if err != nil || len(lines) != 2 || lines[1].RamorieStatus() != "TODO" {
+		t.Errorf("json lines = %+v, %v", lines, err)
+	}
+}
+
+func TestFromRamorie(t *testing.T) {
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	done := created.Add(48 * time.Hour)
+	blocker := uuid.New()
+	task := models.Task{
+		ID: uuid.New(), Title: "Ship it", Description: "All the details", Status: "COMPLETED", Priority: "H",
+		CreatedAt: created, UpdatedAt: done, CompletedAt: &done,
+		Annotations:   []models.Annotation{{Content: "halfway", CreatedAt: created.Add(time.Hour)}},
+		BlockingTasks: []models.Dependency{{BlockingTaskID: blocker}},
+	}
+	out := FromRamorie(task, "orion", []string{"release", "needs review"})
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"uuid":"` + task.ID.String() + `"`, `"description":"Ship it"`, `"status":"completed"`,
+		`"entry":"20250301T090000Z"`, `"end":"20250303T090000Z"`, `"project":"orion"`,
+		`"tags":["release","needs-review"]`, `"description":"All the details"`, `"depends":["` + blocker.String() + `"]`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %s in %s", want, data)
+		}
+	}
+
+	// What we write reads back the same way
+	back, err := Parse(strings.NewReader("[" + string(data) + "]"))
+	if err != nil || len(back) != 1 || back[0].RamorieStatus() != "COMPLETED" || len(back[0].Annotations) != 2 {
+		t.Errorf("round trip = %+v, %v", back, err)
+	}
+}
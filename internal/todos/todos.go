@@ -0,0 +1,271 @@
+// Package todos finds TODO, FIXME and HACK comments in source code. Each
+// comment gets a fingerprint that survives it moving within its file, so
+// tasks created from comments can be matched with them on later scans.
+package todos
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+)
+
+// Kinds are the comment markers found, in upper case only so prose like
+// "a todo list" is not picked up
+var Kinds = []string{"TODO", "FIXME", "HACK"}
+
+// maxFileSize skips generated and data files
+const maxFileSize = 1 << 20
+
+// Item is a TODO comment
+type Item struct {
+	File        string `json:"file"` // slash-separated, relative to the repository root
+	Line        int    `json:"line"`
+	Kind        string `json:"kind"`
+	Owner       string `json:"owner,omitempty"`
+	Priority    string `json:"priority"`
+	Text        string `json:"text"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// comment matches a marker right after a comment leader: //, #, /*, a
+// leading * of a block comment, --, ;, <!--, % or a docstring quote
+var comment = regexp.MustCompile(`(?://+|#+|/\*+|^\s*\*+|--|;+|<!--|%+|"""|''')\s*(` +
+	strings.Join(Kinds, "|") + `)(?:$|[\s:(\[-])`)
+
+// ParseLine returns the TODO comment on a line of code, if any. The marker may
+// be followed by an owner in parentheses and a priority in brackets, in either
+// order: "// TODO(alice) [high]: retry on 503".
+func ParseLine(line string) (Item, bool) {
+	m := comment.FindStringSubmatchIndex(line)
+	if m == nil {
+		return Item{}, false
+	}
+	it := Item{Kind: line[m[2]:m[3]]}
+	rest := line[m[3]:]
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		var open, close byte
+		switch {
+		case strings.HasPrefix(rest, "(") && it.Owner == "":
+			open, close = '(', ')'
+		case strings.HasPrefix(rest, "[") && it.Priority == "":
+			open, close = '[', ']'
+		}
+		end := strings.IndexByte(rest, close)
+		if open == 0 || end < 0 {
+			break
+		}
+		value := strings.TrimSpace(rest[1:end])
+		if open == '(' {
+			it.Owner = strings.TrimPrefix(value, "@")
+		} else if p, err := models.ParsePriority(value); err == nil && value != "" {
+			it.Priority = p
+		} else {
+			// Not a priority, e.g. "TODO [#123]": part of the text
+			break
+		}
+		rest = rest[end+1:]
+	}
+	rest = strings.TrimLeft(rest, " \t:-")
+	for _, suffix := range []string{"*/", "-->", `"""`, "'''"} {
+		rest = strings.TrimSuffix(strings.TrimSpace(rest), suffix)
+	}
+	it.Text = strings.Join(strings.Fields(rest), " ")
+	if it.Priority == "" {
+		it.Priority = DefaultPriority(it.Kind)
+	}
+	return it, true
+}
+
+// DefaultPriority is the priority of a comment without [priority]: FIXME is
+// high, HACK low and TODO medium
+func DefaultPriority(kind string) string {
+	switch kind {
+	case "FIXME":
+		return "H"
+	case "HACK":
+		return "L"
+	}
+	return "M"
+}
+
+// Title is the task title for the comment
+func (it Item) Title() string {
+	if it.Text == "" {
+		return fmt.Sprintf("%s in %s:%d", it.Kind, it.File, it.Line)
+	}
+	if r := []rune(it.Text); len(r) > 120 {
+		return string(r[:117]) + "..."
+	}
+	return it.Text
+}
+
+// Description is the task description; its last line is the reference
+// CodeRef reads back
+func (it Item) Description() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s at %s:%d", it.Kind, it.File, it.Line)
+	if it.Owner != "" {
+		fmt.Fprintf(&b, " (owner: %s)", it.Owner)
+	}
+	b.WriteString("\n\n")
+	if it.Text != "" && it.Title() != it.Text {
+		b.WriteString(it.Text + "\n\n")
+	}
+	fmt.Fprintf(&b, "%s %s %s", refPrefix, it.Fingerprint, it.File)
+	return b.String()
+}
+
+const refPrefix = "Code-TODO:"
+
+var refLine = regexp.MustCompile(`(?m)^` + refPrefix + ` ([0-9a-f]{12}) (.+?)\s*$`)
+
+// CodeRef is the fingerprint and file of the comment a task description was
+// created from, or "" when it was not created by a scan
+func CodeRef(description string) (fingerprint, file string) {
+	m := refLine.FindAllStringSubmatch(description, -1)
+	if len(m) == 0 {
+		return "", ""
+	}
+	return m[len(m)-1][1], m[len(m)-1][2]
+}
+
+// InScope reports whether file lies under scope, a slash-separated path
+// relative to the repository root; "" is the whole repository
+func InScope(file, scope string) bool {
+	return scope == "" || file == scope || strings.HasPrefix(file, scope+"/")
+}
+
+// ParseFile returns the TODO comments in the file at path, named file in the
+// results. Binary and very large files have none.
+func ParseFile(path, file string) ([]Item, error) {
+	data, ok, err := readSource(path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return parse(data, file)
+}
+
+// readSource reads a source file; ok is false for binary and very large files
+func readSource(path string) (data []byte, ok bool, err error) {
+	data, err = os.ReadFile(path)
+	if err != nil || len(data) > maxFileSize {
+		return nil, false, err
+	}
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return data, bytes.IndexByte(head, 0) < 0, nil
+}
+
+func parse(data []byte, file string) ([]Item, error) {
+	var items []Item
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		it, ok := ParseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		it.File, it.Line = file, line
+		// Line numbers are left out, so edits above the comment keep the
+		// fingerprint; repeats of the same comment are told apart by order
+		key := it.Kind + "\x00" + it.Text
+		it.Fingerprint = fingerprint(file, key, seen[key])
+		seen[key]++
+		items = append(items, it)
+	}
+	return items, scanner.Err()
+}
+
+func fingerprint(file, key string, n int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d", file, key, n)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Result is what Scan found
+type Result struct {
+	Items []Item
+	// Scope is the scanned path relative to the repository root; "" is the
+	// whole repository. Outside a git repository, file names and the scope
+	// are relative to the scanned directory.
+	Scope string
+	// Parsed holds the files that were read; ignored, excluded, binary and
+	// very large files were not
+	Parsed map[string]bool
+
+	root string
+}
+
+// Covers reports whether the scan would have seen a comment in file: the file
+// was read, or it lies in the scope and no longer exists. A comment in a
+// covered file that is not among the items has been removed.
+func (r *Result) Covers(file string) bool {
+	if r.Parsed[file] {
+		return true
+	}
+	if !InScope(file, r.Scope) {
+		return false
+	}
+	_, err := os.Lstat(filepath.Join(r.root, filepath.FromSlash(file)))
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// Scan finds the TODO comments under path, a directory or a file. Files
+// ignored by .gitignore are skipped, as are files matching one of the exclude
+// globs.
+func Scan(path string, exclude []string) (*Result, error) {
+	files, root, scope, err := listFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var skip []*regexp.Regexp
+	for _, pattern := range exclude {
+		skip = append(skip, globRegexp(strings.Trim(pattern, "/")))
+	}
+	res := &Result{Scope: scope, Parsed: map[string]bool{}, root: root}
+	for _, file := range files {
+		if excluded(file, skip) {
+			continue
+		}
+		data, ok, err := readSource(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if !ok {
+			continue
+		}
+		found, err := parse(data, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		res.Parsed[file] = true
+		res.Items = append(res.Items, found...)
+	}
+	return res, nil
+}
+
+// excluded matches the globs against the path and each of its parent directories
+func excluded(file string, globs []*regexp.Regexp) bool {
+	for _, re := range globs {
+		for p := file; p != "."; p = filepath.ToSlash(filepath.Dir(p)) {
+			if re.MatchString(p) || re.MatchString(p[strings.LastIndex(p, "/")+1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package todos
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want Item
+		ok   bool
+	}{
+		{"\t// TODO(alice) [high]: retry on 503", Item{Kind: "TODO", Owner: "alice", Priority: "H", Text: "retry on 503"}, true},
+		{"x := 1 # FIXME [l] (@bob) - drop this */", Item{Kind: "FIXME", Owner: "bob", Priority: "L", Text: "drop this"}, true},
+		{"/* HACK */", Item{Kind: "HACK", Priority: "L"}, true},
+		{" * TODO [#123] follow up", Item{Kind: "TODO", Priority: "M", Text: "[#123] follow up"}, true},
+		{"<!-- TODO: translate -->", Item{Kind: "TODO", Priority: "M", Text: "translate"}, true},
+		{"-- FIXME:  slow   query", Item{Kind: "FIXME", Priority: "H", Text: "slow query"}, true},
+		{`fmt.Println("TODO: not a comment")`, Item{}, false},
+		{"// todo lowercase is prose", Item{}, false},
+		{"// see TODOS.md", Item{}, false},
+		{"a := b * TODO", Item{}, false},
+	} {
+		got, ok := ParseLine(tc.line)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("ParseLine(%q) = %+v, %v; want %+v, %v", tc.line, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestFingerprintAndCodeRef(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	os.WriteFile(path, []byte("// TODO: one\n// TODO: one\n// FIXME: two\n"), 0644)
+	before, err := ParseFile(path, "pkg/a.go")
+	if err != nil || len(before) != 3 {
+		t.Fatalf("items = %+v, %v", before, err)
+	}
+	if before[0].Fingerprint == before[1].Fingerprint {
+		t.Error("repeated comments must get different fingerprints")
+	}
+
+	// Lines added above keep the fingerprints
+	os.WriteFile(path, []byte("package a\n\n// TODO: one\n// TODO: one\n// FIXME: two\n"), 0644)
+	after, _ := ParseFile(path, "pkg/a.go")
+	for i := range after {
+		if after[i].Fingerprint != before[i].Fingerprint || after[i].Line != before[i].Line+2 {
+			t.Errorf("item %d: %+v, was %+v", i, after[i], before[i])
+		}
+	}
+
+	fp, file := CodeRef(after[2].Description())
+	if fp != after[2].Fingerprint || file != "pkg/a.go" {
+		t.Errorf("CodeRef = %q, %q", fp, file)
+	}
+	if !strings.HasPrefix(after[2].Description(), "FIXME at pkg/a.go:5\n") {
+		t.Errorf("description = %q", after[2].Description())
+	}
+	if fp, _ := CodeRef("Source: https://example.com"); fp != "" {
+		t.Error("CodeRef of a plain description")
+	}
+
+	os.WriteFile(path, []byte("// TODO: one\x00binary"), 0644)
+	if items, _ := ParseFile(path, "a.go"); len(items) != 0 {
+		t.Errorf("binary file: %+v", items)
+	}
+}
+
+func TestScanGitignore(t *testing.T) {
+	files := map[string]string{
+		".gitignore":         "*.log\n/build/\nvendor/\n!keep.log\n",
+		"main.go":            "// TODO: main\n",
+		"debug.log":          "# TODO: ignored\n",
+		"keep.log":           "# TODO: kept\n",
+		"build/out.go":       "// TODO: ignored\n",
+		"sub/build/x.go":     "// TODO: not anchored here\n",
+		"sub/vendor/lib.go":  "// TODO: ignored\n",
+		"sub/.gitignore":     "gen_*.go\n",
+		"sub/gen_api.go":     "// TODO: ignored\n",
+		"sub/fixtures/a.txt": "# TODO: excluded\n",
+	}
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(content), 0644)
+		}
+		return dir
+	}
+	want := "keep.log main.go sub/build/x.go"
+	check := func(t *testing.T, dir, subScope string) {
+		res, err := Scan(dir, []string{"fixtures"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, it := range res.Items {
+			got = append(got, it.File)
+		}
+		sort.Strings(got)
+		if strings.Join(got, " ") != want || res.Scope != "" {
+			t.Errorf("files = %v, scope %q; want %s", got, res.Scope, want)
+		}
+		if sub, _ := Scan(filepath.Join(dir, "sub"), nil); sub == nil || sub.Scope != subScope {
+			t.Errorf("scope of sub = %+v", sub)
+		}
+	}
+
+	t.Run("walk", func(t *testing.T) { check(t, setup(t), "") }) // no repository: paths are relative to the scanned directory
+	t.Run("git", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git not installed")
+		}
+		dir := setup(t)
+		if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+			t.Fatalf("%v: %s", err, out)
+		}
+		check(t, dir, "sub")
+	})
+}
+
+func TestScanCovers(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":       "// TODO: main\n",
+		"vendor/lib.go": "// TODO: vendored\n",
+		"sub/a.go":      "// TODO: a\n",
+		"data.bin":      "# TODO: binary\x00",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	res, err := Scan(dir, []string{"vendor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]bool{
+		"main.go":       true,  // read
+		"sub/gone.go":   true,  // deleted since the last scan
+		"vendor/lib.go": false, // excluded
+		"data.bin":      false, // binary
+	} {
+		if got := res.Covers(file); got != want {
+			t.Errorf("Covers(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package todos
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/terzigolu/josepshbrain-go/internal/gitlink"
+)
+
+// listFiles returns the files under path relative to root, the repository
+// root, and path relative to root as the scope of the scan. In a git
+// repository git itself lists the files, so every ignore source (.gitignore
+// files, info/exclude, the global excludes file) is honoured; elsewhere
+// .gitignore files are read during the walk.
+func listFiles(path string) (files []string, root, scope string, err error) {
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, "", "", err
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return nil, "", "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", "", err
+	}
+	dir, pathspec := path, "."
+	if !info.IsDir() {
+		dir, pathspec = filepath.Dir(path), filepath.Base(path)
+	}
+
+	if top, err := gitlink.Git(dir, "rev-parse", "--show-toplevel"); err == nil {
+		if root, err = filepath.EvalSymlinks(top); err != nil {
+			return nil, "", "", err
+		}
+		out, err := gitlink.Git(dir, "ls-files", "-z", "--cached", "--others", "--exclude-standard", "--full-name", "--", pathspec)
+		if err != nil {
+			return nil, "", "", err
+		}
+		for _, file := range strings.Split(out, "\x00") {
+			// Deleted files are still listed until staged, submodules are directories
+			if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(file))); file != "" && err == nil && info.Mode().IsRegular() {
+				files = append(files, file)
+			}
+		}
+		scope, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if scope = filepath.ToSlash(scope); scope == "." {
+			scope = ""
+		}
+		return files, root, scope, nil
+	}
+
+	if !info.IsDir() {
+		return []string{filepath.Base(path)}, dir, filepath.Base(path), nil
+	}
+	var rules []ignoreRule
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" || rel != "." && ignored(rules, rel, true) {
+				return filepath.SkipDir
+			}
+			base := rel
+			if base == "." {
+				base = ""
+			}
+			rules = append(rules, readIgnore(filepath.Join(p, ".gitignore"), base)...)
+			return nil
+		}
+		if d.Type().IsRegular() && !ignored(rules, rel, false) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, path, "", err
+}
+
+// ignoreRule is a .gitignore pattern of the directory base
+type ignoreRule struct {
+	base     string
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool // matched against the path below base rather than the name
+}
+
+func readIgnore(path, base string) []ignoreRule {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.re = globRegexp(strings.TrimPrefix(line, "/"))
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// ignored applies the rules in order; the last matching rule wins
+func ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	result := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir || !InScope(rel, r.base) || rel == r.base {
+			continue
+		}
+		sub := strings.TrimPrefix(strings.TrimPrefix(rel, r.base), "/")
+		if !r.anchored {
+			sub = sub[strings.LastIndex(sub, "/")+1:]
+		}
+		if r.re.MatchString(sub) {
+			result = !r.negate
+		}
+	}
+	return result
+}
+
+// globRegexp compiles a gitignore glob: * and ? stay within a path
+// segment, ** spans segments
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end > 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+				i += end + 1
+				continue
+			}
+			b.WriteString(`\[`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		// A malformed class; match the pattern literally
+		return regexp.MustCompile("^" + regexp.QuoteMeta(glob) + "$")
+	}
+	return re
+}
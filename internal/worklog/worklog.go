@@ -0,0 +1,151 @@
+// Package worklog records when tasks are started and stopped from this machine,
+// so the time spent on them can be shown as work sessions.
+package worklog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/config"
+)
+
+// fileName is the log inside the config directory
+const fileName = "worklog.jsonl"
+
+// Event actions
+const (
+	Start = "start"
+	Stop  = "stop"
+)
+
+// Event is a task being started or stopped
+type Event struct {
+	Time   time.Time `json:"time"`
+	TaskID string    `json:"task_id"` // full ID on starts; stops (and older logs) may carry a short ID
+	Action string    `json:"action"`
+}
+
+// Session is an uninterrupted stretch of work on one task
+type Session struct {
+	TaskID     string
+	Start, End time.Time
+	// Open sessions have not been stopped yet; End is the time they were read
+	Open bool
+}
+
+// Duration is how long the session lasted
+func (s Session) Duration() time.Duration { return s.End.Sub(s.Start) }
+
+// DefaultPath returns the log path (~/.ramorie/worklog.jsonl)
+func DefaultPath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Record appends an event for taskID to the default log
+func Record(taskID, action string) error {
+	path, err := DefaultPath()
+	if err != nil {
+		return err
+	}
+	return Append(path, Event{Time: time.Now(), TaskID: taskID, Action: action})
+}
+
+// Append writes an event to the log at path
+func Append(path string, ev Event) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the events of the log at path; a missing log has none
+func Read(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+		events = append(events, ev)
+	}
+	return events, scanner.Err()
+}
+
+// Sessions pairs starts with stops. Only one task is active at a time, so
+// starting a task also ends the session of the previous one. A session still
+// running at now is returned as open.
+func Sessions(events []Event, now time.Time) []Session {
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var sessions []Session
+	var open *Session
+	end := func(at time.Time) {
+		if open != nil && at.After(open.Start) {
+			open.End = at
+			sessions = append(sessions, *open)
+		}
+		open = nil
+	}
+	for _, ev := range sorted {
+		switch ev.Action {
+		case Start:
+			if open != nil && sameTask(open.TaskID, ev.TaskID) {
+				continue
+			}
+			end(ev.Time)
+			open = &Session{TaskID: ev.TaskID, Start: ev.Time}
+		case Stop:
+			if open != nil && sameTask(open.TaskID, ev.TaskID) {
+				end(ev.Time)
+			}
+		}
+	}
+	if open != nil && now.After(open.Start) {
+		open.End, open.Open = now, true
+		sessions = append(sessions, *open)
+	}
+	return sessions
+}
+
+// sameTask compares IDs that may be short prefixes of each other
+func sameTask(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return a != "" && b != "" && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a))
+}
@@ -0,0 +1,50 @@
+package worklog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 3, h, m, 0, 0, time.UTC) }
+	events := []Event{
+		{Time: at(9, 0), TaskID: "aaaaaaa1", Action: Start},
+		{Time: at(9, 5), TaskID: "aaaaaaa1-0000-4000-8000-000000000001", Action: Start}, // already running
+		{Time: at(10, 0), TaskID: "aaaaaaa1", Action: Stop},
+		{Time: at(11, 0), TaskID: "bbbbbbb2", Action: Start},
+		{Time: at(12, 30), TaskID: "ccccccc3", Action: Start}, // ends bbbbbbb2
+		{Time: at(13, 0), TaskID: "bbbbbbb2", Action: Stop},   // not the open session
+		{Time: at(14, 0), TaskID: "ddddddd4", Action: Stop},   // nothing open
+	}
+	got := Sessions(events, at(15, 0))
+	if len(got) != 3 {
+		t.Fatalf("sessions = %+v", got)
+	}
+	want := []struct {
+		task string
+		d    time.Duration
+		open bool
+	}{{"aaaaaaa1", time.Hour, false}, {"bbbbbbb2", 90 * time.Minute, false}, {"ccccccc3", 150 * time.Minute, true}}
+	for i, w := range want {
+		if got[i].TaskID != w.task || got[i].Duration() != w.d || got[i].Open != w.open {
+			t.Errorf("session %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "worklog.jsonl")
+	if events, err := Read(path); err != nil || len(events) != 0 {
+		t.Fatalf("missing log = %v, %v", events, err)
+	}
+	for _, action := range []string{Start, Stop} {
+		if err := Append(path, Event{Time: time.Now(), TaskID: "aaaaaaa1", Action: action}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := Read(path)
+	if err != nil || len(events) != 2 || events[1].Action != Stop {
+		t.Errorf("events = %+v, %v", events, err)
+	}
+}
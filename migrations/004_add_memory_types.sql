@@ -0,0 +1,6 @@
+-- Add structured memory types (snippet, gotcha, how-to, reference) and their fields
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS type VARCHAR(32);
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS fields JSONB;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_memories_type ON memories(type);
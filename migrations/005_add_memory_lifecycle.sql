@@ -0,0 +1,10 @@
+-- Add memory expiry, review dates, evergreen flag and archiving
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS review_after TIMESTAMP WITH TIME ZONE;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS evergreen BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_memories_expires_at ON memories(expires_at);
+CREATE INDEX IF NOT EXISTS idx_memories_review_after ON memories(review_after);
+CREATE INDEX IF NOT EXISTS idx_memories_archived_at ON memories(archived_at);
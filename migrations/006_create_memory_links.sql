@@ -0,0 +1,13 @@
+-- Create memory_links table (typed relations between memories)
+CREATE TABLE IF NOT EXISTS memory_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    memory_id UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    target_memory_id UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    relation_type VARCHAR(50) NOT NULL DEFAULT 'see-also',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(memory_id, target_memory_id, relation_type),
+    CHECK (memory_id <> target_memory_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_memory_links_target_memory_id ON memory_links(target_memory_id);
@@ -0,0 +1,21 @@
+-- Create attachments table (files attached to tasks and memories)
+-- Content is stored on disk by its SHA-256; text_content holds the text of
+-- text attachments so recall can search it.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    owner_type VARCHAR(20) NOT NULL CHECK (owner_type IN ('task', 'memory')),
+    owner_id UUID NOT NULL,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    text_content TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(owner_id, sha256)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_attachments_owner ON attachments(owner_type, owner_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_project_id ON attachments(project_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_sha256 ON attachments(sha256);
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +17,17 @@ type Memory struct {
 	CreatedAt time.Time  `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time  `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 
+	// Structured memory type (snippet, gotcha, how-to, reference); empty for plain notes
+	Type   string         `json:"type,omitempty" gorm:"type:varchar(32);index"`
+	Fields datatypes.JSON `json:"fields,omitempty" gorm:"type:jsonb"`
+
+	// Lifecycle: expired and archived memories are hidden from recall, memories
+	// past ReviewAfter show up in the review queue, evergreen ones do not decay
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	ReviewAfter *time.Time `json:"review_after,omitempty" gorm:"index"`
+	Evergreen   bool       `json:"evergreen,omitempty" gorm:"not null;default:false"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" gorm:"index"`
+
 	// Active task linking (returned from API when memory is auto-linked)
 	LinkedTaskID *uuid.UUID `json:"linked_task_id,omitempty" gorm:"-"`
 
@@ -76,6 +88,34 @@ type MemoryTaskLink struct {
 	MemoryItem *MemoryItem `json:"memory_item,omitempty" gorm:"foreignKey:MemoryID;constraint:OnDelete:CASCADE"`
 }
 
+// MemoryRevision represents the memory_revisions table.
+// Every content change of a memory is stored as a new version, starting at 1.
+type MemoryRevision struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	MemoryID  uuid.UUID `json:"memory_id" gorm:"not null;type:uuid;uniqueIndex:idx_memory_revision_version"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_memory_revision_version"`
+	Content   string    `json:"content" gorm:"not null"`
+	Author    string    `json:"author" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	// Foreign Key Relations
+	Memory *Memory `json:"memory,omitempty" gorm:"foreignKey:MemoryID;constraint:OnDelete:CASCADE"`
+}
+
+// MemoryLink represents the memory_links table: a typed relation from one
+// memory to another (supersedes, contradicts, elaborates, see-also)
+type MemoryLink struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	MemoryID       uuid.UUID `json:"memory_id" gorm:"not null;type:uuid;uniqueIndex:idx_memory_link"`
+	TargetMemoryID uuid.UUID `json:"target_memory_id" gorm:"not null;type:uuid;uniqueIndex:idx_memory_link;index"`
+	RelationType   string    `json:"relation_type" gorm:"type:varchar(50);not null;default:'see-also';uniqueIndex:idx_memory_link"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	// Foreign Key Relations
+	Memory       *Memory `json:"memory,omitempty" gorm:"foreignKey:MemoryID;constraint:OnDelete:CASCADE"`
+	TargetMemory *Memory `json:"target_memory,omitempty" gorm:"foreignKey:TargetMemoryID;constraint:OnDelete:CASCADE"`
+}
+
 // TableName specifies the table name for GORM
 func (Memory) TableName() string {
 	return "memories"
@@ -92,3 +132,11 @@ func (TaskMemory) TableName() string {
 func (MemoryTaskLink) TableName() string {
 	return "memory_task_links"
 }
+
+func (MemoryRevision) TableName() string {
+	return "memory_revisions"
+}
+
+func (MemoryLink) TableName() string {
+	return "memory_links"
+}
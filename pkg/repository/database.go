@@ -69,6 +69,8 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Annotation{},
 		&models.Dependency{},
 		&models.Memory{},
+		&models.MemoryRevision{},
+		&models.MemoryLink{},
 		&models.MemoryItem{},
 		&models.TaskMemory{},
 		&models.MemoryTaskLink{},
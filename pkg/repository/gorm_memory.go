@@ -2,6 +2,7 @@ package repository
 
 import (
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/terzigolu/josepshbrain-go/pkg/models"
@@ -43,7 +44,23 @@ func (r *gormMemoryRepository) GetAll() ([]models.Memory, error) {
 }
 
 func (r *gormMemoryRepository) Update(memory *models.Memory) error {
-	return r.db.Save(memory).Error
+	return r.UpdateWithAuthor(memory, "")
+}
+
+func (r *gormMemoryRepository) UpdateWithAuthor(memory *models.Memory, author string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Memory
+		if err := tx.Where("id = ?", memory.ID).First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(memory).Error; err != nil {
+			return err
+		}
+		if current.Content == memory.Content {
+			return nil
+		}
+		return addRevision(tx, &current, memory.Content, author)
+	})
 }
 
 func (r *gormMemoryRepository) Delete(id uuid.UUID) error {
@@ -74,3 +91,24 @@ func (r *gormMemoryRepository) GetByTags(tags []string) ([]models.Memory, error)
 	err := query.Find(&memories).Error
 	return memories, err
 }
+
+func (r *gormMemoryRepository) GetDueForReview(projectID *uuid.UUID, now time.Time) ([]models.Memory, error) {
+	var memories []models.Memory
+	query := r.db.Preload("Tags").
+		Where("archived_at IS NULL").
+		Where("review_after <= ? OR expires_at <= ?", now, now)
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	err := query.Order("COALESCE(review_after, expires_at) ASC").Find(&memories).Error
+	return memories, err
+}
+
+func (r *gormMemoryRepository) GetByType(memoryType string) ([]models.Memory, error) {
+	var memories []models.Memory
+	err := r.db.Preload("Tags").
+		Where("type = ?", memoryType).
+		Order("created_at DESC").
+		Find(&memories).Error
+	return memories, err
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormMemoryLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewMemoryLinkRepository creates a new GORM memory link repository
+func NewMemoryLinkRepository(db *gorm.DB) MemoryLinkRepository {
+	return &gormMemoryLinkRepository{db: db}
+}
+
+func (r *gormMemoryLinkRepository) Create(link *models.MemoryLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *gormMemoryLinkRepository) GetByMemoryID(memoryID uuid.UUID) ([]models.MemoryLink, error) {
+	var links []models.MemoryLink
+	err := r.db.Where("memory_id = ? OR target_memory_id = ?", memoryID, memoryID).
+		Order("created_at ASC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *gormMemoryLinkRepository) GetByProjectID(projectID uuid.UUID) ([]models.MemoryLink, error) {
+	var links []models.MemoryLink
+	err := r.db.Joins("JOIN memories ON memories.id = memory_links.memory_id").
+		Where("memories.project_id = ?", projectID).
+		Order("memory_links.created_at ASC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *gormMemoryLinkRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.MemoryLink{}, "id = ?", id).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/pkg/models"
+	"gorm.io/gorm"
+)
+
+type gormMemoryRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewMemoryRevisionRepository creates a new GORM memory revision repository
+func NewMemoryRevisionRepository(db *gorm.DB) MemoryRevisionRepository {
+	return &gormMemoryRevisionRepository{db: db}
+}
+
+func (r *gormMemoryRevisionRepository) Create(revision *models.MemoryRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *gormMemoryRevisionRepository) GetByMemoryID(memoryID uuid.UUID) ([]models.MemoryRevision, error) {
+	var revisions []models.MemoryRevision
+	err := r.db.Where("memory_id = ?", memoryID).Order("version ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *gormMemoryRevisionRepository) GetVersion(memoryID uuid.UUID, version int) (*models.MemoryRevision, error) {
+	var revision models.MemoryRevision
+	err := r.db.Where("memory_id = ? AND version = ?", memoryID, version).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *gormMemoryRevisionRepository) Restore(memoryID uuid.UUID, version int, author string) (*models.Memory, error) {
+	var memory models.Memory
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var revision models.MemoryRevision
+		if err := tx.Where("memory_id = ? AND version = ?", memoryID, version).First(&revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", memoryID).First(&memory).Error; err != nil {
+			return err
+		}
+		if memory.Content == revision.Content {
+			return nil
+		}
+		current := memory
+		memory.Content = revision.Content
+		if err := tx.Save(&memory).Error; err != nil {
+			return err
+		}
+		return addRevision(tx, &current, revision.Content, author)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// addRevision appends content as the next version of a memory. Memories created
+// before revisions existed get their previous content stored as version 1 first.
+func addRevision(tx *gorm.DB, previous *models.Memory, content, author string) error {
+	var latest int
+	if err := tx.Model(&models.MemoryRevision{}).
+		Where("memory_id = ?", previous.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	if latest == 0 {
+		if err := tx.Create(&models.MemoryRevision{
+			MemoryID:  previous.ID,
+			Version:   1,
+			Content:   previous.Content,
+			CreatedAt: previous.CreatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		latest = 1
+	}
+
+	return tx.Create(&models.MemoryRevision{
+		MemoryID: previous.ID,
+		Version:  latest + 1,
+		Content:  content,
+		Author:   author,
+	}).Error
+}
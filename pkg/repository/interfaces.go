@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/terzigolu/josepshbrain-go/pkg/models"
 )
@@ -40,9 +42,33 @@ type MemoryRepository interface {
 	GetByProjectID(projectID uuid.UUID) ([]models.Memory, error)
 	GetAll() ([]models.Memory, error)
 	Update(memory *models.Memory) error
+	// UpdateWithAuthor saves the memory and records its new content as a revision
+	UpdateWithAuthor(memory *models.Memory, author string) error
 	Delete(id uuid.UUID) error
 	Search(query string) ([]models.Memory, error)
 	GetByTags(tags []string) ([]models.Memory, error)
+	GetByType(memoryType string) ([]models.Memory, error)
+	// GetDueForReview returns unarchived memories that are past their review date or expired
+	GetDueForReview(projectID *uuid.UUID, now time.Time) ([]models.Memory, error)
+}
+
+// MemoryRevisionRepository defines the interface for memory version history
+type MemoryRevisionRepository interface {
+	Create(revision *models.MemoryRevision) error
+	GetByMemoryID(memoryID uuid.UUID) ([]models.MemoryRevision, error)
+	GetVersion(memoryID uuid.UUID, version int) (*models.MemoryRevision, error)
+	// Restore makes an old version the current content, recorded as a new revision
+	Restore(memoryID uuid.UUID, version int, author string) (*models.Memory, error)
+}
+
+// MemoryLinkRepository defines the interface for memory-to-memory relations
+type MemoryLinkRepository interface {
+	Create(link *models.MemoryLink) error
+	// GetByMemoryID returns the links from or to a memory
+	GetByMemoryID(memoryID uuid.UUID) ([]models.MemoryLink, error)
+	// GetByProjectID returns the links between memories of a project
+	GetByProjectID(projectID uuid.UUID) ([]models.MemoryLink, error)
+	Delete(id uuid.UUID) error
 }
 
 // ContextRepository defines the interface for context operations
@@ -94,11 +120,13 @@ type OrganizationRepository interface {
 
 // Repository aggregates all repository interfaces
 type Repository struct {
-	Project      ProjectRepository
-	Task         TaskRepository
-	Memory       MemoryRepository
-	Context      ContextRepository
-	Tag          TagRepository
-	Annotation   AnnotationRepository
-	Organization OrganizationRepository
+	Project        ProjectRepository
+	Task           TaskRepository
+	Memory         MemoryRepository
+	MemoryRevision MemoryRevisionRepository
+	MemoryLink     MemoryLinkRepository
+	Context        ContextRepository
+	Tag            TagRepository
+	Annotation     AnnotationRepository
+	Organization   OrganizationRepository
 }
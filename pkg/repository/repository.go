@@ -7,11 +7,13 @@ import (
 // NewRepository creates a new repository with all sub-repositories
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		Project:    NewProjectRepository(db),
-		Task:       NewTaskRepository(db),
-		Memory:     NewMemoryRepository(db),
-		Context:    NewContextRepository(db),
-		Tag:        NewTagRepository(db),
-		Annotation: NewAnnotationRepository(db),
+		Project:        NewProjectRepository(db),
+		Task:           NewTaskRepository(db),
+		Memory:         NewMemoryRepository(db),
+		MemoryRevision: NewMemoryRevisionRepository(db),
+		MemoryLink:     NewMemoryLinkRepository(db),
+		Context:        NewContextRepository(db),
+		Tag:            NewTagRepository(db),
+		Annotation:     NewAnnotationRepository(db),
 	}
 }
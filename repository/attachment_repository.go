@@ -0,0 +1,173 @@
+// attachment_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/terzigolu/josepshbrain-go/internal/attach"
+	"github.com/terzigolu/josepshbrain-go/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownOwner is returned when an attachment's task or memory does not exist
+var ErrUnknownOwner = errors.New("task or memory not found")
+
+type AttachmentRepository struct {
+	DB *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{DB: db}
+}
+
+// AttachmentFilter narrows ListAttachments; empty fields match everything
+type AttachmentFilter struct {
+	OwnerType string
+	OwnerID   *uuid.UUID
+	ProjectID *uuid.UUID
+	Search    string
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+const attachmentColumns = `id, owner_type, owner_id, project_id, name, mime_type, size, sha256, created_at`
+
+// OwnerProject returns the project of the task or memory an attachment belongs to
+func (r *AttachmentRepository) OwnerProject(ctx context.Context, ownerType string, ownerID uuid.UUID) (*uuid.UUID, error) {
+	table := map[string]string{"task": "tasks", "memory": "memories"}[ownerType]
+	if table == "" {
+		return nil, fmt.Errorf("owner_type must be task or memory")
+	}
+	var projectID uuid.NullUUID
+	err := r.DB.QueryRowContext(ctx, `SELECT project_id FROM `+table+` WHERE id = $1`, ownerID).Scan(&projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnknownOwner
+	}
+	if err != nil || !projectID.Valid {
+		return nil, err
+	}
+	return &projectID.UUID, nil
+}
+
+// CreateAttachment stores attachment metadata. Attaching the same content to
+// the same owner again returns the existing attachment.
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, a *models.Attachment, text string) (*models.Attachment, bool, error) {
+	if existing, err := r.scanOne(r.DB.QueryRowContext(ctx, `
+		SELECT `+attachmentColumns+` FROM attachments WHERE owner_id = $1 AND sha256 = $2
+	`, a.OwnerID, a.SHA256)); err == nil {
+		return existing, false, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, err
+	}
+
+	a.ID = uuid.New()
+	a.CreatedAt = time.Now()
+	var textContent sql.NullString
+	if text != "" {
+		textContent = sql.NullString{String: text, Valid: true}
+	}
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO attachments (id, owner_type, owner_id, project_id, name, mime_type, size, sha256, text_content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, a.ID, a.OwnerType, a.OwnerID, a.ProjectID, a.Name, a.MimeType, a.Size, a.SHA256, textContent, a.CreatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+	return a, true, nil
+}
+
+// ListAttachments returns matching attachments, newest first. With a search
+// term only text attachments containing it match, with a snippet around the match.
+func (r *AttachmentRepository) ListAttachments(ctx context.Context, f AttachmentFilter) ([]models.Attachment, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.OwnerType != "" {
+		add("owner_type = $%d", f.OwnerType)
+	}
+	if f.OwnerID != nil {
+		add("owner_id = $%d", *f.OwnerID)
+	}
+	if f.ProjectID != nil {
+		add("project_id = $%d", *f.ProjectID)
+	}
+	search := strings.TrimSpace(f.Search)
+	if search != "" {
+		// The term is matched literally, so % and _ in it are escaped
+		add(`text_content ILIKE '%%' || $%d || '%%' ESCAPE '\'`, likeEscaper.Replace(search))
+	}
+
+	query := `SELECT ` + attachmentColumns + `, COALESCE(text_content, '') FROM attachments`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		var a models.Attachment
+		var projectID uuid.NullUUID
+		var text string
+		if err := rows.Scan(&a.ID, &a.OwnerType, &a.OwnerID, &projectID, &a.Name, &a.MimeType, &a.Size, &a.SHA256, &a.CreatedAt, &text); err != nil {
+			return nil, err
+		}
+		if projectID.Valid {
+			a.ProjectID = &projectID.UUID
+		}
+		if search != "" {
+			a.Snippet = attach.Snippet(text, search, 80)
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// GetAttachment returns an attachment by ID
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
+	return r.scanOne(r.DB.QueryRowContext(ctx, `SELECT `+attachmentColumns+` FROM attachments WHERE id = $1`, id))
+}
+
+// DeleteAttachment removes an attachment and reports whether other
+// attachments still use its content
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, id uuid.UUID) (shared bool, err error) {
+	var sum string
+	err = r.DB.QueryRowContext(ctx, `DELETE FROM attachments WHERE id = $1 RETURNING sha256`, id).Scan(&sum)
+	if err != nil {
+		return false, err
+	}
+	return r.ContentInUse(ctx, sum)
+}
+
+// ContentInUse reports whether any attachment uses the content with the given SHA-256
+func (r *AttachmentRepository) ContentInUse(ctx context.Context, sum string) (used bool, err error) {
+	err = r.DB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM attachments WHERE sha256 = $1)`, sum).Scan(&used)
+	return used, err
+}
+
+func (r *AttachmentRepository) scanOne(row *sql.Row) (*models.Attachment, error) {
+	var a models.Attachment
+	var projectID uuid.NullUUID
+	if err := row.Scan(&a.ID, &a.OwnerType, &a.OwnerID, &projectID, &a.Name, &a.MimeType, &a.Size, &a.SHA256, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	if projectID.Valid {
+		a.ProjectID = &projectID.UUID
+	}
+	return &a, nil
+}
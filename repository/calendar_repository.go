@@ -0,0 +1,78 @@
+// calendar_repository.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/terzigolu/josepshbrain-go/internal/ical"
+)
+
+// CalendarRepository reads the tasks published in the calendar feed
+type CalendarRepository struct {
+	DB *sql.DB
+}
+
+func NewCalendarRepository(db *sql.DB) *CalendarRepository {
+	return &CalendarRepository{DB: db}
+}
+
+// ListTodos returns the tasks of a project (or all projects) as calendar
+// to-dos, filed under their project and tags. With dueOnly, tasks without a
+// due date are left out.
+func (r *CalendarRepository) ListTodos(ctx context.Context, projectID *uuid.UUID, dueOnly bool) ([]ical.Todo, error) {
+	query := `
+		SELECT t.id, t.description, t.status, t.priority, t.created_at, t.updated_at, t.due_date, t.completed_at,
+			COALESCE(p.name, ''),
+			COALESCE((SELECT string_agg(tg.name, ',' ORDER BY tg.name) FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id WHERE tt.task_id = t.id), '')
+		FROM tasks t
+		LEFT JOIN projects p ON p.id = t.project_id
+		WHERE t.deleted_at IS NULL`
+	var args []interface{}
+	if projectID != nil {
+		args = append(args, *projectID)
+		query += ` AND t.project_id = $1`
+	}
+	if dueOnly {
+		query += ` AND t.due_date IS NOT NULL`
+	}
+	query += ` ORDER BY t.created_at`
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []ical.Todo
+	for rows.Next() {
+		var id uuid.UUID
+		var t ical.Todo
+		var due, completed sql.NullTime
+		var project, tags string
+		if err := rows.Scan(&id, &t.Summary, &t.Status, &t.Priority, &t.Created, &t.Modified, &due, &completed, &project, &tags); err != nil {
+			return nil, err
+		}
+		t.UID = id.String() + "@ramorie"
+		t.Due = nullTime(due)
+		t.Completed = nullTime(completed)
+		if project != "" {
+			t.Categories = append(t.Categories, project)
+		}
+		if tags != "" {
+			t.Categories = append(t.Categories, strings.Split(tags, ",")...)
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}